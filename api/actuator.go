@@ -1,6 +1,9 @@
 package api
 
 import (
+	"net/http"
+	"sync"
+
 	"github.com/angel-one/fd-core/commons/flags"
 	"github.com/angel-one/fd-core/constants"
 	goActuator "github.com/angel-one/go-actuator"
@@ -8,19 +11,24 @@ import (
 )
 
 var (
-	actuatorHandler = goActuator.GetActuatorHandler(&goActuator.Config{
-		Env:     flags.Env(),
-		Name:    constants.ApplicationName,
-		Port:    flags.Port(),
-		Version: constants.V1,
-		Endpoints: []int{
-			goActuator.Info,
-			goActuator.Ping,
-			goActuator.Metrics,
-		},
-	})
+	actuatorOnce    sync.Once
+	actuatorHandler http.HandlerFunc
 )
 
+// Actuator serves the actuator endpoints, built on the first request once the flags are parsed
 func Actuator(ctx *gin.Context) {
+	actuatorOnce.Do(func() {
+		actuatorHandler = goActuator.GetActuatorHandler(&goActuator.Config{
+			Env:     flags.Env(),
+			Name:    constants.ApplicationName,
+			Port:    flags.Port(),
+			Version: constants.V1,
+			Endpoints: []int{
+				goActuator.Info,
+				goActuator.Ping,
+				goActuator.Metrics,
+			},
+		})
+	})
 	actuatorHandler(ctx.Writer, ctx.Request)
 }
@@ -1,10 +1,15 @@
 package events
 
 import (
+	"bytes"
+	gocontext "context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/angel-one/fd-core/business/model"
 	"github.com/angel-one/fd-core/business/service"
+	"github.com/angel-one/fd-core/commons/config"
 	"github.com/angel-one/fd-core/commons/context"
 	"github.com/angel-one/fd-core/commons/errors"
 	"github.com/angel-one/fd-core/commons/log"
@@ -17,32 +22,73 @@ type WebhooksController struct {
 	webhookService service.WebhookService
 }
 
-func DefaultWebhooksController() WebhooksController {
-	return WebhooksController{webhookService: service.DefaultWebhookService()}
+func NewWebhooksController(webhookService service.WebhookService) WebhooksController {
+	return WebhooksController{webhookService: webhookService}
 }
 
-func (c *WebhooksController) ReadUpSwingMessage(gctx *gin.Context) {
+// ReadWebhookMessage queues the delivery of the provider in the path, the WebhookAuth middleware has already
+// authenticated it with the provider's strategy
+func (c *WebhooksController) ReadWebhookMessage(gctx *gin.Context) {
 	ctx := context.Build(gctx)
-	clientCode := context.Get(ctx).UserID
-	if clientCode != constants.UpSwingProvider {
-		errors.Throw(gctx, goerr.New(nil, http.StatusForbidden, "invalid token"))
+	provider := gctx.Param(constants.Provider)
+	if !service.IsWebhookProviderSupported(provider) {
+		errors.Throw(gctx, goerr.New(nil, http.StatusNotFound, fmt.Sprintf("webhooks of provider %s are not supported", provider)))
 		return
 	}
-	log.Debug(ctx).Msg("New upswing webhook event received")
+	log.Debug(ctx).Msgf("New %s webhook event received", provider)
 
-	var request model.UpSwingWebhookEvent
-	if err := gctx.ShouldBind(&request); err != nil {
-		errors.Throw(gctx, err)
+	payload, err := gctx.GetRawData()
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "unable to read webhook payload"))
 		return
 	}
+	if !json.Valid(payload) {
+		errors.Throw(gctx, goerr.New(nil, http.StatusBadRequest, "webhook payload is not a valid json"))
+		return
+	}
+
+	log.Debug(ctx).Msgf("Webhook request payload: %s", string(payload))
 
-	log.Debug(ctx).Msgf("Webhook request payload: %+v", request)
+	if isBatchPayload(payload) {
+		c.readBatch(ctx, gctx, provider, payload)
+		return
+	}
 
-	err := c.webhookService.RegisterNewEvent(ctx, constants.UpSwingProvider, request)
+	// acknowledged once durably queued, the inbox workers register the event
+	err = c.webhookService.EnqueueEvent(ctx, provider, payload)
 	if err != nil {
+		if goerr.Code(err) == 0 {
+			err = goerr.New(err, http.StatusInternalServerError, "unable to queue webhook event")
+		}
 		errors.Throw(gctx, err)
 		return
 	}
 
 	gctx.JSON(http.StatusOK, model.EmptyJSON{})
 }
+
+// readBatch queues every event of an array delivery and responds with the result of each event
+func (c *WebhooksController) readBatch(ctx gocontext.Context, gctx *gin.Context, provider string, payload []byte) {
+	var payloads []json.RawMessage
+	if err := json.Unmarshal(payload, &payloads); err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "webhook payload is not a valid json array"))
+		return
+	}
+	maxBatchSize := int(config.App().Webhooks.MaxBatchSize)
+	if len(payloads) == 0 || len(payloads) > maxBatchSize {
+		errors.Throw(gctx, goerr.New(nil, http.StatusBadRequest, fmt.Sprintf("webhook batch must have 1 to %d events", maxBatchSize)))
+		return
+	}
+
+	response, err := c.webhookService.EnqueueEvents(ctx, provider, payloads)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, "unable to queue webhook events"))
+		return
+	}
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: response})
+}
+
+func isBatchPayload(payload []byte) bool {
+	trimmed := bytes.TrimSpace(payload)
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
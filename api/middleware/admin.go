@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"sync/atomic"
+
+	fderr "github.com/angel-one/fd-core/commons/errors"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/fd-core/errors"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler restricts a route group to the internal users (user_id -> role) replaced at runtime, a request is
+// checked against the users in effect when it came
+type AdminHandler struct {
+	adminUsers atomic.Pointer[map[string]string]
+}
+
+func NewAdminHandler(adminUsers map[string]string) *AdminHandler {
+	h := &AdminHandler{}
+	h.Update(adminUsers)
+	return h
+}
+
+// Update replaces the admin users of the handler
+func (h *AdminHandler) Update(adminUsers map[string]string) {
+	h.adminUsers.Store(&adminUsers)
+}
+
+// Admin restricts a route group to the configured internal users (user_id -> role), the user id is the one verified
+// by Auth. The resolved role is kept on the gin context for the handlers.
+func Admin(adminUsers map[string]string) gin.HandlerFunc {
+	return NewAdminHandler(adminUsers).Handle
+}
+
+func (h *AdminHandler) Handle(gctx *gin.Context) {
+	userID := gctx.GetString(constants.AuthUserIDKey)
+	role, ok := (*h.adminUsers.Load())[userID]
+	if !ok {
+		log.Warn(gctx).Msgf("user %s is not allowed to access admin APIs", userID)
+		fderr.Throw(gctx, errors.AdminAccessDenied)
+		return
+	}
+	gctx.Set(constants.AdminRoleKey, role)
+	gctx.Next()
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminTrustsOnlyTheVerifiedUser(t *testing.T) {
+	signingKey := []byte("s3cret")
+	router := gin.New()
+	router.Use(Auth(signingKey))
+	router.GET("/v1/admin/state", Admin(map[string]string{"A1": "ops"}), func(gctx *gin.Context) {
+		gctx.String(http.StatusOK, context.Get(context.Build(gctx)).UserID)
+	})
+
+	token := func(userID string) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			constants.AuthJWTClaimsUserData: map[string]interface{}{constants.AuthJWTClaimsUserDataUserID: userID},
+		}).SignedString(signingKey)
+		assert.NoError(t, err)
+		return signed
+	}
+
+	tests := []struct {
+		name   string
+		userID string
+		header string
+		want   int
+	}{
+		{"admin user", "A1", "", http.StatusOK},
+		{"spoofed admin header", "C1", "A1", http.StatusForbidden},
+		{"admin with spoofed header", "A1", "C1", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/v1/admin/state", nil)
+			request.Header.Set(constants.HeaderAuthorization, constants.HeaderAuthorizationBearer+" "+token(tt.userID))
+			if tt.header != "" {
+				request.Header.Set(constants.HeaderUserID, tt.header)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.want, recorder.Code)
+			if tt.want == http.StatusOK {
+				assert.Equal(t, tt.userID, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdminHandlerUpdate(t *testing.T) {
+	admin := NewAdminHandler(map[string]string{"A1": "ops"})
+	router := gin.New()
+	router.GET("/v1/admin/state", func(gctx *gin.Context) {
+		gctx.Set(constants.AuthUserIDKey, "A2")
+	}, admin.Handle, func(gctx *gin.Context) {
+		gctx.String(http.StatusOK, gctx.GetString(constants.AdminRoleKey))
+	})
+	serve := func() *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/v1/admin/state", nil))
+		return recorder
+	}
+
+	assert.Equal(t, http.StatusForbidden, serve().Code)
+	admin.Update(map[string]string{"A2": "support"})
+	recorder := serve()
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "support", recorder.Body.String())
+}
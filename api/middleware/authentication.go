@@ -16,8 +16,11 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// Auth verifies the bearer token and keeps its user id on the gin context, the userID header is only ever the
+// verified one since a client sending it could claim any user
 func Auth(signingKey []byte) gin.HandlerFunc {
 	return func(gctx *gin.Context) {
+		gctx.Request.Header.Del(constants.HeaderUserID)
 		if isExcludedPath(gctx) {
 			gctx.Next()
 			return
@@ -73,7 +76,8 @@ func Auth(signingKey []byte) gin.HandlerFunc {
 			return
 		}
 
-		gctx.Request.Header.Add(constants.HeaderUserID, userID)
+		gctx.Request.Header.Set(constants.HeaderUserID, userID)
+		gctx.Set(constants.AuthUserIDKey, userID)
 		gctx.Next()
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"sync/atomic"
 
 	"github.com/angel-one/fd-core/constants"
 	"github.com/gin-gonic/gin"
@@ -59,34 +60,53 @@ func (o *CORSMiddlewareOptions) init() {
 	}
 }
 
+// CORSHandler allows CORS with options replaced at runtime, a request is served with the options in effect when it came
+type CORSHandler struct {
+	options atomic.Pointer[CORSMiddlewareOptions]
+}
+
+func NewCORSHandler(options CORSMiddlewareOptions) *CORSHandler {
+	h := &CORSHandler{}
+	h.Update(options)
+	return h
+}
+
+// Update replaces the options of the handler
+func (h *CORSHandler) Update(options CORSMiddlewareOptions) {
+	options.init()
+	h.options.Store(&options)
+}
+
 // CORS is used to allow CORS for the requests this is added to
 func CORS(options CORSMiddlewareOptions) gin.HandlerFunc {
-	options.init()
-	return func(ctx *gin.Context) {
-		if strings.EqualFold(options.AllowedOrigins, constants.AllowedOriginsForCorsDefault) {
-			ctx.Header(AccessControlAllowOriginHeader, options.AllowedOrigins)
-		} else {
-			requestOrigin := ctx.Request.Header.Get(OriginRequestHeader)
-			if options.originsMap[requestOrigin] {
-				ctx.Header(AccessControlAllowOriginHeader, requestOrigin)
-			} else { //go by regex matches this is costly operation and should be avoided
-				for key := range options.originsMap {
-					match, _ := regexp.MatchString(key, requestOrigin)
-					if match {
-						ctx.Header(AccessControlAllowOriginHeader, requestOrigin)
-						break
-					}
+	return NewCORSHandler(options).Handle
+}
+
+func (h *CORSHandler) Handle(ctx *gin.Context) {
+	options := h.options.Load()
+	if strings.EqualFold(options.AllowedOrigins, constants.AllowedOriginsForCorsDefault) {
+		ctx.Header(AccessControlAllowOriginHeader, options.AllowedOrigins)
+	} else {
+		requestOrigin := ctx.Request.Header.Get(OriginRequestHeader)
+		if options.originsMap[requestOrigin] {
+			ctx.Header(AccessControlAllowOriginHeader, requestOrigin)
+		} else { //go by regex matches this is costly operation and should be avoided
+			for key := range options.originsMap {
+				match, _ := regexp.MatchString(key, requestOrigin)
+				if match {
+					ctx.Header(AccessControlAllowOriginHeader, requestOrigin)
+					break
 				}
 			}
-
 		}
-		ctx.Header(AccessControlAllowCredentialsHeader, options.allowCredentials)
-		ctx.Header(AccessControlAllowHeadersHeader, options.AllowedHeaders)
-		ctx.Header(AccessControlAllowMethodsHeader, options.AllowedMethods)
-		if ctx.Request.Method == http.MethodOptions {
-			ctx.AbortWithStatus(204)
-			return
-		}
-		ctx.Next()
+
+	}
+	ctx.Header(AccessControlAllowCredentialsHeader, options.allowCredentials)
+	ctx.Header(AccessControlAllowHeadersHeader, options.AllowedHeaders)
+	ctx.Header(AccessControlAllowMethodsHeader, options.AllowedMethods)
+	if ctx.Request.Method == http.MethodOptions {
+		ctx.AbortWithStatus(204)
+		return
 	}
+	ctx.Next()
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"sync/atomic"
 
 	"github.com/angel-one/fd-core/commons/log"
 	"github.com/gin-gonic/gin"
@@ -29,38 +30,58 @@ func (o *HeadersMiddlewareOptions) init() {
 	}
 }
 
+// HeaderChecksHandler checks the headers with options replaced at runtime, a request is checked with the options in
+// effect when it came
+type HeaderChecksHandler struct {
+	options atomic.Pointer[HeadersMiddlewareOptions]
+}
+
+func NewHeaderChecksHandler(o HeadersMiddlewareOptions) *HeaderChecksHandler {
+	h := &HeaderChecksHandler{}
+	h.Update(o)
+	return h
+}
+
+// Update replaces the options of the handler
+func (h *HeaderChecksHandler) Update(o HeadersMiddlewareOptions) {
+	o.init()
+	h.options.Store(&o)
+}
+
 // explict checks on headers that ensures , no tampering is attempted
 func HeaderChecks(o HeadersMiddlewareOptions) gin.HandlerFunc {
-	o.init()
-	return func(ctx *gin.Context) {
-		flag := false
-		requestURI := ctx.Request.RequestURI
-		requestHost := ctx.Request.Host
-		url := ctx.Request.RemoteAddr
+	return NewHeaderChecksHandler(o).Handle
+}
 
-		// ignore if the request is for excluded URI
-		for _, v := range o.ExcludedURI {
-			if strings.EqualFold(v, requestURI) {
-				flag = true
-				break
-			}
+func (h *HeaderChecksHandler) Handle(ctx *gin.Context) {
+	o := h.options.Load()
+	flag := false
+	requestURI := ctx.Request.RequestURI
+	requestHost := ctx.Request.Host
+	url := ctx.Request.RemoteAddr
+
+	// ignore if the request is for excluded URI
+	for _, v := range o.ExcludedURI {
+		if strings.EqualFold(v, requestURI) {
+			flag = true
+			break
 		}
+	}
 
-		// if not on excluded list, then check valid host
-		if !flag {
-			for key := range o.originsMap {
-				match, _ := regexp.MatchString(key, requestHost)
-				if match {
-					flag = true
-					break
-				}
+	// if not on excluded list, then check valid host
+	if !flag {
+		for key := range o.originsMap {
+			match, _ := regexp.MatchString(key, requestHost)
+			if match {
+				flag = true
+				break
 			}
 		}
-		if !flag && len(requestHost) > 0 {
-			log.Info(ctx).Msgf("Ignoring request from  Host: %s; URL : %s; URI: %s", requestHost, url, requestURI)
-			ctx.AbortWithStatus(http.StatusPreconditionFailed)
-			return
-		}
-		ctx.Next()
 	}
+	if !flag && len(requestHost) > 0 {
+		log.Info(ctx).Msgf("Ignoring request from  Host: %s; URL : %s; URI: %s", requestHost, url, requestURI)
+		ctx.AbortWithStatus(http.StatusPreconditionFailed)
+		return
+	}
+	ctx.Next()
 }
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadedMiddlewareOptions(t *testing.T) {
+	cors := NewCORSHandler(CORSMiddlewareOptions{AllowedOrigins: "https://www.angelone.in"})
+	headerChecks := NewHeaderChecksHandler(HeadersMiddlewareOptions{WhitelistedHosts: "angelone.in"})
+	router := gin.New()
+	router.Use(cors.Handle, headerChecks.Handle)
+	router.GET("/v1/home", func(gctx *gin.Context) {
+		gctx.Status(http.StatusOK)
+	})
+
+	serve := func(host string, origin string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/v1/home", nil)
+		request.Host = host
+		request.Header.Set(OriginRequestHeader, origin)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := serve("fd.angelone.in", "https://www.angelone.in")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "https://www.angelone.in", recorder.Header().Get(AccessControlAllowOriginHeader))
+	assert.Equal(t, http.StatusPreconditionFailed, serve("fd.example.com", "https://www.angelone.in").Code)
+
+	cors.Update(CORSMiddlewareOptions{AllowedOrigins: "https://fd.example.com"})
+	headerChecks.Update(HeadersMiddlewareOptions{WhitelistedHosts: "angelone.in,example.com"})
+
+	recorder = serve("fd.example.com", "https://www.angelone.in")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get(AccessControlAllowOriginHeader))
+	assert.Equal(t, "https://fd.example.com", serve("fd.example.com", "https://fd.example.com").Header().Get(AccessControlAllowOriginHeader))
+}
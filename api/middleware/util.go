@@ -8,5 +8,7 @@ import (
 )
 
 func isExcludedPath(ctx *gin.Context) bool {
-	return strings.Contains(ctx.FullPath(), constants.ActuatorRoute) || strings.Contains(ctx.FullPath(), constants.SwaggerRoute)
+	// webhooks are authenticated per provider by the WebhookAuth middleware
+	return strings.Contains(ctx.FullPath(), constants.ActuatorRoute) || strings.Contains(ctx.FullPath(), constants.SwaggerRoute) ||
+		strings.HasPrefix(ctx.FullPath(), constants.Webhook+constants.PathSplitter)
 }
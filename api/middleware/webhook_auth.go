@@ -0,0 +1,132 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	fderr "github.com/angel-one/fd-core/commons/errors"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/fd-core/errors"
+	"github.com/angel-one/goerr"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookAuthenticator verifies that a webhook delivery was sent by the provider
+type WebhookAuthenticator interface {
+	Authenticate(gctx *gin.Context, provider string, body []byte) error
+}
+
+// JWTWebhookAuth expects the regular bearer token of a user other than the guest, as Auth does on the other routes. A
+// Subject also requires the token to be issued with it as the user_id.
+type JWTWebhookAuth struct {
+	SigningKey []byte
+	Subject    string
+}
+
+func (a JWTWebhookAuth) Authenticate(gctx *gin.Context, provider string, body []byte) error {
+	token := getTokenFromHeader(gctx.Request.Header.Get(constants.HeaderAuthorization))
+	if token == "" {
+		return errors.HeaderAuthMissingInvalid
+	}
+	claims, err := validateToken(token, a.SigningKey)
+	if err != nil {
+		return goerr.New(err, http.StatusUnauthorized, "invalid webhook token")
+	}
+	userData, ok := claims[constants.AuthJWTClaimsUserData].(map[string]interface{})
+	if !ok {
+		return errors.NotAuthorized
+	}
+	userID, ok := userData[constants.AuthJWTClaimsUserDataUserID].(string)
+	if !ok || userID == "" || userID == constants.AuthGuestUserID {
+		return errors.NotAuthorized
+	}
+	if a.Subject != "" && userID != a.Subject {
+		return goerr.New(nil, http.StatusForbidden, "invalid token")
+	}
+	return nil
+}
+
+// HMACWebhookAuth expects the hex encoded HMAC-SHA256 of the unix seconds of the timestamp header, a dot and the raw
+// body, signed with the provider secret. A delivery whose timestamp is more than MaxSkew off the clock is rejected, so
+// a captured delivery cannot be replayed later.
+type HMACWebhookAuth struct {
+	Secret  []byte
+	MaxSkew time.Duration
+}
+
+func (a HMACWebhookAuth) Authenticate(gctx *gin.Context, provider string, body []byte) error {
+	timestamp := gctx.Request.Header.Get(constants.HeaderWebhookTimestamp)
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return goerr.New(err, http.StatusUnauthorized, "webhook timestamp is invalid/missing")
+	}
+	if skew := time.Since(time.Unix(seconds, 0)); skew > a.MaxSkew || skew < -a.MaxSkew {
+		return goerr.New(nil, http.StatusUnauthorized, "webhook timestamp is outside the allowed skew")
+	}
+	signature, err := hex.DecodeString(gctx.Request.Header.Get(constants.HeaderWebhookSignature))
+	if err != nil || len(signature) == 0 {
+		return goerr.New(err, http.StatusUnauthorized, "webhook signature is invalid/missing")
+	}
+	mac := hmac.New(sha256.New, a.Secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return goerr.New(nil, http.StatusUnauthorized, "webhook signature mismatch")
+	}
+	return nil
+}
+
+// WebhookAuthHandler authenticates the deliveries with strategies replaced at runtime, a delivery is authenticated
+// with the strategies in effect when it came
+type WebhookAuthHandler struct {
+	authenticators atomic.Pointer[map[string]WebhookAuthenticator]
+}
+
+func NewWebhookAuthHandler(authenticators map[string]WebhookAuthenticator) *WebhookAuthHandler {
+	h := &WebhookAuthHandler{}
+	h.Update(authenticators)
+	return h
+}
+
+// Update replaces the strategies of the handler
+func (h *WebhookAuthHandler) Update(authenticators map[string]WebhookAuthenticator) {
+	h.authenticators.Store(&authenticators)
+}
+
+// WebhookAuth authenticates a delivery with the strategy of the provider in the path, providers without a strategy
+// are rejected. The body is restored for the handlers after it is read for the signature check.
+func WebhookAuth(authenticators map[string]WebhookAuthenticator) gin.HandlerFunc {
+	return NewWebhookAuthHandler(authenticators).Handle
+}
+
+func (h *WebhookAuthHandler) Handle(gctx *gin.Context) {
+	provider := gctx.Param(constants.Provider)
+	authenticator, ok := (*h.authenticators.Load())[provider]
+	if !ok {
+		log.Warn(gctx).Msgf("no webhook auth strategy for provider: %s", provider)
+		fderr.Throw(gctx, goerr.New(nil, http.StatusNotFound, "unknown webhook provider"))
+		return
+	}
+
+	body, err := io.ReadAll(gctx.Request.Body)
+	if err != nil {
+		fderr.Throw(gctx, goerr.New(err, http.StatusBadRequest, "unable to read webhook payload"))
+		return
+	}
+	gctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err = authenticator.Authenticate(gctx, provider, body); err != nil {
+		log.Warn(gctx).Err(err).Msgf("webhook authentication failed for provider: %s", provider)
+		fderr.Throw(gctx, err)
+		return
+	}
+	gctx.Next()
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/angel-one/fd-core/constants"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookAuthHMAC(t *testing.T) {
+	secret := []byte("s3cret")
+	body := `{"pci":"S1614297","eventType":"TD_BOOKED"}`
+	sign := func(timestamp string) string {
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(timestamp + "." + body))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-10*time.Minute).Unix(), 10)
+	ahead := strconv.FormatInt(time.Now().Add(10*time.Minute).Unix(), 10)
+	signature := sign(now)
+
+	router := gin.New()
+	router.POST("/webhook/:provider", WebhookAuth(map[string]WebhookAuthenticator{"bank": HMACWebhookAuth{Secret: secret, MaxSkew: 5 * time.Minute}}), func(gctx *gin.Context) {
+		payload, _ := io.ReadAll(gctx.Request.Body)
+		gctx.String(http.StatusOK, string(payload))
+	})
+
+	tests := []struct {
+		name      string
+		provider  string
+		timestamp string
+		signature string
+		want      int
+	}{
+		{"valid signature", "bank", now, signature, http.StatusOK},
+		{"tampered signature", "bank", now, strings.Repeat("0", len(signature)), http.StatusUnauthorized},
+		{"missing signature", "bank", now, "", http.StatusUnauthorized},
+		{"signature of another timestamp", "bank", stale, signature, http.StatusUnauthorized},
+		{"missing timestamp", "bank", "", signature, http.StatusUnauthorized},
+		{"stale timestamp", "bank", stale, sign(stale), http.StatusUnauthorized},
+		{"timestamp ahead of the clock", "bank", ahead, sign(ahead), http.StatusUnauthorized},
+		{"unknown provider", "other", now, signature, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/webhook/"+tt.provider, strings.NewReader(body))
+			request.Header.Set(constants.HeaderWebhookTimestamp, tt.timestamp)
+			request.Header.Set(constants.HeaderWebhookSignature, tt.signature)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.want, recorder.Code)
+			if tt.want == http.StatusOK {
+				assert.Equal(t, body, recorder.Body.String(), "body must be restored for the handler")
+			}
+		})
+	}
+}
+
+func TestWebhookAuthJWT(t *testing.T) {
+	signingKey := []byte("s3cret")
+	router := gin.New()
+	router.POST("/webhook/:provider", WebhookAuth(map[string]WebhookAuthenticator{
+		"upswing": JWTWebhookAuth{SigningKey: signingKey},
+		"bank":    JWTWebhookAuth{SigningKey: signingKey, Subject: "bank"},
+	}), func(gctx *gin.Context) {
+		gctx.Status(http.StatusOK)
+	})
+
+	token := func(key []byte, userID string) string {
+		signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			constants.AuthJWTClaimsUserData: map[string]interface{}{constants.AuthJWTClaimsUserDataUserID: userID},
+		}).SignedString(key)
+		assert.NoError(t, err)
+		return constants.HeaderAuthorizationBearer + " " + signed
+	}
+
+	tests := []struct {
+		name     string
+		provider string
+		header   string
+		want     int
+	}{
+		{"any user without a subject", "upswing", token(signingKey, "C1"), http.StatusOK},
+		{"guest user", "upswing", token(signingKey, constants.AuthGuestUserID), http.StatusUnauthorized},
+		{"empty user", "upswing", token(signingKey, ""), http.StatusUnauthorized},
+		{"token of another key", "upswing", token([]byte("other"), "C1"), http.StatusUnauthorized},
+		{"missing token", "upswing", "", http.StatusBadRequest},
+		{"subject user", "bank", token(signingKey, "bank"), http.StatusOK},
+		{"user other than the subject", "bank", token(signingKey, "C1"), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/webhook/"+tt.provider, strings.NewReader(`{}`))
+			if tt.header != "" {
+				request.Header.Set(constants.HeaderAuthorization, tt.header)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.want, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	v1 "github.com/angel-one/fd-core/api/v1"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/gin-gonic/gin"
+)
+
+func InitAdminRoute(adminController v1.AdminController, adminAuth gin.HandlerFunc, vGroups ...*gin.RouterGroup) {
+	initAdminV1Group(vGroups[0], adminController, adminAuth)
+}
+
+func initAdminV1Group(v1Group *gin.RouterGroup, adminController v1.AdminController, adminAuth gin.HandlerFunc) {
+
+	admin := v1Group.Group(constants.Admin, adminAuth)
+	{
+		admin.POST(constants.Webhooks+constants.Rederive+constants.PathParam+constants.Provider, adminController.RederiveWebhookEvents)
+		admin.POST(constants.Webhooks+constants.Replay, adminController.ReplayWebhookEvents)
+		admin.GET(constants.Webhooks+constants.DeadLetters, adminController.GetWebhookDeadLetters)
+		admin.POST(constants.Webhooks+constants.DeadLetters+constants.PathParam+constants.DeadLetterID+constants.Requeue, adminController.RequeueWebhookDeadLetter)
+		admin.GET(constants.Clients+constants.PathParam+constants.ClientCode+constants.State, adminController.GetClientState)
+		admin.PUT(constants.Clients+constants.PathParam+constants.ClientCode+constants.Invalid, adminController.SetInvalidClient)
+		admin.GET(constants.Clients+constants.PathParam+constants.ClientCode+constants.Invalid+constants.Audit, adminController.GetInvalidClientAudit)
+		admin.GET(constants.Reconciliation+constants.Summary, adminController.GetReconciliationSummary)
+		admin.GET(constants.Jobs+constants.Config, adminController.GetJobConfigs)
+		admin.PUT(constants.Jobs+constants.Config+constants.PathParam+constants.JobName, adminController.SaveJobConfig)
+		admin.GET(constants.Config, adminController.GetConfig)
+	}
+}
@@ -6,12 +6,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func InitComparePageRoute(vGroups ...*gin.RouterGroup) {
-	initComparePageV1Group(vGroups[0])
+func InitComparePageRoute(compareController v1.CompareController, vGroups ...*gin.RouterGroup) {
+	initComparePageV1Group(vGroups[0], compareController)
 }
 
-func initComparePageV1Group(v1Group *gin.RouterGroup) {
-	compareController := v1.DefaultCompareController()
+func initComparePageV1Group(v1Group *gin.RouterGroup, compareController v1.CompareController) {
 	compare := v1Group.Group(constants.Compare)
 	{
 		compare.GET("", compareController.GetCompareDetails)
@@ -6,12 +6,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func initFAQ(vGroups ...*gin.RouterGroup) {
-	initFAQV1Group(vGroups[0])
+func initFAQ(faqController v1.FAQController, vGroups ...*gin.RouterGroup) {
+	initFAQV1Group(vGroups[0], faqController)
 }
 
-func initFAQV1Group(v1Group *gin.RouterGroup) {
-	faqController := v1.DefaultFAQController()
+func initFAQV1Group(v1Group *gin.RouterGroup, faqController v1.FAQController) {
 	faqs := v1Group.Group(constants.FAQ)
 	{
 		faqs.GET(constants.PathParam+constants.Tag, faqController.GetFAQs)
@@ -6,12 +6,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func InitHomepageRoute(vGroups ...*gin.RouterGroup) {
-	initHomepageV1Group(vGroups[0])
+func InitHomepageRoute(homeController v1.HomepageController, vGroups ...*gin.RouterGroup) {
+	initHomepageV1Group(vGroups[0], homeController)
 }
 
-func initHomepageV1Group(v1Group *gin.RouterGroup) {
-	homeController := v1.DefaultHomepageController()
+func initHomepageV1Group(v1Group *gin.RouterGroup, homeController v1.HomepageController) {
 	plans := v1Group.Group(constants.Home)
 	{
 		plans.GET("", homeController.GetHomepage)
@@ -6,16 +6,22 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func InitJobsRoute(vGroups ...*gin.RouterGroup) {
-	initJobsV1Group(vGroups[0])
+func InitJobsRoute(jobsController v1.JobsController, adminAuth gin.HandlerFunc, vGroups ...*gin.RouterGroup) {
+	initJobsV1Group(vGroups[0], jobsController, adminAuth)
 }
 
-func initJobsV1Group(v1Group *gin.RouterGroup) {
-	jobsController := v1.DefaultJobsController()
+// the runs are started and cancelled by the admins only, a run sweeps every client against the rate limited provider.
+// The deprecated update routes start a run too.
+func initJobsV1Group(v1Group *gin.RouterGroup, jobsController v1.JobsController, adminAuth gin.HandlerFunc) {
 
 	jobs := v1Group.Group(constants.Jobs)
 	{
-		jobs.GET(constants.PathSplitter+constants.Update+constants.Portfolio+constants.PathParam+constants.Refresher, jobsController.GetPortfolioJob)
-		jobs.GET(constants.PathSplitter+constants.Update+constants.PendingJourney+constants.PathParam+constants.Refresher, jobsController.GetPendingJourneyJob)
+		jobs.GET(constants.PathSplitter+constants.Update+constants.Portfolio+constants.PathParam+constants.Refresher, adminAuth, jobsController.GetPortfolioJob)
+		jobs.GET(constants.PathSplitter+constants.Update+constants.PendingJourney+constants.PathParam+constants.Refresher, adminAuth, jobsController.GetPendingJourneyJob)
+		jobs.GET(constants.Leases, jobsController.GetJobLeases)
+		jobs.GET(constants.Runs, jobsController.GetJobRuns)
+		jobs.GET(constants.Runs+constants.PathParam+constants.RunID, jobsController.GetJobRun)
+		jobs.POST(constants.Runs+constants.PathParam+constants.RunID+constants.Cancel, adminAuth, jobsController.CancelJobRun)
+		jobs.POST(constants.PathParam+constants.JobName+constants.Runs, adminAuth, jobsController.TriggerJobRun)
 	}
 }
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	v1 "github.com/angel-one/fd-core/api/v1"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/gin-gonic/gin"
+)
+
+func InitJourneyRoute(journeyController v1.JourneyController, vGroups ...*gin.RouterGroup) {
+	initJourneyV1Group(vGroups[0], journeyController)
+}
+
+func initJourneyV1Group(v1Group *gin.RouterGroup, journeyController v1.JourneyController) {
+
+	journeys := v1Group.Group(constants.Journeys)
+	{
+		journeys.GET("", journeyController.GetJourneys)
+		journeys.GET(constants.PathParam+constants.JourneyID+constants.Timeline, journeyController.GetJourneyTimeline)
+	}
+}
@@ -6,12 +6,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func InitPlansRoute(vGroups ...*gin.RouterGroup) {
-	initPlansV1Group(vGroups[0])
+func InitPlansRoute(plansController v1.PlansController, vGroups ...*gin.RouterGroup) {
+	initPlansV1Group(vGroups[0], plansController)
 }
 
-func initPlansV1Group(v1Group *gin.RouterGroup) {
-	plansController := v1.DefaultPlansController()
+func initPlansV1Group(v1Group *gin.RouterGroup, plansController v1.PlansController) {
 
 	plans := v1Group.Group(constants.Plans)
 	{
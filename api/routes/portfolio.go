@@ -6,12 +6,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func InitPortfolioRoute(vGroups ...*gin.RouterGroup) {
-	initPortfolioV1Group(vGroups[0])
+func InitPortfolioRoute(portfolioController v1.PortfolioController, vGroups ...*gin.RouterGroup) {
+	initPortfolioV1Group(vGroups[0], portfolioController)
 }
 
-func initPortfolioV1Group(v1Group *gin.RouterGroup) {
-	portfolioController := v1.DefaultPortfolioController()
+func initPortfolioV1Group(v1Group *gin.RouterGroup, portfolioController v1.PortfolioController) {
 
 	portfolio := v1Group.Group(constants.Portfolio)
 	{
@@ -5,11 +5,12 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/angel-one/fd-core/api/events"
 	"github.com/angel-one/fd-core/api/middleware"
+	v1 "github.com/angel-one/fd-core/api/v1"
 	"github.com/angel-one/fd-core/commons/config"
 
 	"github.com/angel-one/fd-core/api"
-	"github.com/angel-one/fd-core/commons/log"
 	"github.com/angel-one/fd-core/constants"
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
@@ -27,22 +28,64 @@ func createRouter(middlewares ...gin.HandlerFunc) *gin.Engine {
 	return router
 }
 
-func DefaultRouter(ctx context.Context) *gin.Engine {
-	authKey := config.Default().Secrets[constants.JWTSymmetricKey]
-	allowedOrigins := config.Default().GetStringD(constants.ApplicationConfig, constants.AllowedOrginsForCorsConfigKey, constants.AllowedOriginsForCorsDefault)
-	if allowedOrigins == "" {
-		log.Fatal(ctx).Err(errors.New(constants.WhitelistedHostIsNotSet)).Msg(constants.WhitelistedHostIsNotSet)
+// Controllers handle the routes, the application builds them with their services and tests with fakes
+type Controllers struct {
+	Homepage  v1.HomepageController
+	Token     v1.TokenController
+	Portfolio v1.PortfolioController
+	Plans     v1.PlansController
+	FAQ       v1.FAQController
+	Compare   v1.CompareController
+	Journey   v1.JourneyController
+	Jobs      v1.JobsController
+	Admin     v1.AdminController
+	Webhooks  events.WebhooksController
+}
+
+// RouterOptions configure the middlewares of the router
+type RouterOptions struct {
+	AuthKey               []byte
+	AllowedOrigins        string
+	WhitelistedHosts      string
+	AdminUsers            map[string]string
+	WebhookAuthenticators map[string]middleware.WebhookAuthenticator
+	// FollowConfig applies the reloaded origins, whitelisted hosts, admin users and webhook auth strategies to the
+	// middlewares, the strategies are rebuilt with the secrets of Config
+	FollowConfig bool
+	Config       *config.Client
+}
+
+// NewRouterOptions reads the router options from the application config and the secrets
+func NewRouterOptions(cfg *config.Client, settings *config.Application) (RouterOptions, error) {
+	if settings.OriginsAllowedForCors == "" {
+		return RouterOptions{}, errors.New(constants.AllowedOriginsIsNotSet)
 	}
-	whitelistedHeaderHosts := config.Default().GetStringD(constants.ApplicationConfig, constants.WhitelistedHostsHeader, constants.WhitelistedHostsHeaderDefault)
-	if allowedOrigins == "" {
-		log.Fatal(ctx).Err(errors.New(constants.WhitelistedHostIsNotSet)).Msg(constants.WhitelistedHostIsNotSet)
+	if settings.WhitelistedHostHeader == "" {
+		return RouterOptions{}, errors.New(constants.WhitelistedHostIsNotSet)
 	}
+	return RouterOptions{AuthKey: []byte(cfg.Secret(constants.JWTSymmetricKey)), AllowedOrigins: settings.OriginsAllowedForCors,
+		WhitelistedHosts: settings.WhitelistedHostHeader, AdminUsers: settings.AdminUsers, WebhookAuthenticators: webhookAuthenticators(cfg, settings),
+		FollowConfig: true, Config: cfg}, nil
+}
 
+func NewRouter(options RouterOptions, controllers Controllers) *gin.Engine {
+	cors := middleware.NewCORSHandler(corsOptions(options.AllowedOrigins))
+	headerChecks := middleware.NewHeaderChecksHandler(headerChecksOptions(options.WhitelistedHosts))
+	admin := middleware.NewAdminHandler(options.AdminUsers)
+	webhookAuth := middleware.NewWebhookAuthHandler(options.WebhookAuthenticators)
+	if options.FollowConfig {
+		config.Subscribe(func(ctx context.Context, settings *config.Settings) {
+			cors.Update(corsOptions(settings.Application.OriginsAllowedForCors))
+			headerChecks.Update(headerChecksOptions(settings.Application.WhitelistedHostHeader))
+			admin.Update(settings.Application.AdminUsers)
+			webhookAuth.Update(webhookAuthenticators(options.Config, &settings.Application))
+		})
+	}
 	router := createRouter(
-		middleware.CORS(middleware.CORSMiddlewareOptions{AllowedMethods: constants.AllowedMethodsForCors, AllowedOrigins: allowedOrigins}),
+		cors.Handle,
 		middleware.SecurityHeader(),
-		middleware.HeaderChecks(middleware.HeadersMiddlewareOptions{WhitelistedHosts: whitelistedHeaderHosts, ExcludedURI: []string{constants.ActuatorInfo, constants.SwaggerRoute}}),
-		middleware.Auth([]byte(authKey)),
+		headerChecks.Handle,
+		middleware.Auth(options.AuthKey),
 		middleware.Logger(),
 	)
 
@@ -51,19 +94,21 @@ func DefaultRouter(ctx context.Context) *gin.Engine {
 	router.GET(constants.ActuatorRoute, api.Actuator)
 
 	// dedicate group for vendors webhooks
-	InitWebhookRoutes(router.Group(constants.Webhook))
+	InitWebhookRoutes(controllers.Webhooks, webhookAuth.Handle, router.Group(constants.Webhook))
 
 	// dedicate group for all version1 APIs
 	v1Group := router.Group(constants.V1)
 
 	// init all routes
-	InitHomepageRoute(v1Group)
-	InitTokenRoute(v1Group)
-	InitPortfolioRoute(v1Group)
-	initPlansV1Group(v1Group)
-	initFAQ(v1Group)
-	InitComparePageRoute(v1Group)
-	InitJobsRoute(v1Group)
+	InitHomepageRoute(controllers.Homepage, v1Group)
+	InitTokenRoute(controllers.Token, v1Group)
+	InitPortfolioRoute(controllers.Portfolio, v1Group)
+	initPlansV1Group(v1Group, controllers.Plans)
+	initFAQ(controllers.FAQ, v1Group)
+	InitComparePageRoute(controllers.Compare, v1Group)
+	InitJourneyRoute(controllers.Journey, v1Group)
+	InitJobsRoute(controllers.Jobs, admin.Handle, v1Group)
+	InitAdminRoute(controllers.Admin, admin.Handle, v1Group)
 
 	// init invalid routes
 	initNoRoute(router)
@@ -71,6 +116,14 @@ func DefaultRouter(ctx context.Context) *gin.Engine {
 	return router
 }
 
+func corsOptions(allowedOrigins string) middleware.CORSMiddlewareOptions {
+	return middleware.CORSMiddlewareOptions{AllowedMethods: constants.AllowedMethodsForCors, AllowedOrigins: allowedOrigins}
+}
+
+func headerChecksOptions(whitelistedHosts string) middleware.HeadersMiddlewareOptions {
+	return middleware.HeadersMiddlewareOptions{WhitelistedHosts: whitelistedHosts, ExcludedURI: []string{constants.ActuatorInfo, constants.SwaggerRoute}}
+}
+
 func initNoRoute(router *gin.Engine) {
 	router.NoRoute(handleUnknown)
 }
@@ -0,0 +1,178 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	v1 "github.com/angel-one/fd-core/api/v1"
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeHomepageService struct {
+	clientCode string
+}
+
+func (f *fakeHomepageService) GetHomePageDetails(ctx context.Context, clientCode string, provider string) (model.Homepage, error) {
+	f.clientCode = clientCode
+	return model.Homepage{Journey: model.Journey{Pending: true}}, nil
+}
+
+type fakeJobsService struct {
+	filter model.JobRunFilter
+}
+
+func (f *fakeJobsService) TriggerJob(ctx context.Context, name string, request model.JobTriggerRequest, requestedBy string) (model.JobRun, error) {
+	return model.JobRun{}, nil
+}
+
+func (f *fakeJobsService) GetRun(ctx context.Context, id int64) (*model.JobRun, error) {
+	return nil, nil
+}
+
+func (f *fakeJobsService) CancelRun(ctx context.Context, id int64) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeJobsService) GetLeases(ctx context.Context) (model.JobLeases, error) {
+	return model.JobLeases{}, nil
+}
+
+func (f *fakeJobsService) GetRuns(ctx context.Context, filter model.JobRunFilter) (model.JobRuns, error) {
+	f.filter = filter
+	return model.JobRuns{Runs: []model.JobRun{{ID: 7, Job: filter.JobName}}}, nil
+}
+
+func TestNewRouter(t *testing.T) {
+	signingKey := []byte("s3cret")
+	homepageService := &fakeHomepageService{}
+	router := NewRouter(RouterOptions{AuthKey: signingKey, AllowedOrigins: "localhost", WhitelistedHosts: "example.com"},
+		Controllers{Homepage: v1.HomepageController{HomepageService: homepageService}})
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		constants.AuthJWTClaimsUserData: map[string]interface{}{constants.AuthJWTClaimsUserDataUserID: "S1614297"},
+	}).SignedString(signingKey)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		path  string
+		token string
+		want  int
+	}{
+		{"authorized", constants.V1 + constants.Home, token, http.StatusOK},
+		{"missing token", constants.V1 + constants.Home, "", http.StatusBadRequest},
+		{"unknown route", constants.V1 + "/unknown", token, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				request.Header.Set(constants.HeaderAuthorization, constants.HeaderAuthorizationBearer+" "+tt.token)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.want, recorder.Code)
+		})
+	}
+	assert.Equal(t, "S1614297", homepageService.clientCode)
+}
+
+func TestConfigIsServedToAdminsOnly(t *testing.T) {
+	signingKey := []byte("s3cret")
+	router := NewRouter(RouterOptions{AuthKey: signingKey, AllowedOrigins: "localhost", WhitelistedHosts: "example.com", AdminUsers: map[string]string{"A1": "ops"}},
+		Controllers{})
+
+	for userID, want := range map[string]int{"A1": http.StatusOK, "S1614297": http.StatusForbidden} {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			constants.AuthJWTClaimsUserData: map[string]interface{}{constants.AuthJWTClaimsUserDataUserID: userID},
+		}).SignedString(signingKey)
+		assert.NoError(t, err)
+		request := httptest.NewRequest(http.MethodGet, constants.V1+constants.Admin+constants.Config, nil)
+		request.Header.Set(constants.HeaderAuthorization, constants.HeaderAuthorizationBearer+" "+token)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, want, recorder.Code, userID)
+	}
+}
+
+func TestJobRunsAreStartedAndCancelledByAdminsOnly(t *testing.T) {
+	signingKey := []byte("s3cret")
+	router := NewRouter(RouterOptions{AuthKey: signingKey, AllowedOrigins: "localhost", WhitelistedHosts: "example.com", AdminUsers: map[string]string{"A1": "ops"}},
+		Controllers{})
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		constants.AuthJWTClaimsUserData: map[string]interface{}{constants.AuthJWTClaimsUserDataUserID: "S1614297"},
+	}).SignedString(signingKey)
+	assert.NoError(t, err)
+
+	routes := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodPost, constants.V1 + constants.Jobs + "/portfolioUpdateCron" + constants.Runs},
+		{http.MethodPost, constants.V1 + constants.Jobs + constants.Runs + "/1" + constants.Cancel},
+		{http.MethodGet, constants.V1 + constants.Jobs + constants.Update + constants.Portfolio + "/instant"},
+		{http.MethodGet, constants.V1 + constants.Jobs + constants.Update + constants.PendingJourney + "/instant"},
+	}
+	for _, route := range routes {
+		request := httptest.NewRequest(route.method, route.path, nil)
+		request.Header.Set(constants.HeaderAuthorization, constants.HeaderAuthorizationBearer+" "+token)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusForbidden, recorder.Code, route.path)
+	}
+}
+
+func TestGetJobRuns(t *testing.T) {
+	signingKey := []byte("s3cret")
+	jobsService := &fakeJobsService{}
+	router := NewRouter(RouterOptions{AuthKey: signingKey, AllowedOrigins: "localhost", WhitelistedHosts: "example.com"},
+		Controllers{Jobs: v1.JobsController{JobsService: jobsService}})
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		constants.AuthJWTClaimsUserData: map[string]interface{}{constants.AuthJWTClaimsUserDataUserID: "A1"},
+	}).SignedString(signingKey)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"filtered", "?job=portfolioUpdateCron&status=failed&trigger=cron&from=2026-10-19T00:00:00Z&limit=10", http.StatusOK},
+		{"invalid limit", "?limit=0", http.StatusBadRequest},
+		{"invalid from", "?from=yesterday", http.StatusBadRequest},
+		{"invalid to", "?to=2026-10-19", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, constants.V1+constants.Jobs+constants.Runs+tt.query, nil)
+			request.Header.Set(constants.HeaderAuthorization, constants.HeaderAuthorizationBearer+" "+token)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.want, recorder.Code)
+		})
+	}
+
+	from := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, model.JobRunFilter{JobName: "portfolioUpdateCron", Status: "failed", Trigger: "cron", From: &from, Limit: 10}, jobsService.filter)
+}
+
+func TestNewRouterOptions(t *testing.T) {
+	cfg := &config.Client{Secrets: map[string]string{constants.JWTSymmetricKey: "s3cret"}}
+
+	options, err := NewRouterOptions(cfg, &config.Application{OriginsAllowedForCors: "*", WhitelistedHostHeader: "localhost", AdminUsers: map[string]string{"A1": "ops"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("s3cret"), options.AuthKey)
+	assert.Equal(t, map[string]string{"A1": "ops"}, options.AdminUsers)
+
+	_, err = NewRouterOptions(cfg, &config.Application{WhitelistedHostHeader: "localhost"})
+	assert.EqualError(t, err, constants.AllowedOriginsIsNotSet)
+	_, err = NewRouterOptions(cfg, &config.Application{OriginsAllowedForCors: "*"})
+	assert.EqualError(t, err, constants.WhitelistedHostIsNotSet)
+}
@@ -6,12 +6,11 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func InitTokenRoute(vGroups ...*gin.RouterGroup) {
-	initTokenV1Group(vGroups[0])
+func InitTokenRoute(tokenController v1.TokenController, vGroups ...*gin.RouterGroup) {
+	initTokenV1Group(vGroups[0], tokenController)
 }
 
-func initTokenV1Group(v1Group *gin.RouterGroup) {
-	tokenController := v1.DefaultTokenController()
+func initTokenV1Group(v1Group *gin.RouterGroup, tokenController v1.TokenController) {
 
 	token := v1Group.Group(constants.Token)
 	{
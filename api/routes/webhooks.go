@@ -1,16 +1,49 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/angel-one/fd-core/api/events"
+	"github.com/angel-one/fd-core/api/middleware"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/commons/log"
 	"github.com/angel-one/fd-core/constants"
 	"github.com/gin-gonic/gin"
 )
 
-func InitWebhookRoutes(vGroups ...*gin.RouterGroup) {
-	initWebhookRoutes(vGroups[0])
+func InitWebhookRoutes(controller events.WebhooksController, webhookAuth gin.HandlerFunc, vGroups ...*gin.RouterGroup) {
+	initWebhookRoutes(vGroups[0], controller, webhookAuth)
+}
+
+func initWebhookRoutes(group *gin.RouterGroup, controller events.WebhooksController, webhookAuth gin.HandlerFunc) {
+	group.POST(constants.PathParam+constants.Provider, webhookAuth, controller.ReadWebhookMessage)
+
+	// path registered with upswing before the generic route, kept as an alias
+	group.POST(constants.PathSplitter+constants.UpSwingProvider+constants.PathSplitter+constants.UpSwingWebhookPath, func(gctx *gin.Context) {
+		gctx.AddParam(constants.Provider, constants.UpSwingProvider)
+	}, webhookAuth, controller.ReadWebhookMessage)
 }
 
-func initWebhookRoutes(group *gin.RouterGroup) {
-	controller := events.DefaultWebhooksController()
-	group.POST(constants.PathSplitter+constants.UpSwingProvider+constants.PathSplitter+constants.UpSwingWebhookPath, controller.ReadUpSwingMessage)
+// webhookAuthenticators builds the configured auth strategy of every provider
+func webhookAuthenticators(cfg *config.Client, settings *config.Application) map[string]middleware.WebhookAuthenticator {
+	ctx := context.Background("webhooks")
+	authenticators := make(map[string]middleware.WebhookAuthenticator)
+	strategies := settings.Webhooks.Auth
+	for provider, strategy := range strategies {
+		switch strategy {
+		case constants.WebhookAuthJWT:
+			authenticators[provider] = middleware.JWTWebhookAuth{SigningKey: []byte(cfg.Secret(constants.JWTSymmetricKey)), Subject: settings.Webhooks.JWTSubjects[provider]}
+		case constants.WebhookAuthHMAC:
+			secret := cfg.Secret(provider + constants.WebhookSecretSuffix)
+			if secret == "" {
+				log.Error(ctx).Msgf("webhook signing secret is not set for provider: %s, its webhooks are rejected", provider)
+				continue
+			}
+			authenticators[provider] = middleware.HMACWebhookAuth{Secret: []byte(secret), MaxSkew: time.Duration(settings.Webhooks.SignatureMaxSkewSeconds) * time.Second}
+		default:
+			log.Error(ctx).Msgf("unknown webhook auth strategy %s for provider: %s, its webhooks are rejected", strategy, provider)
+		}
+	}
+	return authenticators
 }
@@ -0,0 +1,280 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/service"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/commons/errors"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/goerr"
+	"github.com/gin-gonic/gin"
+)
+
+type AdminController struct {
+	WebhookService        service.WebhookService
+	ReplayService         service.ReplayService
+	WebhookInboxService   service.WebhookInboxService
+	ClientStateService    service.ClientStateService
+	ReconciliationService service.ReconciliationService
+	JobConfigService      service.JobConfigService
+	InvalidClientService  service.InvalidClientService
+}
+
+func NewAdminController(webhookService service.WebhookService, replayService service.ReplayService, webhookInboxService service.WebhookInboxService,
+	clientStateService service.ClientStateService, reconciliationService service.ReconciliationService, jobConfigService service.JobConfigService,
+	invalidClientService service.InvalidClientService) AdminController {
+	return AdminController{WebhookService: webhookService, ReplayService: replayService, WebhookInboxService: webhookInboxService,
+		ClientStateService: clientStateService, ReconciliationService: reconciliationService, JobConfigService: jobConfigService,
+		InvalidClientService: invalidClientService}
+}
+
+// Swagger not required as this is internal engg API
+func (a *AdminController) RederiveWebhookEvents(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+	userID := context.Get(ctx).UserID
+	provider := gctx.Param(constants.Provider)
+	log.Info(ctx).Msgf("UserID: %s; re-deriving webhook events for provider: %s", userID, provider)
+
+	if !slices.Contains(constants.KnownProviders, provider) {
+		msg := fmt.Sprintf("Provider %s not supported", provider)
+		errors.Throw(gctx, goerr.New(nil, http.StatusForbidden, msg))
+		return
+	}
+
+	updated, err := a.WebhookService.RederiveEvents(ctx, provider)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, "unable to re-derive webhook events"))
+		return
+	}
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: model.RederiveResult{Provider: provider, EventsUpdated: updated}})
+}
+
+// Swagger not required as this is internal engg API
+func (a *AdminController) ReplayWebhookEvents(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+	userID := context.Get(ctx).UserID
+
+	var request model.ReplayRequest
+	if err := gctx.ShouldBindJSON(&request); err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "invalid replay request"))
+		return
+	}
+	if request.Provider == "" {
+		request.Provider = constants.UpSwingProvider
+	}
+	log.Info(ctx).Msgf("UserID: %s; replaying webhook events: %+v", userID, request)
+
+	if !slices.Contains(constants.KnownProviders, request.Provider) {
+		msg := fmt.Sprintf("Provider %s not supported", request.Provider)
+		errors.Throw(gctx, goerr.New(nil, http.StatusForbidden, msg))
+		return
+	}
+
+	response, err := a.ReplayService.Replay(ctx, request)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, "unable to replay webhook events"))
+		return
+	}
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: response})
+}
+
+// Swagger not required as this is internal engg API
+func (a *AdminController) GetWebhookDeadLetters(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+	userID := context.Get(ctx).UserID
+	provider := gctx.DefaultQuery(constants.Provider, constants.UpSwingProvider)
+	includeRequeued, _ := strconv.ParseBool(gctx.DefaultQuery(constants.IncludeRequeued, "false"))
+	limit, err := strconv.Atoi(gctx.DefaultQuery(constants.Limit, "50"))
+	if err != nil || limit <= 0 {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "limit must be a positive number"))
+		return
+	}
+	log.Info(ctx).Msgf("UserID: %s; fetching webhook dead letters for provider: %s", userID, provider)
+
+	response, err := a.WebhookInboxService.FetchDeadLetters(ctx, provider, includeRequeued, limit)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, "unable to fetch webhook dead letters"))
+		return
+	}
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: response})
+}
+
+// Swagger not required as this is internal engg API
+func (a *AdminController) RequeueWebhookDeadLetter(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+	userID := context.Get(ctx).UserID
+	id, err := strconv.ParseInt(gctx.Param(constants.DeadLetterID), 10, 64)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "invalid dead letter id"))
+		return
+	}
+	log.Info(ctx).Msgf("UserID: %s; requeueing webhook dead letter: %d", userID, id)
+
+	requeued, err := a.WebhookInboxService.RequeueDeadLetter(ctx, id, userID)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, "unable to requeue webhook dead letter"))
+		return
+	}
+	if !requeued {
+		errors.Throw(gctx, goerr.New(nil, http.StatusNotFound, fmt.Sprintf("dead letter %d not found or already requeued", id)))
+		return
+	}
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: model.RequeueResult{DeadLetterID: id, Requeued: requeued}})
+}
+
+// Swagger not required as this is internal engg API
+func (a *AdminController) GetClientState(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+	userID := context.Get(ctx).UserID
+	clientCode := gctx.Param(constants.ClientCode)
+	provider := gctx.DefaultQuery(constants.Provider, constants.UpSwingProvider)
+	events, err := strconv.Atoi(gctx.DefaultQuery(constants.Events, "20"))
+	if err != nil || events <= 0 {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "events must be a positive number"))
+		return
+	}
+	if !slices.Contains(constants.KnownProviders, provider) {
+		msg := fmt.Sprintf("Provider %s not supported", provider)
+		errors.Throw(gctx, goerr.New(nil, http.StatusForbidden, msg))
+		return
+	}
+
+	// PII is shown only to the configured roles
+	role := gctx.GetString(constants.AdminRoleKey)
+	unmaskedRoles := config.App().AdminUnmaskedRoles
+	masked := !slices.Contains(unmaskedRoles, role)
+	log.Info(ctx).Msgf("UserID: %s; role: %s; fetching client state for provider: %s; masked: %t", userID, role, provider, masked)
+
+	response, err := a.ClientStateService.GetClientState(ctx, provider, clientCode, events, masked)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, "unable to fetch client state"))
+		return
+	}
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: response})
+}
+
+// Swagger not required as this is internal engg API
+func (a *AdminController) GetReconciliationSummary(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+	userID := context.Get(ctx).UserID
+	provider := gctx.DefaultQuery(constants.Provider, constants.UpSwingProvider)
+	limit, err := strconv.Atoi(gctx.DefaultQuery(constants.Limit, "20"))
+	if err != nil || limit <= 0 {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "limit must be a positive number"))
+		return
+	}
+	log.Info(ctx).Msgf("UserID: %s; fetching reconciliation summary for provider: %s", userID, provider)
+
+	response, err := a.ReconciliationService.GetSummary(ctx, provider, limit)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, "unable to fetch reconciliation summary"))
+		return
+	}
+	if response == nil {
+		errors.Throw(gctx, goerr.New(nil, http.StatusNotFound, fmt.Sprintf("no reconciliation run found for provider %s", provider)))
+		return
+	}
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: response})
+}
+
+// Swagger not required as this is internal engg API
+func (a *AdminController) GetJobConfigs(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+
+	response, err := a.JobConfigService.GetConfigs(ctx)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, "unable to fetch job configs"))
+		return
+	}
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: response})
+}
+
+// GetConfig serves the config in effect with the secrets redacted
+// Swagger not required as this is internal engg API
+func (a *AdminController) GetConfig(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+	log.Info(ctx).Msgf("UserID: %s; fetching the config in effect", context.Get(ctx).UserID)
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: config.View()})
+}
+
+// Swagger not required as this is internal engg API
+func (a *AdminController) SaveJobConfig(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+	userID := context.Get(ctx).UserID
+	name := gctx.Param(constants.JobName)
+
+	var request model.JobConfigRequest
+	if err := gctx.ShouldBindJSON(&request); err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "invalid job config request"))
+		return
+	}
+	log.Info(ctx).Msgf("UserID: %s; saving config of job %s: %+v", userID, name, request)
+
+	response, err := a.JobConfigService.SaveConfig(ctx, name, request, userID)
+	if err != nil {
+		if goerr.Code(err) == 0 {
+			err = goerr.New(err, http.StatusInternalServerError, "unable to save job config")
+		}
+		errors.Throw(gctx, err)
+		return
+	}
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: response})
+}
+
+// Swagger not required as this is internal engg API
+func (a *AdminController) SetInvalidClient(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+	userID := context.Get(ctx).UserID
+	clientCode := gctx.Param(constants.ClientCode)
+
+	var request model.InvalidClientRequest
+	if err := gctx.ShouldBindJSON(&request); err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "invalid client invalid flag request"))
+		return
+	}
+	if request.Provider == "" {
+		request.Provider = constants.UpSwingProvider
+	}
+	if !slices.Contains(constants.KnownProviders, request.Provider) {
+		msg := fmt.Sprintf("Provider %s not supported", request.Provider)
+		errors.Throw(gctx, goerr.New(nil, http.StatusForbidden, msg))
+		return
+	}
+	log.Info(ctx).Msgf("UserID: %s; setting invalid flag of client for provider: %s; invalid: %t; scope: %s; reason: %s", userID, request.Provider, *request.Invalid, request.Scope, request.Reason)
+
+	response, err := a.InvalidClientService.SetInvalid(ctx, clientCode, request, userID)
+	if err != nil {
+		if goerr.Code(err) == 0 {
+			err = goerr.New(err, http.StatusInternalServerError, "unable to set invalid flag of client")
+		}
+		errors.Throw(gctx, err)
+		return
+	}
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: response})
+}
+
+// Swagger not required as this is internal engg API
+func (a *AdminController) GetInvalidClientAudit(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+	clientCode := gctx.Param(constants.ClientCode)
+	provider := gctx.DefaultQuery(constants.Provider, constants.UpSwingProvider)
+	limit, err := strconv.Atoi(gctx.DefaultQuery(constants.Limit, "50"))
+	if err != nil || limit <= 0 {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "limit must be a positive number"))
+		return
+	}
+
+	response, err := a.InvalidClientService.GetAudit(ctx, provider, clientCode, limit)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, "unable to fetch invalid client audit"))
+		return
+	}
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: response})
+}
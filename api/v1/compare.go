@@ -17,8 +17,8 @@ type CompareController struct {
 	CompareService service.CompareService
 }
 
-func DefaultCompareController() CompareController {
-	return CompareController{CompareService: service.DefaultCompareService()}
+func NewCompareController(compareService service.CompareService) CompareController {
+	return CompareController{CompareService: compareService}
 }
 
 // @Summary      Get comparable FSIs
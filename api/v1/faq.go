@@ -18,8 +18,8 @@ type FAQController struct {
 	FAQService service.FAQService
 }
 
-func DefaultFAQController() FAQController {
-	return FAQController{FAQService: service.DefaultFAQService()}
+func NewFAQController(faqService service.FAQService) FAQController {
+	return FAQController{FAQService: faqService}
 }
 
 // @Summary      Get FAQs
@@ -19,8 +19,8 @@ type HomepageController struct {
 	HomepageService service.HomepageService
 }
 
-func DefaultHomepageController() HomepageController {
-	return HomepageController{HomepageService: service.DefaultHomepageService()}
+func NewHomepageController(homepageService service.HomepageService) HomepageController {
+	return HomepageController{HomepageService: homepageService}
 }
 
 // @Summary      Get Homepage data
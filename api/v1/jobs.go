@@ -1,10 +1,19 @@
 package v1
 
 import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/angel-one/fd-core/business/jobs"
+	"github.com/angel-one/fd-core/business/model"
 	"github.com/angel-one/fd-core/business/service"
 	"github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/commons/errors"
 	"github.com/angel-one/fd-core/commons/log"
 	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/goerr"
 	"github.com/gin-gonic/gin"
 )
 
@@ -12,26 +21,145 @@ type JobsController struct {
 	JobsService service.JobsService
 }
 
-func DefaultJobsController() JobsController {
-	return JobsController{JobsService: service.DefaultJobsService()}
+func NewJobsController(jobsService service.JobsService) JobsController {
+	return JobsController{JobsService: jobsService}
 }
 
 // Swagger not required as this is internal engg API
+// Deprecated: use TriggerJobRun, kept for the existing callers and now runs the job in the background
 func (j *JobsController) GetPortfolioJob(gctx *gin.Context) {
+	j.triggerJob(gctx, jobs.PortfolioUpdateCron, model.JobTriggerRequest{Refresher: gctx.Param(constants.Refresher)})
+}
+
+// Swagger not required as this is internal engg API
+// Deprecated: use TriggerJobRun, kept for the existing callers and now runs the job in the background
+func (j *JobsController) GetPendingJourneyJob(gctx *gin.Context) {
+	j.triggerJob(gctx, jobs.PendingJourneyUpdateCron, model.JobTriggerRequest{Refresher: gctx.Param(constants.Refresher)})
+}
+
+// Swagger not required as this is internal engg API
+func (j *JobsController) TriggerJobRun(gctx *gin.Context) {
+	var request model.JobTriggerRequest
+	if gctx.Request.ContentLength > 0 {
+		if err := gctx.ShouldBindJSON(&request); err != nil {
+			errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "invalid job trigger request"))
+			return
+		}
+	}
+	j.triggerJob(gctx, gctx.Param(constants.JobName), request)
+}
+
+// triggerJob starts a run in the background and responds with it, a run already in progress is a conflict
+func (j *JobsController) triggerJob(gctx *gin.Context, name string, request model.JobTriggerRequest) {
 	ctx := context.Build(gctx)
-	clientCode := context.Get(ctx).UserID
-	refresher := gctx.Param(constants.Refresher)
-	log.Debug(ctx).Msgf("ClientCode: %s; Refresher: %s ", clientCode, refresher)
+	userID := context.Get(ctx).UserID
+	log.Info(ctx).Msgf("UserID: %s; triggering job %s; refresher: %s; mode: %s; runId: %d", userID, name, request.Refresher, request.Mode, request.RunID)
 
-	j.JobsService.InvokePortfolioJob(ctx, refresher)
+	response, err := j.JobsService.TriggerJob(ctx, name, request, userID)
+	if err != nil {
+		if goerr.Code(err) == 0 {
+			err = goerr.New(err, http.StatusInternalServerError, "unable to trigger job")
+		}
+		errors.Throw(gctx, err)
+		return
+	}
+	gctx.JSON(http.StatusAccepted, model.APIResponse{Data: response})
 }
 
 // Swagger not required as this is internal engg API
-func (j *JobsController) GetPendingJourneyJob(gctx *gin.Context) {
+func (j *JobsController) GetJobRun(gctx *gin.Context) {
 	ctx := context.Build(gctx)
-	clientCode := context.Get(ctx).UserID
-	refresher := gctx.Param(constants.Refresher)
-	log.Debug(ctx).Msgf("ClientCode: %s; Refresher: %s ", clientCode, refresher)
+	id, err := strconv.ParseInt(gctx.Param(constants.RunID), 10, 64)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "invalid job run id"))
+		return
+	}
+
+	response, err := j.JobsService.GetRun(ctx, id)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, "unable to fetch job run"))
+		return
+	}
+	if response == nil {
+		errors.Throw(gctx, goerr.New(nil, http.StatusNotFound, fmt.Sprintf("job run %d not found", id)))
+		return
+	}
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: response})
+}
+
+// Swagger not required as this is internal engg API
+func (j *JobsController) CancelJobRun(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+	userID := context.Get(ctx).UserID
+	id, err := strconv.ParseInt(gctx.Param(constants.RunID), 10, 64)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "invalid job run id"))
+		return
+	}
+	log.Info(ctx).Msgf("UserID: %s; cancelling job run %d", userID, id)
+
+	cancelled, err := j.JobsService.CancelRun(ctx, id)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, "unable to cancel job run"))
+		return
+	}
+	if !cancelled {
+		errors.Throw(gctx, goerr.New(nil, http.StatusNotFound, fmt.Sprintf("job run %d not found or not running", id)))
+		return
+	}
+	gctx.JSON(http.StatusAccepted, model.APIResponse{Data: model.JobRunCancelResult{RunID: id, CancelRequested: true}})
+}
+
+// Swagger not required as this is internal engg API
+func (j *JobsController) GetJobLeases(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+
+	response, err := j.JobsService.GetLeases(ctx)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, "unable to fetch job leases"))
+		return
+	}
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: response})
+}
+
+// Swagger not required as this is internal engg API
+func (j *JobsController) GetJobRuns(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+	filter := model.JobRunFilter{JobName: gctx.Query(constants.Job), Status: gctx.Query(constants.Status), Trigger: gctx.Query(constants.Trigger)}
+
+	var err error
+	filter.Limit, err = strconv.Atoi(gctx.DefaultQuery(constants.Limit, "50"))
+	if err != nil || filter.Limit <= 0 {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "limit must be a positive number"))
+		return
+	}
+	if filter.From, err = parseTimeQuery(gctx, constants.From); err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "from must be an RFC3339 timestamp"))
+		return
+	}
+	if filter.To, err = parseTimeQuery(gctx, constants.To); err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusBadRequest, "to must be an RFC3339 timestamp"))
+		return
+	}
+	log.Debug(ctx).Msgf("fetching job runs: %+v", filter)
+
+	response, err := j.JobsService.GetRuns(ctx, filter)
+	if err != nil {
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, "unable to fetch job runs"))
+		return
+	}
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: response})
+}
 
-	j.JobsService.InvokePendingJourneyJob(ctx, refresher)
+// parseTimeQuery returns nil when the query parameter is absent
+func parseTimeQuery(gctx *gin.Context, key string) (*time.Time, error) {
+	value := gctx.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
 }
@@ -0,0 +1,84 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/service"
+	"github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/commons/errors"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/goerr"
+	"github.com/gin-gonic/gin"
+)
+
+type JourneyController struct {
+	JourneyService service.JourneyService
+}
+
+func NewJourneyController(journeyService service.JourneyService) JourneyController {
+	return JourneyController{JourneyService: journeyService}
+}
+
+// @Summary      Get booking journeys
+// @Description  Lists the booking journeys of the user with their current state, latest first
+// @version 1.0
+// @Tags         Journeys
+// @Produce      json
+// @Param Authorization header string true "authorization token"
+// @Param X-Request-Id header string true "unique request id"
+// @Success      200  {object}  model.APIResponse{data=model.BookingJourneys}
+// @Failure	     400  {object}  errors.ErrResponse
+// @Failure      500  {object}  errors.ErrResponse
+// @Router       /v1/journeys [GET]
+func (c *JourneyController) GetJourneys(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+	clientCode := context.Get(ctx).UserID
+	provider := constants.UpSwingProvider //hardcode for now until we find another vendor
+	log.Info(ctx).Msgf("ClientCode: %s; Provider: %s", clientCode, provider)
+
+	response, err := c.JourneyService.GetJourneys(ctx, clientCode, provider)
+	if err != nil {
+		errMsg := fmt.Sprintf("unable to get journeys due to %v", err)
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, errMsg))
+		return
+	}
+	log.Trace(ctx).Msgf("Journeys Response: %+v", response)
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: response})
+}
+
+// @Summary      Get booking journey timeline
+// @Description  Returns the journey with every event received for it and the state reached after each event
+// @version 1.0
+// @Tags         Journeys
+// @Produce      json
+// @Param Authorization header string true "authorization token"
+// @Param X-Request-Id header string true "unique request id"
+// @Param id path string true "journey id"
+// @Success      200  {object}  model.APIResponse{data=model.JourneyTimeline}
+// @Failure	     400  {object}  errors.ErrResponse
+// @Failure      404  {object}  errors.ErrResponse
+// @Failure      500  {object}  errors.ErrResponse
+// @Router       /v1/journeys/{id}/timeline [GET]
+func (c *JourneyController) GetJourneyTimeline(gctx *gin.Context) {
+	ctx := context.Build(gctx)
+	clientCode := context.Get(ctx).UserID
+	journeyID := gctx.Param(constants.JourneyID)
+	provider := constants.UpSwingProvider //hardcode for now until we find another vendor
+	log.Info(ctx).Msgf("ClientCode: %s; Provider: %s; JourneyID: %s", clientCode, provider, journeyID)
+
+	response, err := c.JourneyService.GetJourneyTimeline(ctx, clientCode, provider, journeyID)
+	if err != nil {
+		errMsg := fmt.Sprintf("unable to get journey timeline due to %v", err)
+		errors.Throw(gctx, goerr.New(err, http.StatusInternalServerError, errMsg))
+		return
+	}
+	if response == nil {
+		errors.Throw(gctx, goerr.New(nil, http.StatusNotFound, fmt.Sprintf("journey %s not found", journeyID)))
+		return
+	}
+	log.Trace(ctx).Msgf("Journey Timeline Response: %+v", response)
+	gctx.JSON(http.StatusOK, model.APIResponse{Data: response})
+}
@@ -18,8 +18,8 @@ type PlansController struct {
 	PlansService service.PlansService
 }
 
-func DefaultPlansController() PlansController {
-	return PlansController{PlansService: service.DefaultPlansService()}
+func NewPlansController(plansService service.PlansService) PlansController {
+	return PlansController{PlansService: plansService}
 }
 
 // @Summary      Get all plans & details
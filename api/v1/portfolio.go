@@ -12,7 +12,6 @@ import (
 	"github.com/angel-one/fd-core/commons/log"
 	"github.com/angel-one/fd-core/constants"
 	"github.com/angel-one/fd-core/external"
-	"github.com/angel-one/fd-core/factory"
 	"github.com/angel-one/goerr"
 	"github.com/gin-gonic/gin"
 )
@@ -22,8 +21,8 @@ type PortfolioController struct {
 	portfolio v1.PortfolioService
 }
 
-func DefaultPortfolioController() PortfolioController {
-	return PortfolioController{upswing: factory.GetUpSwingExternalService(), portfolio: factory.GetPortfolioService()}
+func NewPortfolioController(upswing external.UpSwing, portfolio v1.PortfolioService) PortfolioController {
+	return PortfolioController{upswing: upswing, portfolio: portfolio}
 }
 
 // Swagger not required - this API would be decommissioned soon
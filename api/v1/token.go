@@ -11,7 +11,6 @@ import (
 	"github.com/angel-one/fd-core/commons/log"
 	"github.com/angel-one/fd-core/constants"
 	"github.com/angel-one/fd-core/external"
-	"github.com/angel-one/fd-core/factory"
 	"github.com/angel-one/goerr"
 	"github.com/gin-gonic/gin"
 )
@@ -20,8 +19,8 @@ type TokenController struct {
 	upswing external.UpSwing
 }
 
-func DefaultTokenController() TokenController {
-	return TokenController{upswing: factory.GetUpSwingExternalService()}
+func NewTokenController(upswing external.UpSwing) TokenController {
+	return TokenController{upswing: upswing}
 }
 
 // GetToken godoc
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/goerr"
+)
+
+// Hook is a step of the application lifecycle. Stop undoes Start and is called only when Start succeeded, either may
+// be nil.
+type Hook struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+// App starts its hooks in the order they were added and stops the started ones in the reverse order, so a hook can
+// rely on the ones before it for as long as it runs
+type App struct {
+	mu      sync.Mutex
+	hooks   []Hook
+	started []Hook
+}
+
+func New(hooks ...Hook) *App {
+	return &App{hooks: hooks}
+}
+
+// Append adds hooks started after the ones already added
+func (a *App) Append(hooks ...Hook) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.hooks = append(a.hooks, hooks...)
+}
+
+// Start starts the hooks not started yet. When a hook fails the hooks started before it are stopped and its error is
+// returned.
+func (a *App) Start(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, hook := range a.hooks[len(a.started):] {
+		if hook.Start != nil {
+			if err := hook.Start(ctx); err != nil {
+				if stopErr := a.stop(ctx); stopErr != nil {
+					log.Error(ctx).Err(stopErr).Msg("stopping the started hooks failed")
+				}
+				return goerr.New(err, fmt.Sprintf("app: starting %s failed", hook.Name))
+			}
+		}
+		log.Info(ctx).Msgf("%s started", hook.Name)
+		a.started = append(a.started, hook)
+	}
+	return nil
+}
+
+// Stop stops the started hooks in the reverse order. A failing hook does not keep the others from stopping, the errors
+// of all of them are returned.
+func (a *App) Stop(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.stop(ctx)
+}
+
+func (a *App) stop(ctx context.Context) error {
+	var errs []error
+	for i := len(a.started) - 1; i >= 0; i-- {
+		hook := a.started[i]
+		if hook.Stop == nil {
+			continue
+		}
+		if err := hook.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("stopping %s failed: %w", hook.Name, err))
+			continue
+		}
+		log.Info(ctx).Msgf("%s stopped", hook.Name)
+	}
+	a.started = nil
+	return errors.Join(errs...)
+}
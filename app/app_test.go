@@ -0,0 +1,46 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func recordingHook(name string, calls *[]string, startErr error, stopErr error) Hook {
+	return Hook{
+		Name: name,
+		Start: func(ctx context.Context) error {
+			*calls = append(*calls, "start "+name)
+			return startErr
+		},
+		Stop: func(ctx context.Context) error {
+			*calls = append(*calls, "stop "+name)
+			return stopErr
+		},
+	}
+}
+
+func TestAppLifecycle(t *testing.T) {
+	var calls []string
+	application := New(recordingHook("config", &calls, nil, nil), recordingHook("database", &calls, nil, errors.New("close failed")))
+	application.Append(Hook{Name: "logger"}, recordingHook("server", &calls, nil, nil))
+
+	assert.NoError(t, application.Start(context.Background()))
+	err := application.Stop(context.Background())
+	assert.ErrorContains(t, err, "stopping database failed")
+	assert.Equal(t, []string{"start config", "start database", "start server", "stop server", "stop database", "stop config"}, calls)
+}
+
+func TestAppStartFailure(t *testing.T) {
+	var calls []string
+	application := New(recordingHook("config", &calls, nil, nil), recordingHook("database", &calls, errors.New("unreachable"), nil),
+		recordingHook("server", &calls, nil, nil))
+
+	err := application.Start(context.Background())
+	assert.ErrorContains(t, err, "starting database failed")
+	// the failed hook is not stopped, the ones started before it are
+	assert.Equal(t, []string{"start config", "start database", "stop config"}, calls)
+	assert.NoError(t, application.Stop(context.Background()))
+}
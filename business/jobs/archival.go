@@ -0,0 +1,95 @@
+package jobs
+
+import (
+	c "context"
+	"errors"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/commons/log"
+)
+
+const (
+	ClosedRecordArchivalCron = "closedRecordArchivalCron"
+)
+
+// closedRecordArchivalJob moves the portfolios and pending journeys closed by the sweeps longer than the retention to
+// the archive tables, so the live tables only keep the customers of the retention period. It also purges the webhook
+// inbox events finished longer than the inbox retention.
+type closedRecordArchivalJob struct {
+	archivalDao dao.ArchivalDAO
+	jobRunDao   dao.JobRunDAO
+}
+
+func newClosedRecordArchivalJob(archivalDao dao.ArchivalDAO, jobRunDao dao.JobRunDAO) *closedRecordArchivalJob {
+	return &closedRecordArchivalJob{archivalDao: archivalDao, jobRunDao: jobRunDao}
+}
+
+func (a *closedRecordArchivalJob) Run() {
+	var ctx = context.Background(ClosedRecordArchivalCron)
+	defer ctx.Done()
+
+	enabled := isJobEnabled(ctx, ClosedRecordArchivalCron)
+	if !enabled {
+		log.Warn(ctx).Msg("closed record archival job is marked as disabled in config, skipping its execution")
+		return
+	}
+	runJob(ctx, a.jobRunDao, ClosedRecordArchivalCron, true, a)
+}
+
+// execute fails when a batch failed, the batches moved before it stay archived
+func (a *closedRecordArchivalJob) execute(ctx c.Context, refresher string, tracker *runTracker) error {
+	log.Info(ctx).Msg("starting closed record archival job...")
+	defer log.Info(ctx).Msg("stopping closed record archival job...")
+	retention := time.Duration(config.App().Archival.RetentionDays) * 24 * time.Hour
+	limit := int(getJobBatchSize(ClosedRecordArchivalCron, config.App().Archival.BatchSize))
+
+	var errs []error
+	portfolios, err := archiveInBatches(ctx, limit, func(limit int) (int64, error) {
+		return a.archivalDao.ArchiveClosedPortfolios(ctx, retention, limit)
+	})
+	if err != nil {
+		log.Error(ctx).Err(err).Stack().Msgf("archiving closed portfolios failed after %d rows", portfolios)
+		errs = append(errs, err)
+	}
+	pendingJourneys, err := archiveInBatches(ctx, limit, func(limit int) (int64, error) {
+		return a.archivalDao.ArchiveClosedPendingJourneys(ctx, retention, limit)
+	})
+	if err != nil {
+		log.Error(ctx).Err(err).Stack().Msgf("archiving closed pending journeys failed after %d rows", pendingJourneys)
+		errs = append(errs, err)
+	}
+	log.Info(ctx).Msgf("archived %d portfolios and %d pending journeys closed before %s", portfolios, pendingJourneys, time.Now().Add(-retention).Format(time.DateOnly))
+
+	inboxRetention := time.Duration(config.App().WebhookInbox.RetentionDays) * 24 * time.Hour
+	inboxEvents, err := archiveInBatches(ctx, limit, func(limit int) (int64, error) {
+		return a.archivalDao.PurgeFinishedWebhookInboxEvents(ctx, inboxRetention, limit)
+	})
+	if err != nil {
+		log.Error(ctx).Err(err).Stack().Msgf("purging finished webhook inbox events failed after %d rows", inboxEvents)
+		errs = append(errs, err)
+	}
+	log.Info(ctx).Msgf("purged %d webhook inbox events finished before %s", inboxEvents, time.Now().Add(-inboxRetention).Format(time.DateOnly))
+	return errors.Join(errs...)
+}
+
+// archiveInBatches archives batches of limit rows until a batch comes back short, returns the rows archived
+func archiveInBatches(ctx c.Context, limit int, archive func(limit int) (int64, error)) (int64, error) {
+	if limit <= 0 {
+		limit = 1000
+	}
+	var archived int64
+	for ctx.Err() == nil {
+		moved, err := archive(limit)
+		if err != nil {
+			return archived, err
+		}
+		archived += moved
+		if moved < int64(limit) {
+			return archived, nil
+		}
+	}
+	return archived, ctx.Err()
+}
@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchiveInBatches(t *testing.T) {
+	remaining := int64(25)
+	archived, err := archiveInBatches(context.Background(), 10, func(limit int) (int64, error) {
+		moved := min(remaining, int64(limit))
+		remaining -= moved
+		return moved, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(25), archived)
+	assert.Equal(t, int64(0), remaining)
+
+	calls := 0
+	archived, err = archiveInBatches(context.Background(), 10, func(limit int) (int64, error) {
+		calls++
+		if calls == 2 {
+			return 0, errors.New("connection reset")
+		}
+		return int64(limit), nil
+	})
+	assert.Error(t, err)
+	assert.Equal(t, int64(10), archived)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	archived, err = archiveInBatches(ctx, 10, func(limit int) (int64, error) {
+		cancel()
+		return int64(limit), nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, int64(10), archived)
+}
+
+type fakeArchivalDAO struct {
+	calls []string
+	err   error
+}
+
+func (f *fakeArchivalDAO) ArchiveClosedPortfolios(ctx context.Context, retention time.Duration, limit int) (int64, error) {
+	f.calls = append(f.calls, "portfolios")
+	return 0, f.err
+}
+
+func (f *fakeArchivalDAO) ArchiveClosedPendingJourneys(ctx context.Context, retention time.Duration, limit int) (int64, error) {
+	f.calls = append(f.calls, "pendingJourneys")
+	return 0, nil
+}
+
+func (f *fakeArchivalDAO) PurgeFinishedWebhookInboxEvents(ctx context.Context, retention time.Duration, limit int) (int64, error) {
+	f.calls = append(f.calls, "inboxEvents")
+	return 0, nil
+}
+
+func TestClosedRecordArchivalExecute(t *testing.T) {
+	archivalDAO := &fakeArchivalDAO{}
+	job := &closedRecordArchivalJob{archivalDao: archivalDAO}
+	assert.NoError(t, job.execute(context.Background(), "", &runTracker{}))
+	assert.Equal(t, []string{"portfolios", "pendingJourneys", "inboxEvents"}, archivalDAO.calls)
+
+	// a failed table does not keep the others from being archived, the run fails
+	archivalDAO = &fakeArchivalDAO{err: errors.New("connection reset")}
+	job = &closedRecordArchivalJob{archivalDao: archivalDAO}
+	assert.Error(t, job.execute(context.Background(), "", &runTracker{}))
+	assert.Equal(t, []string{"portfolios", "pendingJourneys", "inboxEvents"}, archivalDAO.calls)
+}
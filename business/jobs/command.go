@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	c "context"
+	"fmt"
+	"net/http"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/goerr"
+)
+
+// RunOptions are the options of a run of the run-job command, Clients replace the clients of the job
+type RunOptions struct {
+	Refresher string
+	Clients   []string
+}
+
+// untrackedJobs are the jobs whose runs are not recorded in job_runs, they process every client of the job
+func (j *Jobs) untrackedJobs() map[string]func(ctx c.Context) {
+	return map[string]func(ctx c.Context){
+		PortfolioReconciliationCron: j.reconciliation.execute,
+	}
+}
+
+// RunJob runs the job in the foreground whether it is enabled or not. The run of a tracked job is recorded like a
+// triggered one and fails with ErrJobRunning when a run of the job is in progress on any instance. The upswing token is
+// held in memory, so it is renewed before a job calling upswing.
+func (j *Jobs) RunJob(ctx c.Context, name string, options RunOptions) (entity.JobRunEntity, error) {
+	untracked, isUntracked := j.untrackedJobs()[name]
+	tracked, isTracked := j.triggerableJobs()[name]
+	switch name {
+	case TokenRenewalCron:
+		tracked, isTracked = j.token, true
+	case ClosedRecordArchivalCron:
+		tracked, isTracked = j.archival, true
+	}
+	if !isTracked && !isUntracked {
+		return entity.JobRunEntity{}, goerr.New(nil, http.StatusNotFound, fmt.Sprintf("unknown job %s", name))
+	}
+	if (isUntracked || name == ClosedRecordArchivalCron) && len(options.Clients) > 0 {
+		return entity.JobRunEntity{}, goerr.New(nil, http.StatusBadRequest, fmt.Sprintf("job %s can not be run for given clients", name))
+	}
+	if name != TokenRenewalCron && name != ClosedRecordArchivalCron {
+		if err := j.upswing.ValidateToken(ctx); err != nil {
+			return entity.JobRunEntity{}, goerr.New(err, fmt.Sprintf("jobs: renewing upswing token before job %s failed", name))
+		}
+	}
+
+	if isUntracked {
+		untracked(ctx)
+		return entity.JobRunEntity{JobName: name, Trigger: constants.JobTriggerCLI, Status: constants.JobRunSucceeded}, nil
+	}
+	runDAO := j.jobRunDAO
+	run := entity.JobRunEntity{JobName: name, Trigger: constants.JobTriggerCLI, Refresher: options.Refresher, Exclusive: name != TokenRenewalCron,
+		Mode: constants.JobRunModeFull}
+	tracker, started, err := startRun(ctx, runDAO, run)
+	if err != nil {
+		return entity.JobRunEntity{}, err
+	}
+	if !started {
+		return entity.JobRunEntity{}, goerr.New(ErrJobRunning, http.StatusConflict, fmt.Sprintf("job %s is already running", name))
+	}
+	tracker.clients = options.Clients
+	tracker.execute(ctx, runDAO, tracked, run.Refresher)
+
+	run = tracker.snapshot()
+	if run.Status != constants.JobRunSucceeded {
+		return run, goerr.New(nil, fmt.Sprintf("run %d of job %s %s: %s", run.ID, name, run.Status, run.Error))
+	}
+	return run, nil
+}
@@ -3,46 +3,104 @@ package jobs
 import (
 	"context"
 
+	"github.com/angel-one/fd-core/business/repository/dao"
 	"github.com/angel-one/fd-core/commons/config"
 	fdctx "github.com/angel-one/fd-core/commons/context"
 	"github.com/angel-one/fd-core/commons/log"
-	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/fd-core/external"
 	"github.com/robfig/cron/v3"
 )
 
-func StartJobs() {
+// JobNames are the cron jobs configurable through job_config
+var JobNames = []string{TokenRenewalCron, PortfolioUpdateCron, PendingJourneyUpdateCron, PortfolioReconciliationCron, InvalidClientRevalidationCron,
+	ClosedRecordArchivalCron}
+
+// Dependencies are the clients and DAOs of the jobs, the application builds them once at boot
+type Dependencies struct {
+	UpSwing           external.UpSwing
+	AlertHook         external.AlertHook
+	PortfolioDAO      dao.PortfolioDAO
+	PendingJourneyDAO dao.PendingJourneyDAO
+	ReconciliationDAO dao.ReconciliationDAO
+	JobRunDAO         dao.JobRunDAO
+	JobLeaseDAO       dao.JobLeaseDAO
+	JobConfigDAO      dao.JobConfigDAO
+	InvalidClientDAO  dao.InvalidClientDAO
+	ArchivalDAO       dao.ArchivalDAO
+}
+
+// Jobs are the cron jobs of the application, they are scheduled by Start, triggered through the API and run from the
+// command line
+type Jobs struct {
+	upswing        external.UpSwing
+	jobRunDAO      dao.JobRunDAO
+	jobLeaseDAO    dao.JobLeaseDAO
+	jobConfigDAO   dao.JobConfigDAO
+	token          *tokenRenewalJob
+	portfolio      *portfolioUpdateJob
+	pendingJourney *pendingJourneyJob
+	reconciliation *portfolioReconciliationJob
+	revalidation   *invalidClientRevalidationJob
+	archival       *closedRecordArchivalJob
+}
+
+func NewJobs(deps Dependencies) *Jobs {
+	return &Jobs{upswing: deps.UpSwing, jobRunDAO: deps.JobRunDAO, jobLeaseDAO: deps.JobLeaseDAO, jobConfigDAO: deps.JobConfigDAO,
+		token:          newTokenRenewalJob(deps.UpSwing),
+		portfolio:      newPortfolioUpdateJob(deps.UpSwing, deps.PortfolioDAO, deps.JobRunDAO),
+		pendingJourney: newPendingJourneyJob(deps.UpSwing, deps.PendingJourneyDAO, deps.JobRunDAO),
+		reconciliation: newPortfolioReconciliationJob(deps.UpSwing, deps.AlertHook, deps.ReconciliationDAO),
+		revalidation:   newInvalidClientRevalidationJob(deps.UpSwing, deps.InvalidClientDAO, deps.JobRunDAO),
+		archival:       newClosedRecordArchivalJob(deps.ArchivalDAO, deps.JobRunDAO)}
+}
+
+// Start schedules the jobs, every job but the token renewal runs on the instance holding its lease
+func (j *Jobs) Start() {
 	crons := map[string]cron.Job{
-		TokenRenewalCron:         DefaultTokenRenewalJob(),
-		PortfolioUpdateCron:      DefaultPortfolioUpdateJob(),
-		PendingJourneyUpdateCron: DefaultPendingJourneyJob(),
+		TokenRenewalCron:              j.token,
+		PortfolioUpdateCron:           j.portfolio,
+		PendingJourneyUpdateCron:      j.pendingJourney,
+		PortfolioReconciliationCron:   j.reconciliation,
+		InvalidClientRevalidationCron: j.revalidation,
+		ClosedRecordArchivalCron:      j.archival,
 	}
 
-	c := cron.New()
+	ctx := fdctx.Background("jobs")
 	for k, v := range crons {
-		schedule := GetConfig(k)
-		_, _ = c.AddJob(schedule, v)
+		// the upswing token is held in memory, every instance renews its own
+		if k != TokenRenewalCron {
+			crons[k] = withLease(k, v, j.jobLeaseDAO)
+		}
+	}
+
+	jobScheduler = newScheduler(crons, j.jobConfigDAO)
+	if err := jobScheduler.reload(ctx); err != nil {
+		log.Error(ctx).Err(err).Msg("loading job configs failed, scheduling from application config")
 	}
-	c.Start()
-	log.Info(fdctx.Background("jobs")).Msgf("inited crons: %+v\n", c.Entries())
+	jobScheduler.cron.Start()
+	jobScheduler.startReloading()
+	jobLeaseDAO = j.jobLeaseDAO
+	log.Info(ctx).Msgf("inited crons: %+v\n", jobScheduler.cron.Entries())
 }
 
-func GetConfig(key string) string {
-	return config.Default().GetStringD(constants.ApplicationConfig, key, "")
+// GetSchedule returns the schedule of the job in the application config, job_config overrides it at runtime
+func GetSchedule(name string) string {
+	return config.App().Jobs.Schedules.Of(name)
 }
 
+// isJobEnabled reads the enabled flag of job_config. Every job has a row from its migration, a job without one does not
+// run but the token renewal, which every instance needs for its upswing token.
 func isJobEnabled(ctx context.Context, name string) bool {
-	//todo: read from config/db for dynamism
-	enabled := false
-	if name == "tokenRenewalCron" {
-		enabled = true
+	if jobConfig, ok := getJobConfig(name); ok {
+		return jobConfig.Enabled
 	}
-	return enabled
+	return name == TokenRenewalCron
 }
 
 func getPortfolioUpdateProvider(ctx context.Context) string {
-	return config.Default().GetStringD(constants.ApplicationConfig, constants.PortfolioProvider, "")
+	return getJobProvider(PortfolioUpdateCron, config.App().Portfolio.Provider)
 }
 
 func getPendingJourneyUpdateProvider(ctx context.Context) string {
-	return config.Default().GetStringD(constants.ApplicationConfig, constants.PendingJourneyProvider, "")
+	return getJobProvider(PendingJourneyUpdateCron, config.App().PendingJourney.Provider)
 }
@@ -0,0 +1,114 @@
+package jobs
+
+import (
+	c "context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/robfig/cron/v3"
+)
+
+var instanceID = newInstanceID()
+
+// InstanceID identifies this replica as a job lease holder
+func InstanceID() string {
+	return instanceID
+}
+
+func newInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// leasedJob runs the job only on the replica holding its lease in job_leases. The holder renews the lease while its
+// run is active and releases it once the run ended, so a crashed holder's lease expires and the next tick of another
+// replica takes it over. The released lease is held until shortly before the next tick, the replicas ticking later in
+// the period do not run the job again.
+type leasedJob struct {
+	name     string
+	job      cron.Job
+	leaseDAO dao.JobLeaseDAO
+	running  atomic.Bool
+}
+
+func withLease(name string, job cron.Job, leaseDAO dao.JobLeaseDAO) cron.Job {
+	return &leasedJob{name: name, job: job, leaseDAO: leaseDAO}
+}
+
+func (l *leasedJob) Run() {
+	var ctx = context.Background(l.name)
+	defer ctx.Done()
+
+	// a tick while the run of the previous one is active must not release the lease under it
+	if !l.running.CompareAndSwap(false, true) {
+		log.Warn(ctx).Msgf("job %s is still running on this instance, skipping this run", l.name)
+		return
+	}
+	defer l.running.Store(false)
+
+	lease := getLeaseDuration()
+	acquired, err := l.leaseDAO.Acquire(ctx, l.name, instanceID, lease)
+	if err != nil {
+		log.Error(ctx).Err(err).Msgf("acquiring lease of job %s failed, skipping this run", l.name)
+		return
+	}
+	if !acquired {
+		log.Debug(ctx).Msgf("job %s is leased by another instance, skipping this run", l.name)
+		return
+	}
+
+	stop := make(chan struct{})
+	heartbeatStopped := l.heartbeat(ctx, lease, stop)
+	defer func() {
+		close(stop)
+		<-heartbeatStopped
+		if err := l.leaseDAO.Release(ctx, l.name, instanceID, leaseHold(l.name, time.Now())); err != nil {
+			log.Error(ctx).Err(err).Msgf("releasing lease of job %s failed, it expires in %s", l.name, lease)
+		}
+	}()
+	l.job.Run()
+}
+
+// heartbeat renews the lease of the job every third of the lease duration until stop is closed, the returned channel
+// is closed once it stopped
+func (l *leasedJob) heartbeat(ctx c.Context, lease time.Duration, stop chan struct{}) chan struct{} {
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			renewed, err := l.leaseDAO.Renew(ctx, l.name, instanceID, lease)
+			if err != nil {
+				log.Error(ctx).Err(err).Msgf("renewing lease of job %s failed", l.name)
+			} else if !renewed {
+				log.Warn(ctx).Msgf("lease of job %s was taken over by another instance", l.name)
+			}
+		}
+	}()
+	return stopped
+}
+
+// leaseHold returns how long after its acquisition a released lease is held: nine tenths of the schedule period, so the
+// lease is free again by the next tick
+func leaseHold(name string, now time.Time) time.Duration {
+	return schedulePeriod(name, now) * 9 / 10
+}
+
+func getLeaseDuration() time.Duration {
+	return time.Duration(config.App().Jobs.LeaseSeconds) * time.Second
+}
@@ -0,0 +1,131 @@
+package jobs
+
+import (
+	c "context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeJobLeaseDAO struct {
+	mu       sync.Mutex
+	holder   string
+	renewals int
+	released []time.Duration
+	// holders whose leases were all released
+	releasedAll []string
+}
+
+func (f *fakeJobLeaseDAO) Acquire(ctx c.Context, jobName string, holder string, lease time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.holder != "" && f.holder != holder {
+		return false, nil
+	}
+	f.holder = holder
+	return true, nil
+}
+
+func (f *fakeJobLeaseDAO) Renew(ctx c.Context, jobName string, holder string, lease time.Duration) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.renewals++
+	return f.holder == holder, nil
+}
+
+func (f *fakeJobLeaseDAO) Release(ctx c.Context, jobName string, holder string, hold time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.released = append(f.released, hold)
+	return nil
+}
+
+func (f *fakeJobLeaseDAO) ReleaseAll(ctx c.Context, holder string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.releasedAll = append(f.releasedAll, holder)
+	return 1, ctx.Err()
+}
+
+func (f *fakeJobLeaseDAO) FetchLeases(ctx c.Context) ([]entity.JobLeaseEntity, error) {
+	return nil, nil
+}
+
+func (f *fakeJobLeaseDAO) snapshot() (int, []time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.renewals, f.released
+}
+
+type blockingJob struct {
+	started chan struct{}
+	finish  chan struct{}
+}
+
+func (b *blockingJob) Run() {
+	close(b.started)
+	<-b.finish
+}
+
+func TestLeasedJobReleasesItsLeaseOnceTheRunEnded(t *testing.T) {
+	leaseDAO := &fakeJobLeaseDAO{}
+	job := &blockingJob{started: make(chan struct{}), finish: make(chan struct{})}
+	leased := withLease(PortfolioUpdateCron, job, leaseDAO)
+	ran := make(chan struct{})
+	go func() {
+		defer close(ran)
+		leased.Run()
+	}()
+	<-job.started
+
+	// a tick while the run is active is skipped without touching the lease
+	leased.Run()
+	_, released := leaseDAO.snapshot()
+	assert.Empty(t, released)
+
+	close(job.finish)
+	<-ran
+	_, released = leaseDAO.snapshot()
+	assert.Len(t, released, 1)
+
+	// another instance skips the job while the lease is held
+	leaseDAO.holder = "other-instance"
+	skipped := &blockingJob{started: make(chan struct{}), finish: make(chan struct{})}
+	withLease(PortfolioUpdateCron, skipped, leaseDAO).Run()
+	select {
+	case <-skipped.started:
+		t.Fatal("job ran without holding its lease")
+	default:
+	}
+}
+
+func TestLeasedJobHeartbeat(t *testing.T) {
+	leaseDAO := &fakeJobLeaseDAO{holder: instanceID}
+	leased := &leasedJob{name: PortfolioUpdateCron, leaseDAO: leaseDAO}
+	stop := make(chan struct{})
+	stopped := leased.heartbeat(c.Background(), 30*time.Millisecond, stop)
+	assert.Eventually(t, func() bool {
+		renewals, _ := leaseDAO.snapshot()
+		return renewals >= 2
+	}, time.Second, 5*time.Millisecond)
+
+	close(stop)
+	<-stopped
+	renewals, _ := leaseDAO.snapshot()
+	time.Sleep(50 * time.Millisecond)
+	after, _ := leaseDAO.snapshot()
+	assert.Equal(t, renewals, after, "the lease is not renewed once the run ended")
+}
+
+func TestLeaseHold(t *testing.T) {
+	previous := jobScheduler
+	t.Cleanup(func() { jobScheduler = previous })
+	jobScheduler = newScheduler(nil, nil)
+	jobScheduler.schedules = map[string]string{PortfolioUpdateCron: "@every 5m", PendingJourneyUpdateCron: "not a cron"}
+
+	assert.Equal(t, 270*time.Second, leaseHold(PortfolioUpdateCron, time.Now()))
+	assert.Equal(t, time.Duration(0), leaseHold(PendingJourneyUpdateCron, time.Now()))
+}
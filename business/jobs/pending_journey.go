@@ -12,30 +12,22 @@ import (
 	"github.com/angel-one/fd-core/commons/log"
 	"github.com/angel-one/fd-core/constants"
 	"github.com/angel-one/fd-core/external"
-	"github.com/angel-one/fd-core/factory"
 	"github.com/angel-one/goerr"
-	"github.com/robfig/cron/v3"
 )
 
 const (
 	PendingJourneyUpdateCron = "pendingJourneyUpdateCron"
 )
 
-type PendingJourneyUpdater interface {
-	DoJob(ctx c.Context, refresher string)
-}
-
 type pendingJourneyJob struct {
 	upswing           external.UpSwing
 	pendingJourneyDao dao.PendingJourneyDAO
+	jobRunDao         dao.JobRunDAO
+	limiter           *tokenBucket
 }
 
-func DefaultPendingJourneyJob() cron.Job {
-	return &pendingJourneyJob{upswing: factory.GetUpSwingExternalService(), pendingJourneyDao: factory.GetPendingJourneyDAO()}
-}
-
-func DefaultPendingJourneyUpdater() PendingJourneyUpdater {
-	return &pendingJourneyJob{upswing: factory.GetUpSwingExternalService(), pendingJourneyDao: factory.GetPendingJourneyDAO()}
+func newPendingJourneyJob(upswing external.UpSwing, pendingJourneyDao dao.PendingJourneyDAO, jobRunDao dao.JobRunDAO) *pendingJourneyJob {
+	return &pendingJourneyJob{upswing: upswing, pendingJourneyDao: pendingJourneyDao, jobRunDao: jobRunDao, limiter: getUpswingLimiter()}
 }
 
 func (p *pendingJourneyJob) Run() {
@@ -47,95 +39,103 @@ func (p *pendingJourneyJob) Run() {
 		log.Warn(ctx).Msg("pending journey job is marked as disabled in config, skipping its execution")
 		return
 	}
-	p.DoJob(ctx, "")
+	runJob(ctx, p.jobRunDao, PendingJourneyUpdateCron, true, p)
 }
 
-func (p *pendingJourneyJob) DoJob(ctx c.Context, refresher string) {
+func (p *pendingJourneyJob) execute(ctx c.Context, refresher string, tracker *runTracker) error {
 	log.Info(ctx).Msg("starting pending journey job...")
+	defer log.Info(ctx).Msg("stopping pending journey update job...")
 	var instantRefresh bool
 	if refresher == "instant" {
 		instantRefresh = true
 	}
-	p.execute(ctx, instantRefresh)
-	log.Info(ctx).Msg("stopping pending journey update job...")
-}
 
-func (p *pendingJourneyJob) execute(ctx c.Context, instantRefresh bool) {
 	provider := getPendingJourneyUpdateProvider(ctx)
-	clientList, err := p.pendingJourneyDao.FetchClientList(ctx, provider, instantRefresh)
+	clientList, err := runClients(ctx, p.jobRunDao, tracker, func() ([]string, error) {
+		return p.pendingJourneyDao.FetchClientList(ctx, provider, instantRefresh)
+	})
 	if err != nil {
 		log.Error(ctx).Err(err).Stack().Msg("fetching client list for pending journey job failed")
-		return
+		return err
 	}
 
-	var batchSize = config.Default().GetIntD(constants.ApplicationConfig, constants.PendingJourneyUpdateBatchSize, 50)
-	var pendingJourneyEntities []entity.PendingJourneyEntity
-	for _, clientCode := range clientList {
-		var pendingJourneyEntity entity.PendingJourneyEntity
-		var errRespMap map[string]interface{}
-		var isError bool
-		response, err := p.upswing.GetPendingJourneyData(ctx, clientCode)
-		if err != nil {
-			log.Error(ctx).Err(err).Stack().Msg("error from upswing API")
-			errResp := goerr.ListStacks(err)[2]
-			err = json.Unmarshal([]byte(errResp), &errRespMap)
-			if err != nil {
-				log.Error(ctx).Err(err).Stack().Msg("error unmarshalling upswing API error response JSON")
-				return
-			}
-			isError = true
-		}
-		pendingJourneyEntity.ClientCode = clientCode
-		pendingJourneyEntity.Provider = provider
-		pendingJourneyEntity.CreatedBy = "pending_journey_update_job"
-		pendingJourneyEntity.UpdatedBy = "pending_journey_update_job"
-		if isError {
-			key := constants.ErrorCode
-			if _, exists := errRespMap[key]; exists {
-				if errRespMap[key] == fmt.Sprintf(constants.ErrPciNotFound, clientCode) {
-					pendingJourneyEntity.InvalidClient = true
-				}
-				pendingJourneyEntity.ApiError = errRespMap[key].(string)
-			}
-			pendingJourneyEntity.Pending = false
-			pendingJourneyEntity.Payment = false
-			pendingJourneyEntity.KYC = false
-		} else {
-			pendingJourneyEntity.Pending = response.JourneyPending
-			pendingJourneyEntity.Payment = response.JourneyPendingOnPayment
-			pendingJourneyEntity.KYC = response.JourneyPendingOnVkyc
-		}
-		pendingJourneyEntities = append(pendingJourneyEntities, pendingJourneyEntity)
-
-		if len(pendingJourneyEntities) >= int(batchSize) {
-			err = p.pendingJourneyDao.BatchUpdatePendingJourney(ctx, pendingJourneyEntities)
-			if err != nil {
-				log.Error(ctx).Err(err).Stack().Msg("batch updating pending journey failed")
-				return
-			}
-			pendingJourneyEntities = pendingJourneyEntities[:0]
-		}
+	tracker.setTotal(len(clientList))
+	opts := sweepOptions{
+		concurrency: getJobConcurrency(PendingJourneyUpdateCron),
+		batchSize:   int(getJobBatchSize(PendingJourneyUpdateCron, config.App().PendingJourney.UpdateBatchSize)),
+		limiter:     p.limiter,
+		tracker:     tracker,
 	}
-
-	if len(pendingJourneyEntities) > 0 {
-		err = p.pendingJourneyDao.BatchUpdatePendingJourney(ctx, pendingJourneyEntities)
+	err = sweep(ctx, clientList, opts, func(ctx c.Context, clientCode string) (entity.PendingJourneyEntity, error) {
+		return p.fetchPendingJourney(ctx, provider, clientCode, tracker)
+	}, func(ctx c.Context, batch []entity.PendingJourneyEntity) error {
+		err := p.pendingJourneyDao.BatchUpdatePendingJourney(ctx, batch)
 		if err != nil {
 			log.Error(ctx).Err(err).Stack().Msg("batch updating pending journey failed")
-			return
+			return err
 		}
+		tracker.checkpoint(batch[len(batch)-1].ClientCode)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	if instantRefresh {
 		err := p.pendingJourneyDao.UpdateRefreshedPendingJourneyClientList(ctx, provider, clientList)
 		if err != nil {
 			log.Error(ctx).Err(err).Stack().Msg("error while update refreshed pending_journey client list")
-			return
+			return err
 		}
 	} else {
-		err := p.pendingJourneyDao.CleanStaleRecords(ctx)
+		err := p.pendingJourneyDao.CloseStaleRecords(ctx)
 		if err != nil {
-			log.Error(ctx).Err(err).Stack().Msg("error while cleaning up stale pending_journey records")
-			return
+			log.Error(ctx).Err(err).Stack().Msg("error while closing stale pending_journey records")
+			return err
 		}
 	}
+	return nil
+}
+
+// fetchPendingJourney builds the pending journey of the client from upswing, a failed call is stored as the api error
+func (p *pendingJourneyJob) fetchPendingJourney(ctx c.Context, provider string, clientCode string, tracker *runTracker) (entity.PendingJourneyEntity, error) {
+	var pendingJourneyEntity entity.PendingJourneyEntity
+	var errRespMap map[string]interface{}
+	var isError bool
+	response, err := p.upswing.GetPendingJourneyData(ctx, clientCode)
+	if err != nil {
+		log.Error(ctx).Err(err).Stack().Msg("error from upswing API")
+		errResp := goerr.ListStacks(err)[2]
+		err = json.Unmarshal([]byte(errResp), &errRespMap)
+		if err != nil {
+			log.Error(ctx).Err(err).Stack().Msg("error unmarshalling upswing API error response JSON")
+			return pendingJourneyEntity, err
+		}
+		isError = true
+	}
+	pendingJourneyEntity.ClientCode = clientCode
+	pendingJourneyEntity.Provider = provider
+	pendingJourneyEntity.CreatedBy = "pending_journey_update_job"
+	pendingJourneyEntity.UpdatedBy = "pending_journey_update_job"
+	if isError {
+		key := constants.ErrorCode
+		if _, exists := errRespMap[key]; exists {
+			if errRespMap[key] == fmt.Sprintf(constants.ErrPciNotFound, clientCode) {
+				pendingJourneyEntity.InvalidClient = true
+			}
+			pendingJourneyEntity.ApiError = errRespMap[key].(string)
+		}
+		pendingJourneyEntity.Pending = false
+		pendingJourneyEntity.Payment = false
+		pendingJourneyEntity.KYC = false
+	} else {
+		pendingJourneyEntity.Pending = response.JourneyPending
+		pendingJourneyEntity.Payment = response.JourneyPendingOnPayment
+		pendingJourneyEntity.KYC = response.JourneyPendingOnVkyc
+	}
+	tracker.processed(isError, pendingJourneyEntity.InvalidClient)
+	if isError && !pendingJourneyEntity.InvalidClient {
+		tracker.failed(clientCode, pendingJourneyEntity.ApiError)
+	}
+	return pendingJourneyEntity, nil
 }
@@ -0,0 +1,219 @@
+package jobs
+
+import (
+	c "context"
+	"sync"
+	"time"
+
+	"github.com/angel-one/fd-core/commons/config"
+)
+
+// tokenBucket allows rate requests per second on average and bursts of up to burst requests
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now(), now: time.Now}
+}
+
+// update replaces the rate and the burst, the tokens gathered at the previous rate are kept up to the new burst
+func (b *tokenBucket) update(rate float64, burst int) {
+	if burst < 1 {
+		burst = 1
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.refill()
+	b.rate, b.burst = rate, float64(burst)
+	if b.rate <= 0 || b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+func (b *tokenBucket) refill() {
+	now := b.now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// reserve takes a token and returns how long to wait until it is available, a rate of 0 never waits
+func (b *tokenBucket) reserve() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.rate <= 0 {
+		return 0
+	}
+	b.refill()
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// Wait blocks until a request is allowed or ctx is done
+func (b *tokenBucket) Wait(ctx c.Context) error {
+	wait := b.reserve()
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+var upswingLimiter struct {
+	once   sync.Once
+	bucket *tokenBucket
+}
+
+// getUpswingLimiter returns the limiter shared by every job calling upswing, so that concurrent jobs together stay
+// within the upswing quota
+func getUpswingLimiter() *tokenBucket {
+	upswingLimiter.once.Do(func() {
+		jobs := config.App().Jobs
+		upswingLimiter.bucket = newTokenBucket(float64(jobs.UpswingRateLimitPerSecond), int(jobs.UpswingRateLimitBurst))
+	})
+	return upswingLimiter.bucket
+}
+
+// UpdateUpswingLimiter applies the reloaded rate and burst to the shared limiter
+func UpdateUpswingLimiter(jobs config.Jobs) {
+	getUpswingLimiter().update(float64(jobs.UpswingRateLimitPerSecond), int(jobs.UpswingRateLimitBurst))
+}
+
+// getJobConcurrency returns the number of clients of the job processed at once, from job_config
+func getJobConcurrency(name string) int {
+	if jobConfig, ok := getJobConfig(name); ok && jobConfig.Concurrency > 0 {
+		return jobConfig.Concurrency
+	}
+	return 1
+}
+
+// sweepOptions configures a sweep, a nil limiter does not limit the fetches
+type sweepOptions struct {
+	concurrency int
+	batchSize   int
+	limiter     *tokenBucket
+	tracker     *runTracker
+}
+
+type sweepResult[T any] struct {
+	index int
+	value T
+	err   error
+}
+
+// sweep fetches the clients with a pool of workers and flushes the results in batches of batchSize, in the order of
+// clients. At most concurrency+batchSize results are held at once. The first fetch or flush error stops the sweep and
+// is returned, as is the error of ctx once it is done. A flush in progress completes when ctx is done, so a stopped
+// sweep ends at a batch boundary.
+func sweep[T any](ctx c.Context, clients []string, opts sweepOptions, fetch func(ctx c.Context, clientCode string) (T, error), flush func(ctx c.Context, batch []T) error) error {
+	if opts.concurrency < 1 {
+		opts.concurrency = 1
+	}
+	if opts.batchSize < 1 {
+		opts.batchSize = 1
+	}
+	ctx, cancel := c.WithCancel(ctx)
+	defer cancel()
+
+	// the window keeps the workers from running ahead of a slow client that is still being fetched
+	window := make(chan struct{}, opts.concurrency+opts.batchSize)
+	indexes := make(chan int)
+	results := make(chan sweepResult[T])
+
+	go func() {
+		defer close(indexes)
+		for i := range clients {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case indexes <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var workers sync.WaitGroup
+	for w := 0; w < opts.concurrency; w++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for i := range indexes {
+				result := sweepResult[T]{index: i}
+				if opts.limiter != nil {
+					result.err = opts.limiter.Wait(ctx)
+				}
+				if result.err == nil {
+					start := time.Now()
+					result.value, result.err = fetch(ctx, clients[i])
+					if opts.tracker != nil {
+						opts.tracker.observe(time.Since(start))
+					}
+				}
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]T)
+	batch := make([]T, 0, opts.batchSize)
+	next := 0
+	for result := range results {
+		if result.err != nil {
+			cancel()
+			return result.err
+		}
+		pending[result.index] = result.value
+		for value, ok := pending[next]; ok; value, ok = pending[next] {
+			delete(pending, next)
+			next++
+			<-window
+			batch = append(batch, value)
+			if len(batch) >= opts.batchSize {
+				if err := flush(c.WithoutCancel(ctx), batch); err != nil {
+					cancel()
+					return err
+				}
+				batch = batch[:0]
+			}
+		}
+	}
+	if next < len(clients) {
+		return ctx.Err()
+	}
+	if len(batch) > 0 {
+		return flush(c.WithoutCancel(ctx), batch)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package jobs
+
+import (
+	c "context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucketReserve(t *testing.T) {
+	now := time.Unix(0, 0)
+	bucket := newTokenBucket(10, 2)
+	bucket.last = now
+	bucket.now = func() time.Time { return now }
+
+	assert.Equal(t, time.Duration(0), bucket.reserve())
+	assert.Equal(t, time.Duration(0), bucket.reserve())
+	assert.Equal(t, 100*time.Millisecond, bucket.reserve())
+	assert.Equal(t, 200*time.Millisecond, bucket.reserve())
+
+	// refilled tokens never exceed the burst
+	now = now.Add(time.Minute)
+	assert.Equal(t, time.Duration(0), bucket.reserve())
+	assert.Equal(t, time.Duration(0), bucket.reserve())
+	assert.Equal(t, 100*time.Millisecond, bucket.reserve())
+
+	unlimited := newTokenBucket(0, 1)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, time.Duration(0), unlimited.reserve())
+	}
+}
+
+func TestTokenBucketUpdate(t *testing.T) {
+	now := time.Unix(0, 0)
+	bucket := newTokenBucket(0, 1)
+	bucket.last = now
+	bucket.now = func() time.Time { return now }
+
+	// limited once the rate is set
+	bucket.update(10, 1)
+	assert.Equal(t, time.Duration(0), bucket.reserve())
+	assert.Equal(t, 100*time.Millisecond, bucket.reserve())
+
+	// the debt taken at the previous rate is paid at the new one
+	bucket.update(5, 3)
+	assert.Equal(t, 400*time.Millisecond, bucket.reserve())
+
+	bucket.update(0, 1)
+	assert.Equal(t, time.Duration(0), bucket.reserve())
+}
+
+func TestTokenBucketWaitCancelled(t *testing.T) {
+	bucket := newTokenBucket(0.001, 1)
+	assert.NoError(t, bucket.Wait(c.Background()))
+
+	ctx, cancel := c.WithCancel(c.Background())
+	cancel()
+	assert.ErrorIs(t, bucket.Wait(ctx), c.Canceled)
+}
+
+func sweepClients(n int) []string {
+	clients := make([]string, n)
+	for i := range clients {
+		clients[i] = fmt.Sprintf("C%03d", i)
+	}
+	return clients
+}
+
+func TestSweepFlushesInOrder(t *testing.T) {
+	clients := sweepClients(103)
+	tracker := &runTracker{}
+	var flushed []string
+	var batches []int
+	err := sweep(c.Background(), clients, sweepOptions{concurrency: 8, batchSize: 10, tracker: tracker}, func(ctx c.Context, clientCode string) (string, error) {
+		time.Sleep(time.Duration(rand.Intn(500)) * time.Microsecond)
+		return clientCode, nil
+	}, func(ctx c.Context, batch []string) error {
+		flushed = append(flushed, batch...)
+		batches = append(batches, len(batch))
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, clients, flushed)
+	assert.Equal(t, []int{10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 3}, batches)
+	assert.Len(t, tracker.latencies, len(clients))
+}
+
+func TestSweepBoundsConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	var running, maxRunning int
+	err := sweep(c.Background(), sweepClients(50), sweepOptions{concurrency: 3, batchSize: 5}, func(ctx c.Context, clientCode string) (string, error) {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		time.Sleep(time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return clientCode, nil
+	}, func(ctx c.Context, batch []string) error {
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, maxRunning)
+}
+
+func TestSweepStopsOnError(t *testing.T) {
+	fetchErr := errors.New("unreadable error response")
+	err := sweep(c.Background(), sweepClients(100), sweepOptions{concurrency: 4, batchSize: 10}, func(ctx c.Context, clientCode string) (string, error) {
+		if clientCode == "C042" {
+			return "", fetchErr
+		}
+		return clientCode, nil
+	}, func(ctx c.Context, batch []string) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, fetchErr)
+
+	flushErr := errors.New("batch update failed")
+	err = sweep(c.Background(), sweepClients(100), sweepOptions{concurrency: 4, batchSize: 10}, func(ctx c.Context, clientCode string) (string, error) {
+		return clientCode, nil
+	}, func(ctx c.Context, batch []string) error {
+		return flushErr
+	})
+	assert.ErrorIs(t, err, flushErr)
+}
+
+func TestSweepCancelled(t *testing.T) {
+	ctx, cancel := c.WithCancel(c.Background())
+	var flushed int
+	err := sweep(ctx, sweepClients(100), sweepOptions{concurrency: 2, batchSize: 10}, func(ctx c.Context, clientCode string) (string, error) {
+		if clientCode == "C020" {
+			cancel()
+		}
+		return clientCode, nil
+	}, func(ctx c.Context, batch []string) error {
+		flushed += len(batch)
+		return nil
+	})
+
+	assert.ErrorIs(t, err, c.Canceled)
+	assert.Less(t, flushed, 100)
+}
+
+func TestRunTrackerMeasure(t *testing.T) {
+	started := time.Unix(0, 0)
+	tracker := &runTracker{started: started}
+	for i := 1; i <= 20; i++ {
+		tracker.processed(false, false)
+		tracker.observe(time.Duration(i) * time.Millisecond)
+	}
+	tracker.measure(started.Add(4 * time.Second))
+
+	run := tracker.snapshot()
+	assert.Equal(t, 5.0, run.ThroughputPerSecond)
+	assert.Equal(t, 10.5, run.LatencyAvgMs)
+	assert.Equal(t, 19.0, run.LatencyP95Ms)
+}
@@ -11,30 +11,22 @@ import (
 	"github.com/angel-one/fd-core/commons/log"
 	"github.com/angel-one/fd-core/constants"
 	"github.com/angel-one/fd-core/external"
-	"github.com/angel-one/fd-core/factory"
 	"github.com/angel-one/goerr"
-	"github.com/robfig/cron/v3"
 )
 
 const (
 	PortfolioUpdateCron = "portfolioUpdateCron"
 )
 
-type PortfolioUpdater interface {
-	DoJob(ctx c.Context, refresher string)
-}
-
 type portfolioUpdateJob struct {
 	upswing      external.UpSwing
 	portfolioDao dao.PortfolioDAO
+	jobRunDao    dao.JobRunDAO
+	limiter      *tokenBucket
 }
 
-func DefaultPortfolioUpdateJob() cron.Job {
-	return &portfolioUpdateJob{upswing: factory.GetUpSwingExternalService(), portfolioDao: factory.GetPortfolioDAO()}
-}
-
-func DefaultPortfolioUpdater() PortfolioUpdater {
-	return &portfolioUpdateJob{upswing: factory.GetUpSwingExternalService(), portfolioDao: factory.GetPortfolioDAO()}
+func newPortfolioUpdateJob(upswing external.UpSwing, portfolioDao dao.PortfolioDAO, jobRunDao dao.JobRunDAO) *portfolioUpdateJob {
+	return &portfolioUpdateJob{upswing: upswing, portfolioDao: portfolioDao, jobRunDao: jobRunDao, limiter: getUpswingLimiter()}
 }
 
 func (p *portfolioUpdateJob) Run() {
@@ -46,110 +38,124 @@ func (p *portfolioUpdateJob) Run() {
 		return
 	}
 
-	p.DoJob(ctx, "")
+	runJob(ctx, p.jobRunDao, PortfolioUpdateCron, true, p)
 }
 
-func (p *portfolioUpdateJob) DoJob(ctx c.Context, refresher string) {
+func (p *portfolioUpdateJob) execute(ctx c.Context, refresher string, tracker *runTracker) error {
 	log.Info(ctx).Msg("starting portfolio update job...")
+	defer log.Info(ctx).Msg("stopping portfolio update job...")
 	var instantRefresh bool
 	if refresher == "instant" {
 		instantRefresh = true
 	}
-	p.execute(ctx, instantRefresh)
-	log.Info(ctx).Msg("stopping portfolio update job...")
-}
 
-func (p *portfolioUpdateJob) execute(ctx c.Context, instantRefresh bool) {
-	provider := getPortfolioUpdateProvider(ctx)
-	clientList, err := p.portfolioDao.FetchClientList(ctx, provider, instantRefresh)
+	provider, startedAt := getPortfolioUpdateProvider(ctx), sweepStartedAt(tracker)
+	clientList, err := runClients(ctx, p.jobRunDao, tracker, func() ([]string, error) {
+		if instantRefresh {
+			return p.portfolioDao.FetchClientList(ctx, provider, true)
+		}
+		return p.portfolioDao.FetchDueClientList(ctx, provider, getPortfolioMaxStale())
+	})
 	if err != nil {
 		log.Error(ctx).Err(err).Stack().Msg("fetching client list for portfolio update job failed")
-		return
+		return err
 	}
 
-	var batchSize = config.Default().GetIntD(constants.ApplicationConfig, constants.PortfolioUpdateBatchSize, 50)
-	var portfolioUpdateEntities []entity.PortfolioEntity
-
-	for _, clientCode := range clientList {
-		var totalInterestPercentage float64
-		var portfolioUpdateEntity entity.PortfolioEntity
-		var errRespMap map[string]interface{}
-		var isError bool
-		response, err := p.upswing.GetNetWorthData(ctx, clientCode)
-		if err != nil {
-			log.Error(ctx).Err(err).Stack().Msg("error from upswing API")
-			errResp := goerr.ListStacks(err)[2]
-			err = json.Unmarshal([]byte(errResp), &errRespMap)
-			if err != nil {
-				log.Error(ctx).Err(err).Stack().Msg("error unmarshalling upswing API error response JSON")
-				return
-			}
-			isError = true
-		}
-		portfolioUpdateEntity.ClientCode = clientCode
-		portfolioUpdateEntity.Provider = provider
-		portfolioUpdateEntity.CreatedBy = "portfolio_update_job"
-		portfolioUpdateEntity.UpdatedBy = "portfolio_update_job"
-
-		if isError {
-			key := constants.ErrorCode
-			if _, exists := errRespMap[key]; exists {
-				if errRespMap[key] == constants.ErrClientNotFound {
-					portfolioUpdateEntity.InvalidClient = true
-				}
-				portfolioUpdateEntity.ApiError = errRespMap[key].(string)
-			}
-			portfolioUpdateEntity.TotalActiveDeposits = 0
-			portfolioUpdateEntity.InvestedValue = 0.0
-			portfolioUpdateEntity.CurrentValue = 0.0
-			portfolioUpdateEntity.InterestEarned = 0.0
-			portfolioUpdateEntity.ReturnsValue = 0.0
-			portfolioUpdateEntity.ReturnsPercentage = 0.0
-		} else {
-			if response.TotalInvestedAmount.Amount == 0.00 {
-				totalInterestPercentage = 0.00
-			} else {
-				totalInterestPercentage = (response.TotalInterestEarned.Amount / response.TotalInvestedAmount.Amount) * 100
-			}
-			portfolioUpdateEntity.TotalActiveDeposits = response.ActiveTermDepositCount
-			portfolioUpdateEntity.InvestedValue = response.TotalInvestedAmount.Amount
-			portfolioUpdateEntity.CurrentValue = response.CurrentAmount.Amount
-			portfolioUpdateEntity.InterestEarned = response.TotalInterestEarned.Amount
-			portfolioUpdateEntity.ReturnsValue = response.TotalInterestEarned.Amount
-			portfolioUpdateEntity.ReturnsPercentage = totalInterestPercentage
-		}
-
-		portfolioUpdateEntities = append(portfolioUpdateEntities, portfolioUpdateEntity)
-
-		if len(portfolioUpdateEntities) >= int(batchSize) {
-			err = p.portfolioDao.BatchUpdatePortfolio(ctx, portfolioUpdateEntities)
-			if err != nil {
-				log.Error(ctx).Err(err).Stack().Msg("batch updating client portfolios failed")
-				return
-			}
-			portfolioUpdateEntities = portfolioUpdateEntities[:0]
-		}
+	tracker.setTotal(len(clientList))
+	opts := sweepOptions{
+		concurrency: getJobConcurrency(PortfolioUpdateCron),
+		batchSize:   int(getJobBatchSize(PortfolioUpdateCron, config.App().Portfolio.UpdateBatchSize)),
+		limiter:     p.limiter,
+		tracker:     tracker,
 	}
-
-	if len(portfolioUpdateEntities) > 0 {
-		err = p.portfolioDao.BatchUpdatePortfolio(ctx, portfolioUpdateEntities)
+	err = sweep(ctx, clientList, opts, func(ctx c.Context, clientCode string) (entity.PortfolioEntity, error) {
+		return p.fetchPortfolio(ctx, provider, clientCode, tracker)
+	}, func(ctx c.Context, batch []entity.PortfolioEntity) error {
+		if err := p.scheduleRefresh(ctx, provider, batch, startedAt); err != nil {
+			log.Error(ctx).Err(err).Stack().Msg("scheduling next portfolio refresh failed")
+			return err
+		}
+		err := p.portfolioDao.BatchUpdatePortfolio(ctx, batch)
 		if err != nil {
 			log.Error(ctx).Err(err).Stack().Msg("batch updating client portfolios failed")
-			return
+			return err
 		}
+		tracker.checkpoint(batch[len(batch)-1].ClientCode)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	if instantRefresh {
 		err := p.portfolioDao.UpdateRefreshedPortfolioClientList(ctx, provider, clientList)
 		if err != nil {
 			log.Error(ctx).Err(err).Stack().Msg("error while update refreshed portfolio client list")
-			return
+			return err
 		}
 	} else {
-		err := p.portfolioDao.CleanStaleRecords(ctx)
+		err := p.portfolioDao.CloseStaleRecords(ctx)
+		if err != nil {
+			log.Error(ctx).Err(err).Stack().Msg("error while closing stale portfolio records")
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchPortfolio builds the portfolio of the client from its upswing net worth, a failed call is stored as the api error
+func (p *portfolioUpdateJob) fetchPortfolio(ctx c.Context, provider string, clientCode string, tracker *runTracker) (entity.PortfolioEntity, error) {
+	var totalInterestPercentage float64
+	var portfolioUpdateEntity entity.PortfolioEntity
+	var errRespMap map[string]interface{}
+	var isError bool
+	response, err := p.upswing.GetNetWorthData(ctx, clientCode)
+	if err != nil {
+		log.Error(ctx).Err(err).Stack().Msg("error from upswing API")
+		errResp := goerr.ListStacks(err)[2]
+		err = json.Unmarshal([]byte(errResp), &errRespMap)
 		if err != nil {
-			log.Error(ctx).Err(err).Stack().Msg("error while cleaning up stale portfolio records")
-			return
+			log.Error(ctx).Err(err).Stack().Msg("error unmarshalling upswing API error response JSON")
+			return portfolioUpdateEntity, err
 		}
+		isError = true
+	}
+	portfolioUpdateEntity.ClientCode = clientCode
+	portfolioUpdateEntity.Provider = provider
+	portfolioUpdateEntity.CreatedBy = "portfolio_update_job"
+	portfolioUpdateEntity.UpdatedBy = "portfolio_update_job"
+
+	if isError {
+		key := constants.ErrorCode
+		if _, exists := errRespMap[key]; exists {
+			if errRespMap[key] == constants.ErrClientNotFound {
+				portfolioUpdateEntity.InvalidClient = true
+			}
+			portfolioUpdateEntity.ApiError = errRespMap[key].(string)
+		}
+		portfolioUpdateEntity.TotalActiveDeposits = 0
+		portfolioUpdateEntity.InvestedValue = 0.0
+		portfolioUpdateEntity.CurrentValue = 0.0
+		portfolioUpdateEntity.InterestEarned = 0.0
+		portfolioUpdateEntity.ReturnsValue = 0.0
+		portfolioUpdateEntity.ReturnsPercentage = 0.0
+	} else {
+		if response.TotalInvestedAmount.Amount == 0.00 {
+			totalInterestPercentage = 0.00
+		} else {
+			totalInterestPercentage = (response.TotalInterestEarned.Amount / response.TotalInvestedAmount.Amount) * 100
+		}
+		portfolioUpdateEntity.TotalActiveDeposits = response.ActiveTermDepositCount
+		portfolioUpdateEntity.InvestedValue = response.TotalInvestedAmount.Amount
+		portfolioUpdateEntity.CurrentValue = response.CurrentAmount.Amount
+		portfolioUpdateEntity.InterestEarned = response.TotalInterestEarned.Amount
+		portfolioUpdateEntity.ReturnsValue = response.TotalInterestEarned.Amount
+		portfolioUpdateEntity.ReturnsPercentage = totalInterestPercentage
+	}
+
+	tracker.processed(isError, portfolioUpdateEntity.InvalidClient)
+	if isError && !portfolioUpdateEntity.InvalidClient {
+		tracker.failed(clientCode, portfolioUpdateEntity.ApiError)
 	}
+	return portfolioUpdateEntity, nil
 }
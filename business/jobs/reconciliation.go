@@ -0,0 +1,157 @@
+package jobs
+
+import (
+	c "context"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/fd-core/external"
+)
+
+const (
+	PortfolioReconciliationCron = "portfolioReconciliationCron"
+)
+
+// portfolioReconciliationJob compares the stored portfolio of the clients with the provider net worth and records the
+// drift as discrepancies, the webhook triggers and the portfolio update job both write the portfolio
+type portfolioReconciliationJob struct {
+	upswing           external.UpSwing
+	alertHook         external.AlertHook
+	reconciliationDAO dao.ReconciliationDAO
+	limiter           *tokenBucket
+}
+
+func newPortfolioReconciliationJob(upswing external.UpSwing, alertHook external.AlertHook, reconciliationDAO dao.ReconciliationDAO) *portfolioReconciliationJob {
+	return &portfolioReconciliationJob{upswing: upswing, alertHook: alertHook, reconciliationDAO: reconciliationDAO, limiter: getUpswingLimiter()}
+}
+
+func (p *portfolioReconciliationJob) Run() {
+	var ctx = context.Background(PortfolioReconciliationCron)
+	defer ctx.Done()
+
+	enabled := isJobEnabled(ctx, PortfolioReconciliationCron)
+	if !enabled {
+		log.Warn(ctx).Msg("portfolio reconciliation job is marked as disabled in config, skipping its execution")
+		return
+	}
+	log.Info(ctx).Msg("starting portfolio reconciliation job...")
+	p.execute(ctx)
+	log.Info(ctx).Msg("stopping portfolio reconciliation job...")
+}
+
+func (p *portfolioReconciliationJob) execute(ctx c.Context) {
+	reconciliation := config.App().Reconciliation
+	provider := getJobProvider(PortfolioReconciliationCron, reconciliation.Provider)
+	sampleSize := int(reconciliation.SampleSize)
+	minor := float64(reconciliation.MinorPercentage)
+	major := float64(reconciliation.MajorPercentage)
+
+	portfolios, err := p.reconciliationDAO.FetchPortfolios(ctx, provider, sampleSize)
+	if err != nil {
+		log.Error(ctx).Err(err).Stack().Msg("fetching portfolios for reconciliation failed")
+		return
+	}
+	run, err := p.reconciliationDAO.StartRun(ctx, provider)
+	if err != nil {
+		log.Error(ctx).Err(err).Stack().Msg("starting reconciliation run failed")
+		return
+	}
+
+	var discrepancies []entity.PortfolioDiscrepancyEntity
+	for _, portfolio := range portfolios {
+		if err := p.limiter.Wait(ctx); err != nil {
+			log.Warn(ctx).Err(err).Msg("portfolio reconciliation stopped")
+			break
+		}
+		response, err := p.upswing.GetNetWorthData(ctx, portfolio.ClientCode)
+		if err != nil {
+			log.Warn(ctx).Err(err).Msgf("net worth call failed, skipping reconciliation of clientCode: %s", portfolio.ClientCode)
+			run.ClientsFailed++
+			continue
+		}
+		run.ClientsCompared++
+
+		db := model.PortfolioValue{ActiveDeposits: portfolio.TotalActiveDeposits, InvestedValue: portfolio.InvestedValue, CurrentValue: portfolio.CurrentValue}
+		live := model.PortfolioValue{ActiveDeposits: response.ActiveTermDepositCount, InvestedValue: response.TotalInvestedAmount.Amount, CurrentValue: response.CurrentAmount.Amount}
+		severity, difference := discrepancySeverity(db, live, minor, major)
+		if severity == "" {
+			continue
+		}
+		discrepancies = append(discrepancies, entity.PortfolioDiscrepancyEntity{ClientCode: portfolio.ClientCode, Provider: provider, Severity: severity,
+			DBActiveDeposits: db.ActiveDeposits, ProviderActiveDeposits: live.ActiveDeposits, DBInvestedValue: db.InvestedValue, ProviderInvestedValue: live.InvestedValue,
+			DBCurrentValue: db.CurrentValue, ProviderCurrentValue: live.CurrentValue, DifferencePercentage: difference})
+	}
+
+	err = p.reconciliationDAO.FinishRun(ctx, run, discrepancies)
+	if err != nil {
+		log.Error(ctx).Err(err).Stack().Msgf("saving reconciliation run %d failed", run.ID)
+		return
+	}
+	log.Info(ctx).Msgf("reconciliation run %d complete; compared: %d; failed: %d; discrepancies: %d", run.ID, run.ClientsCompared, run.ClientsFailed, len(discrepancies))
+	p.alert(ctx, run, discrepancies)
+}
+
+// alert notifies the alert hook when the run found discrepancies of at least the configured severity
+func (p *portfolioReconciliationJob) alert(ctx c.Context, run entity.ReconciliationRunEntity, discrepancies []entity.PortfolioDiscrepancyEntity) {
+	alertSeverity := config.App().Reconciliation.AlertSeverity
+	threshold := slices.Index(constants.Severities, alertSeverity)
+	if threshold < 0 {
+		log.Warn(ctx).Msgf("unknown reconciliation alert severity: %s, alerting on %s", alertSeverity, constants.SeverityHigh)
+		threshold = slices.Index(constants.Severities, constants.SeverityHigh)
+	}
+
+	counts := make(map[string]int)
+	var alerting int
+	for _, discrepancy := range discrepancies {
+		counts[discrepancy.Severity]++
+		if slices.Index(constants.Severities, discrepancy.Severity) >= threshold {
+			alerting++
+		}
+	}
+	if alerting == 0 {
+		return
+	}
+
+	alert := model.Alert{Source: PortfolioReconciliationCron, Severity: alertSeverity,
+		Summary: fmt.Sprintf("portfolio reconciliation run %d of %s found %d discrepancies of severity %s or above", run.ID, run.Provider, alerting, alertSeverity),
+		Details: map[string]interface{}{"runId": run.ID, "clientsCompared": run.ClientsCompared, "clientsFailed": run.ClientsFailed, "bySeverity": counts}}
+	if err := p.alertHook.Notify(ctx, alert); err != nil {
+		log.Error(ctx).Err(err).Msgf("alerting reconciliation run %d failed", run.ID)
+	}
+}
+
+// discrepancySeverity grades the drift of the stored portfolio from the provider's. A different deposit count or a
+// value drift of major percent or more is high, minor percent or more is medium and any drift above a paisa is low.
+// Returns an empty severity when the values match, along with the largest value drift in percent.
+func discrepancySeverity(db model.PortfolioValue, provider model.PortfolioValue, minor float64, major float64) (string, float64) {
+	difference := math.Max(driftPercentage(db.InvestedValue, provider.InvestedValue), driftPercentage(db.CurrentValue, provider.CurrentValue))
+	difference = math.Round(difference*100) / 100
+
+	switch {
+	case db.ActiveDeposits != provider.ActiveDeposits || difference >= major:
+		return constants.SeverityHigh, difference
+	case difference >= minor:
+		return constants.SeverityMedium, difference
+	case math.Abs(db.InvestedValue-provider.InvestedValue) > 0.01 || math.Abs(db.CurrentValue-provider.CurrentValue) > 0.01:
+		return constants.SeverityLow, difference
+	}
+	return "", difference
+}
+
+func driftPercentage(value float64, expected float64) float64 {
+	if expected == 0 {
+		if math.Abs(value) > 0.01 {
+			return 100
+		}
+		return 0
+	}
+	return math.Abs(value-expected) / math.Abs(expected) * 100
+}
@@ -0,0 +1,34 @@
+package jobs
+
+import (
+	"testing"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscrepancySeverity(t *testing.T) {
+	provider := model.PortfolioValue{ActiveDeposits: 2, InvestedValue: 10000, CurrentValue: 10500}
+
+	severity, difference := discrepancySeverity(provider, provider, 1, 5)
+	assert.Empty(t, severity)
+	assert.Zero(t, difference)
+
+	severity, _ = discrepancySeverity(model.PortfolioValue{ActiveDeposits: 2, InvestedValue: 10000, CurrentValue: 10510}, provider, 1, 5)
+	assert.Equal(t, constants.SeverityLow, severity)
+
+	severity, difference = discrepancySeverity(model.PortfolioValue{ActiveDeposits: 2, InvestedValue: 10200, CurrentValue: 10500}, provider, 1, 5)
+	assert.Equal(t, constants.SeverityMedium, severity)
+	assert.Equal(t, 2.0, difference)
+
+	severity, _ = discrepancySeverity(model.PortfolioValue{ActiveDeposits: 2, InvestedValue: 20000, CurrentValue: 10500}, provider, 1, 5)
+	assert.Equal(t, constants.SeverityHigh, severity)
+
+	severity, _ = discrepancySeverity(model.PortfolioValue{ActiveDeposits: 3, InvestedValue: 10000, CurrentValue: 10500}, provider, 1, 5)
+	assert.Equal(t, constants.SeverityHigh, severity, "a different deposit count is always high")
+
+	severity, difference = discrepancySeverity(model.PortfolioValue{ActiveDeposits: 0, InvestedValue: 500}, model.PortfolioValue{}, 1, 5)
+	assert.Equal(t, constants.SeverityHigh, severity)
+	assert.Equal(t, 100.0, difference)
+}
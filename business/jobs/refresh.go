@@ -0,0 +1,89 @@
+package jobs
+
+import (
+	c "context"
+	"fmt"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/goerr"
+)
+
+const clientRefreshUpdatedBy = "client_refresh_worker"
+
+// RefreshClient refreshes the portfolio and pending journey of one client the way the sweeps do, and clears their
+// to_be_refreshed flags. Only the tables the client has a row in are refreshed, the webhook triggers add the rows of the
+// tables an event touches. Unlike a sweep it fails when upswing did not answer, so that the stored values are kept.
+func (j *Jobs) RefreshClient(ctx c.Context, provider string, clientCode string) error {
+	hasPortfolio, err := j.portfolio.portfolioDao.HasClient(ctx, clientCode, provider)
+	if err != nil {
+		return err
+	}
+	hasPendingJourney, err := j.pendingJourney.pendingJourneyDao.HasClient(ctx, clientCode, provider)
+	if err != nil {
+		return err
+	}
+	if hasPortfolio {
+		if err := j.refreshPortfolio(ctx, provider, clientCode); err != nil {
+			return err
+		}
+	}
+	if hasPendingJourney {
+		if err := j.refreshPendingJourney(ctx, provider, clientCode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (j *Jobs) refreshPortfolio(ctx c.Context, provider string, clientCode string) error {
+	portfolioJob := j.portfolio
+	if err := portfolioJob.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	tracker := &runTracker{}
+	portfolio, err := portfolioJob.fetchPortfolio(ctx, provider, clientCode, tracker)
+	if err != nil {
+		return err
+	}
+	if run := tracker.snapshot(); run.UpstreamErrors > run.InvalidClients {
+		return goerr.New(nil, fmt.Sprintf("jobs: net worth call failed for client refresh: %s", portfolio.ApiError))
+	}
+
+	portfolio.UpdatedBy = clientRefreshUpdatedBy
+	portfolios := []entity.PortfolioEntity{portfolio}
+	if err := portfolioJob.scheduleRefresh(ctx, provider, portfolios, time.Now()); err != nil {
+		return err
+	}
+	if err := portfolioJob.portfolioDao.BatchUpdatePortfolio(ctx, portfolios); err != nil {
+		return goerr.New(err, "jobs: storing refreshed portfolio failed")
+	}
+	if err := portfolioJob.portfolioDao.UpdateRefreshedPortfolioClientList(ctx, provider, []string{clientCode}); err != nil {
+		return goerr.New(err, "jobs: clearing portfolio refresh flag failed")
+	}
+	return nil
+}
+
+func (j *Jobs) refreshPendingJourney(ctx c.Context, provider string, clientCode string) error {
+	pendingJourneyJob := j.pendingJourney
+	if err := pendingJourneyJob.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	tracker := &runTracker{}
+	pendingJourney, err := pendingJourneyJob.fetchPendingJourney(ctx, provider, clientCode, tracker)
+	if err != nil {
+		return err
+	}
+	if run := tracker.snapshot(); run.UpstreamErrors > run.InvalidClients {
+		return goerr.New(nil, fmt.Sprintf("jobs: pending journey call failed for client refresh: %s", pendingJourney.ApiError))
+	}
+
+	pendingJourney.UpdatedBy = clientRefreshUpdatedBy
+	if err := pendingJourneyJob.pendingJourneyDao.BatchUpdatePendingJourney(ctx, []entity.PendingJourneyEntity{pendingJourney}); err != nil {
+		return goerr.New(err, "jobs: storing refreshed pending journey failed")
+	}
+	if err := pendingJourneyJob.pendingJourneyDao.UpdateRefreshedPendingJourneyClientList(ctx, provider, []string{clientCode}); err != nil {
+		return goerr.New(err, "jobs: clearing pending journey refresh flag failed")
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package jobs
+
+import (
+	c "context"
+	"hash/fnv"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/goerr"
+)
+
+// refreshPolicy decides when the portfolio sweep refreshes a client next. Clients with recent webhook events, a
+// deposit maturing soon, or a failed refresh come back every active interval, dormant clients about every dormant
+// interval.
+type refreshPolicy struct {
+	activeWindow    time.Duration
+	activeInterval  time.Duration
+	maturityWindow  time.Duration
+	dormantInterval time.Duration
+}
+
+func getRefreshPolicy() refreshPolicy {
+	portfolio := config.App().Portfolio
+	return refreshPolicy{
+		activeWindow:    time.Duration(portfolio.RefreshActiveWindowDays) * 24 * time.Hour,
+		activeInterval:  time.Duration(portfolio.RefreshActiveIntervalHours) * time.Hour,
+		maturityWindow:  time.Duration(portfolio.RefreshMaturityWindowDays) * 24 * time.Hour,
+		dormantInterval: time.Duration(portfolio.RefreshDormantIntervalHours) * time.Hour,
+	}
+}
+
+// getPortfolioMaxStale returns how long a client goes without a refresh at most, whatever its schedule
+func getPortfolioMaxStale() time.Duration {
+	return time.Duration(config.App().Portfolio.RefreshMaxStaleHours) * time.Hour
+}
+
+// nextRefreshAt returns when the client of the refreshed portfolio is due again. A deposit maturing before then brings
+// the refresh forward to its maturity.
+func (r refreshPolicy) nextRefreshAt(now time.Time, portfolio entity.PortfolioEntity, signal entity.RefreshSignalEntity) time.Time {
+	next := now.Add(r.dormantSpread(portfolio.ClientCode))
+	switch {
+	case portfolio.ApiError != "" && !portfolio.InvalidClient:
+		next = now.Add(r.activeInterval)
+	case signal.LastEventAt != nil && now.Sub(*signal.LastEventAt) <= r.activeWindow:
+		next = now.Add(r.activeInterval)
+	case signal.NextMaturityAt != nil && signal.NextMaturityAt.Sub(now) <= r.maturityWindow:
+		next = now.Add(r.activeInterval)
+	}
+	if signal.NextMaturityAt != nil && signal.NextMaturityAt.After(now) && signal.NextMaturityAt.Before(next) {
+		next = *signal.NextMaturityAt
+	}
+	return next
+}
+
+// dormantSpread returns the interval of a dormant client, between half and the whole dormant interval by its client
+// code, so the dormant clients refreshed together once do not stay due on the same day
+func (r refreshPolicy) dormantSpread(clientCode string) time.Duration {
+	half := r.dormantInterval / 2
+	hours := int64(half / time.Hour)
+	if hours <= 0 {
+		return r.dormantInterval
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(clientCode))
+	return half + time.Duration(int64(hash.Sum32())%(hours+1))*time.Hour
+}
+
+// scheduleRefresh sets the next refresh of the portfolios of the batch, refreshed at refreshedAt, from the webhook
+// activity of their clients
+func (p *portfolioUpdateJob) scheduleRefresh(ctx c.Context, provider string, batch []entity.PortfolioEntity, refreshedAt time.Time) error {
+	clientList := make([]string, len(batch))
+	for i := range batch {
+		clientList[i] = batch[i].ClientCode
+	}
+	signals, err := p.portfolioDao.FetchRefreshSignals(ctx, provider, clientList)
+	if err != nil {
+		return goerr.New(err, "jobs: fetching refresh signals failed")
+	}
+	policy := getRefreshPolicy()
+	for i := range batch {
+		next := policy.nextRefreshAt(refreshedAt, batch[i], signals[batch[i].ClientCode])
+		batch[i].NextRefreshAt = &next
+	}
+	return nil
+}
+
+// sweepStartedAt returns when the run of the sweep started. The clients of a sweep are refreshed as of its start, so a
+// client reached late in a long sweep is still due by the start of the run an interval later.
+func sweepStartedAt(tracker *runTracker) time.Time {
+	if startedAt := tracker.snapshot().StartedAt; !startedAt.IsZero() {
+		return startedAt
+	}
+	return time.Now()
+}
@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextRefreshAt(t *testing.T) {
+	policy := refreshPolicy{activeWindow: 7 * 24 * time.Hour, activeInterval: 24 * time.Hour, maturityWindow: 7 * 24 * time.Hour, dormantInterval: 168 * time.Hour}
+	now := time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+	portfolio := entity.PortfolioEntity{ClientCode: "S1614297"}
+
+	// dormant clients come back within the dormant interval, always after the same spread
+	dormant := policy.nextRefreshAt(now, portfolio, entity.RefreshSignalEntity{LastEventAt: at(-30 * 24 * time.Hour)})
+	assert.True(t, !dormant.Before(now.Add(84*time.Hour)) && !dormant.After(now.Add(168*time.Hour)))
+	assert.Equal(t, dormant, policy.nextRefreshAt(now, portfolio, entity.RefreshSignalEntity{}))
+
+	// recent events, a maturity in the window and failed refreshes come back after the active interval
+	assert.Equal(t, now.Add(24*time.Hour), policy.nextRefreshAt(now, portfolio, entity.RefreshSignalEntity{LastEventAt: at(-2 * 24 * time.Hour)}))
+	assert.Equal(t, now.Add(24*time.Hour), policy.nextRefreshAt(now, portfolio, entity.RefreshSignalEntity{NextMaturityAt: at(5 * 24 * time.Hour)}))
+	assert.Equal(t, now.Add(24*time.Hour), policy.nextRefreshAt(now, entity.PortfolioEntity{ClientCode: "S1614297", ApiError: "INTERNAL_SERVER_ERROR"}, entity.RefreshSignalEntity{}))
+
+	// a maturity before the next refresh brings it forward
+	assert.Equal(t, now.Add(6*time.Hour), policy.nextRefreshAt(now, portfolio, entity.RefreshSignalEntity{NextMaturityAt: at(6 * time.Hour)}))
+	assert.Equal(t, now.Add(24*time.Hour), policy.nextRefreshAt(now, portfolio, entity.RefreshSignalEntity{LastEventAt: at(-time.Hour), NextMaturityAt: at(30 * 24 * time.Hour)}))
+
+	// an invalid client is dormant, the revalidation job takes care of it
+	assert.Equal(t, dormant, policy.nextRefreshAt(now, entity.PortfolioEntity{ClientCode: "S1614297", InvalidClient: true, ApiError: "CLIENT_NOT_FOUND"}, entity.RefreshSignalEntity{}))
+}
+
+func TestDormantSpread(t *testing.T) {
+	policy := refreshPolicy{dormantInterval: 168 * time.Hour}
+	spreads := map[time.Duration]bool{}
+	for _, clientCode := range []string{"A1", "B2", "C3", "D4", "E5", "F6", "G7", "H8"} {
+		spread := policy.dormantSpread(clientCode)
+		assert.True(t, spread >= 84*time.Hour && spread <= 168*time.Hour)
+		spreads[spread] = true
+	}
+	assert.Greater(t, len(spreads), 1)
+	assert.Equal(t, time.Hour, refreshPolicy{dormantInterval: time.Hour}.dormantSpread("A1"))
+}
+
+func TestSweepStartedAt(t *testing.T) {
+	startedAt := time.Date(2026, 10, 19, 2, 0, 0, 0, time.UTC)
+	assert.Equal(t, startedAt, sweepStartedAt(&runTracker{run: entity.JobRunEntity{StartedAt: startedAt}}))
+	// a run that could not be recorded starts now
+	assert.WithinDuration(t, time.Now(), sweepStartedAt(&runTracker{}), time.Second)
+}
@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	c "context"
+	"testing"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/external"
+	"github.com/stretchr/testify/assert"
+)
+
+// the embedded interfaces panic on the calls the refresh must not make
+type fakeRefreshUpSwing struct {
+	external.UpSwing
+	netWorthCalls       int
+	pendingJourneyCalls int
+}
+
+func (f *fakeRefreshUpSwing) GetNetWorthData(ctx c.Context, clientCode string) (*model.NetWorthResponse, error) {
+	f.netWorthCalls++
+	return &model.NetWorthResponse{}, nil
+}
+
+func (f *fakeRefreshUpSwing) GetPendingJourneyData(ctx c.Context, clientCode string) (*model.PendingJourneyResponse, error) {
+	f.pendingJourneyCalls++
+	return &model.PendingJourneyResponse{JourneyPending: true, JourneyPendingOnPayment: true}, nil
+}
+
+type fakeRefreshPortfolioDAO struct {
+	dao.PortfolioDAO
+	has    bool
+	stored []entity.PortfolioEntity
+}
+
+func (f *fakeRefreshPortfolioDAO) HasClient(ctx c.Context, clientCode string, provider string) (bool, error) {
+	return f.has, nil
+}
+
+func (f *fakeRefreshPortfolioDAO) FetchRefreshSignals(ctx c.Context, provider string, clientList []string) (map[string]entity.RefreshSignalEntity, error) {
+	return nil, nil
+}
+
+func (f *fakeRefreshPortfolioDAO) BatchUpdatePortfolio(ctx c.Context, portfolios []entity.PortfolioEntity) error {
+	f.stored = append(f.stored, portfolios...)
+	return nil
+}
+
+func (f *fakeRefreshPortfolioDAO) UpdateRefreshedPortfolioClientList(ctx c.Context, provider string, clientList []string) error {
+	return nil
+}
+
+type fakeRefreshPendingJourneyDAO struct {
+	dao.PendingJourneyDAO
+	has    bool
+	stored []entity.PendingJourneyEntity
+}
+
+func (f *fakeRefreshPendingJourneyDAO) HasClient(ctx c.Context, clientCode string, provider string) (bool, error) {
+	return f.has, nil
+}
+
+func (f *fakeRefreshPendingJourneyDAO) BatchUpdatePendingJourney(ctx c.Context, pendingJourneys []entity.PendingJourneyEntity) error {
+	f.stored = append(f.stored, pendingJourneys...)
+	return nil
+}
+
+func (f *fakeRefreshPendingJourneyDAO) UpdateRefreshedPendingJourneyClientList(ctx c.Context, provider string, clientList []string) error {
+	return nil
+}
+
+func TestRefreshClientOnlyRefreshesItsTables(t *testing.T) {
+	tests := []struct {
+		name              string
+		hasPortfolio      bool
+		hasPendingJourney bool
+	}{
+		{"pending journey only", false, true},
+		{"portfolio only", true, false},
+		{"both", true, true},
+		{"neither", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upswing := &fakeRefreshUpSwing{}
+			portfolioDAO := &fakeRefreshPortfolioDAO{has: tt.hasPortfolio}
+			pendingJourneyDAO := &fakeRefreshPendingJourneyDAO{has: tt.hasPendingJourney}
+			jobs := NewJobs(Dependencies{UpSwing: upswing, PortfolioDAO: portfolioDAO, PendingJourneyDAO: pendingJourneyDAO})
+
+			assert.NoError(t, jobs.RefreshClient(c.Background(), "upswing", "S1614297"))
+			if tt.hasPortfolio {
+				assert.Equal(t, 1, upswing.netWorthCalls)
+				assert.Len(t, portfolioDAO.stored, 1)
+			} else {
+				assert.Zero(t, upswing.netWorthCalls, "a client without a portfolio must not get one")
+				assert.Empty(t, portfolioDAO.stored)
+			}
+			if tt.hasPendingJourney {
+				assert.Equal(t, 1, upswing.pendingJourneyCalls)
+				if assert.Len(t, pendingJourneyDAO.stored, 1) {
+					assert.True(t, pendingJourneyDAO.stored[0].Pending)
+				}
+			} else {
+				assert.Zero(t, upswing.pendingJourneyCalls, "a client without a pending journey must not get one")
+				assert.Empty(t, pendingJourneyDAO.stored)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package jobs
+
+import (
+	c "context"
+	"fmt"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/fd-core/external"
+)
+
+const (
+	InvalidClientRevalidationCron = "invalidClientRevalidationCron"
+
+	revalidationChangedBy = "invalid_client_revalidation_job"
+)
+
+// invalidClientRevalidationJob checks the clients marked invalid with upswing again, the client list of the sweeps
+// leaves them out. A registered client is cleared, a client still unknown is checked again after a growing backoff.
+type invalidClientRevalidationJob struct {
+	upswing          external.UpSwing
+	invalidClientDao dao.InvalidClientDAO
+	jobRunDao        dao.JobRunDAO
+	limiter          *tokenBucket
+}
+
+func newInvalidClientRevalidationJob(upswing external.UpSwing, invalidClientDao dao.InvalidClientDAO, jobRunDao dao.JobRunDAO) *invalidClientRevalidationJob {
+	return &invalidClientRevalidationJob{upswing: upswing, invalidClientDao: invalidClientDao, jobRunDao: jobRunDao, limiter: getUpswingLimiter()}
+}
+
+func (r *invalidClientRevalidationJob) Run() {
+	var ctx = context.Background(InvalidClientRevalidationCron)
+	defer ctx.Done()
+
+	enabled := isJobEnabled(ctx, InvalidClientRevalidationCron)
+	if !enabled {
+		log.Warn(ctx).Msg("invalid client revalidation job is marked as disabled in config, skipping its execution")
+		return
+	}
+	runJob(ctx, r.jobRunDao, InvalidClientRevalidationCron, true, r)
+}
+
+func (r *invalidClientRevalidationJob) execute(ctx c.Context, refresher string, tracker *runTracker) error {
+	log.Info(ctx).Msg("starting invalid client revalidation job...")
+	defer log.Info(ctx).Msg("stopping invalid client revalidation job...")
+
+	provider := getJobProvider(InvalidClientRevalidationCron, config.App().Portfolio.Provider)
+	limit := int(getJobBatchSize(InvalidClientRevalidationCron, config.App().Revalidation.Limit))
+	backoff, maxBackoff := GetRevalidationBackoff()
+
+	clientList, err := runClients(ctx, r.jobRunDao, tracker, func() ([]string, error) {
+		return r.invalidClientDao.FetchDueClients(ctx, provider, limit)
+	})
+	if err != nil {
+		log.Error(ctx).Err(err).Stack().Msg("fetching invalid clients for revalidation failed")
+		return err
+	}
+
+	tracker.setTotal(len(clientList))
+	for _, clientCode := range clientList {
+		if err := r.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		start := time.Now()
+		_, err := r.upswing.GetNetWorthData(ctx, clientCode)
+		tracker.observe(time.Since(start))
+
+		switch revalidationOutcome(clientCode, err) {
+		case constants.RegistrationRegistered:
+			change := entity.InvalidClientAuditEntity{ClientCode: clientCode, Provider: provider, Invalid: false, Reason: "revalidation: registered at provider", ChangedBy: revalidationChangedBy}
+			scopes, err := r.invalidClientDao.SetInvalid(ctx, change, constants.InvalidClientScopes, nil)
+			if err != nil {
+				log.Error(ctx).Err(err).Stack().Msgf("clearing invalid flag failed for clientCode: %s", clientCode)
+				return err
+			}
+			log.Info(ctx).Msgf("client %s is registered again, cleared invalid flag in %v", clientCode, scopes)
+			tracker.processed(false, false)
+		case constants.RegistrationNotRegistered:
+			if err := r.invalidClientDao.DeferRevalidation(ctx, provider, clientCode, backoff, maxBackoff); err != nil {
+				log.Error(ctx).Err(err).Stack().Msgf("deferring revalidation failed for clientCode: %s", clientCode)
+				return err
+			}
+			tracker.processed(true, true)
+		default:
+			// the provider did not answer, the client stays due for the next run
+			log.Warn(ctx).Err(err).Msgf("revalidation call failed for clientCode: %s", clientCode)
+			tracker.processed(true, false)
+			tracker.failed(clientCode, external.UpSwingErrorCode(err))
+		}
+		tracker.checkpoint(clientCode)
+	}
+	return nil
+}
+
+// revalidationOutcome tells from the error of the net worth call whether the client is registered at upswing, not
+// registered, or unknown when upswing did not answer
+func revalidationOutcome(clientCode string, err error) string {
+	if err == nil {
+		return constants.RegistrationRegistered
+	}
+	errorCode := external.UpSwingErrorCode(err)
+	if errorCode == constants.ErrClientNotFound || errorCode == fmt.Sprintf(constants.ErrPciNotFound, clientCode) {
+		return constants.RegistrationNotRegistered
+	}
+	return constants.RegistrationUnknown
+}
+
+// GetRevalidationBackoff returns the wait after the first attempt finding a client invalid and the longest wait
+func GetRevalidationBackoff() (time.Duration, time.Duration) {
+	revalidation := config.App().Revalidation
+	return time.Duration(revalidation.BackoffHours) * time.Hour, time.Duration(revalidation.MaxBackoffHours) * time.Hour
+}
@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/goerr"
+	"github.com/stretchr/testify/assert"
+)
+
+// upswingError wraps the error response body the way the upswing client does
+func upswingError(errorCode string) error {
+	body := errors.New(fmt.Sprintf(`{"%s":"%s"}`, constants.ErrorCode, errorCode))
+	return goerr.New(goerr.New(body, "http call failed"), "external failed : networth api call failed with upswing")
+}
+
+func TestRevalidationOutcome(t *testing.T) {
+	assert.Equal(t, constants.RegistrationRegistered, revalidationOutcome("S1614297", nil))
+	assert.Equal(t, constants.RegistrationNotRegistered, revalidationOutcome("S1614297", upswingError(constants.ErrClientNotFound)))
+	assert.Equal(t, constants.RegistrationNotRegistered, revalidationOutcome("S1614297", upswingError(fmt.Sprintf(constants.ErrPciNotFound, "S1614297"))))
+	assert.Equal(t, constants.RegistrationUnknown, revalidationOutcome("S1614297", upswingError("INTERNAL_SERVER_ERROR")))
+	assert.Equal(t, constants.RegistrationUnknown, revalidationOutcome("S1614297", goerr.New(errors.New("connection refused"), "external failed")))
+}
@@ -0,0 +1,441 @@
+package jobs
+
+import (
+	c "context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/goerr"
+)
+
+// trackedJob is a job whose runs are recorded in job_runs, execute reports its progress on the tracker and must stop
+// once ctx is cancelled
+type trackedJob interface {
+	execute(ctx c.Context, refresher string, tracker *runTracker) error
+}
+
+// runTracker holds the counters of a run, the job updates them while the heartbeat reads them. Without a heartbeat
+// interval the progress is only stored at the end of the run.
+type runTracker struct {
+	mu        sync.Mutex
+	run       entity.JobRunEntity
+	heartbeat time.Duration
+	started   time.Time
+	latencies []time.Duration
+	// failures not stored yet
+	failures []entity.JobRunFailureEntity
+	// clients given on the command line, processed instead of the clients of the job
+	clients []string
+}
+
+func (t *runTracker) setTotal(total int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.run.ClientsTotal = total
+}
+
+// processed counts a client, upstreamErr tells whether the provider call failed and invalid whether it reported the client unknown
+func (t *runTracker) processed(upstreamErr bool, invalid bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.run.ClientsProcessed++
+	if upstreamErr {
+		t.run.UpstreamErrors++
+	} else {
+		t.run.Successes++
+	}
+	if invalid {
+		t.run.InvalidClients++
+	}
+}
+
+// failed records a client whose upstream call failed, to be processed again by a retryFailed run
+func (t *runTracker) failed(clientCode string, apiError string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.run.ID == 0 {
+		return
+	}
+	t.failures = append(t.failures, entity.JobRunFailureEntity{RunID: t.run.ID, ClientCode: clientCode, ApiError: apiError})
+}
+
+// checkpoint moves the cursor of the run to the last client whose result is stored, clients are stored in order
+func (t *runTracker) checkpoint(clientCode string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.run.Cursor = clientCode
+}
+
+// takeFailures returns the failures not stored yet, putBackFailures returns them when storing failed
+func (t *runTracker) takeFailures() []entity.JobRunFailureEntity {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	failures := t.failures
+	t.failures = nil
+	return failures
+}
+
+func (t *runTracker) putBackFailures(failures []entity.JobRunFailureEntity) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.failures = append(failures, t.failures...)
+}
+
+// observe records the latency of a provider call
+func (t *runTracker) observe(latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.latencies = append(t.latencies, latency)
+}
+
+// measure sets the throughput of the run since it started and the average and p95 latency of its provider calls
+func (t *runTracker) measure(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if elapsed := now.Sub(t.started).Seconds(); elapsed > 0 {
+		t.run.ThroughputPerSecond = float64(t.run.ClientsProcessed) / elapsed
+	}
+	if len(t.latencies) == 0 {
+		return
+	}
+	sorted := make([]time.Duration, len(t.latencies))
+	copy(sorted, t.latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	var total time.Duration
+	for _, latency := range sorted {
+		total += latency
+	}
+	t.run.LatencyAvgMs = float64(total.Microseconds()) / float64(len(sorted)) / 1000
+	t.run.LatencyP95Ms = float64(sorted[(len(sorted)*95+99)/100-1].Microseconds()) / 1000
+}
+
+func (t *runTracker) snapshot() entity.JobRunEntity {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.run
+}
+
+// runs of this instance by id, to cancel them without waiting for the heartbeat. The runs not recorded are kept by their
+// tracker.
+var activeRuns sync.Map
+
+// ErrJobRunning is returned when a run of the job is already in progress
+var ErrJobRunning = errors.New("job is already running")
+
+// triggerableJobs are the jobs that can be run on demand
+func (j *Jobs) triggerableJobs() map[string]trackedJob {
+	return map[string]trackedJob{
+		PortfolioUpdateCron:           j.portfolio,
+		PendingJourneyUpdateCron:      j.pendingJourney,
+		InvalidClientRevalidationCron: j.revalidation,
+	}
+}
+
+// Trigger records a run of the job and executes it in the background, the returned run is already recorded as running.
+// A resume run continues an interrupted run, by default the latest one, and a retryFailed run processes the failed clients
+// of a run. Fails with ErrJobRunning when a run of the job is in progress on any instance.
+func (j *Jobs) Trigger(ctx c.Context, name string, request model.JobTriggerRequest, requestedBy string) (entity.JobRunEntity, error) {
+	if stopping.Load() {
+		return entity.JobRunEntity{}, goerr.New(nil, http.StatusServiceUnavailable, "instance is shutting down")
+	}
+	job, ok := j.triggerableJobs()[name]
+	if !ok {
+		return entity.JobRunEntity{}, goerr.New(nil, http.StatusNotFound, fmt.Sprintf("job %s can not be triggered", name))
+	}
+	runDAO := j.jobRunDAO
+	run := entity.JobRunEntity{JobName: name, Trigger: constants.JobTriggerAPI, Refresher: request.Refresher, RequestedBy: requestedBy, Exclusive: true, Mode: constants.JobRunModeFull}
+	if request.Mode != "" && request.Mode != constants.JobRunModeFull {
+		source, err := fetchSourceRun(ctx, runDAO, name, request)
+		if err != nil {
+			return entity.JobRunEntity{}, err
+		}
+		if run, err = sourcedRun(run, request.Mode, source); err != nil {
+			return entity.JobRunEntity{}, err
+		}
+	}
+	tracker, started, err := startRun(ctx, runDAO, run)
+	if err != nil {
+		return entity.JobRunEntity{}, err
+	}
+	if !started {
+		return entity.JobRunEntity{}, goerr.New(ErrJobRunning, http.StatusConflict, fmt.Sprintf("job %s is already running", name))
+	}
+
+	// the run outlives the request that triggered it
+	runCtx := context.Background(name)
+	triggeredRuns.Add(1)
+	go func() {
+		defer triggeredRuns.Done()
+		tracker.execute(runCtx, runDAO, job, run.Refresher)
+	}()
+	return tracker.snapshot(), nil
+}
+
+// fetchSourceRun returns the run given in the request, a resume without a run continues the latest interrupted run
+// however long ago it was interrupted
+func fetchSourceRun(ctx c.Context, runDAO dao.JobRunDAO, name string, request model.JobTriggerRequest) (*entity.JobRunEntity, error) {
+	if request.RunID == 0 && request.Mode == constants.JobRunModeResume {
+		source, err := runDAO.FetchResumableRun(ctx, name, getRunStaleAfter(), nil)
+		if err != nil {
+			return nil, goerr.New(err, fmt.Sprintf("jobs: fetching resumable run of job %s failed", name))
+		}
+		if source == nil {
+			return nil, goerr.New(nil, http.StatusNotFound, fmt.Sprintf("job %s has no interrupted run to resume", name))
+		}
+		return source, nil
+	}
+	if request.RunID == 0 {
+		return nil, goerr.New(nil, http.StatusBadRequest, fmt.Sprintf("runId is required for mode %s", request.Mode))
+	}
+	source, err := runDAO.FetchRun(ctx, request.RunID)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("jobs: fetching job run %d failed", request.RunID))
+	}
+	if source == nil || source.JobName != name {
+		return nil, goerr.New(nil, http.StatusNotFound, fmt.Sprintf("job %s has no run %d", name, request.RunID))
+	}
+	return source, nil
+}
+
+// sourcedRun sets the run up to resume source after its cursor or to retry the failed clients of source, the run keeps
+// the refresher of source
+func sourcedRun(run entity.JobRunEntity, mode string, source *entity.JobRunEntity) (entity.JobRunEntity, error) {
+	switch mode {
+	case constants.JobRunModeResume:
+		if source.Mode == constants.JobRunModeRetryFailed {
+			return run, goerr.New(nil, http.StatusBadRequest, fmt.Sprintf("run %d retried failed clients and can not be resumed, retry its failed clients instead", source.ID))
+		}
+		if source.Status == constants.JobRunSucceeded {
+			return run, goerr.New(nil, http.StatusBadRequest, fmt.Sprintf("run %d completed and can not be resumed", source.ID))
+		}
+		run.Cursor = source.Cursor
+	case constants.JobRunModeRetryFailed:
+		if source.Status == constants.JobRunRunning {
+			return run, goerr.New(nil, http.StatusConflict, fmt.Sprintf("run %d is still running", source.ID))
+		}
+	default:
+		return run, goerr.New(nil, http.StatusBadRequest, fmt.Sprintf("unknown job run mode: %s", mode))
+	}
+	run.Mode = mode
+	run.SourceRunID = source.ID
+	run.Refresher = source.Refresher
+	return run, nil
+}
+
+// runClients returns the clients the run processes in client code order, after the cursor of the run. A retryFailed
+// run processes the failed clients of its source run, a run of the command line its given clients, any other run the
+// clients of fetch.
+func runClients(ctx c.Context, runDAO dao.JobRunDAO, tracker *runTracker, fetch func() ([]string, error)) ([]string, error) {
+	run := tracker.snapshot()
+	var clients []string
+	var err error
+	if run.Mode == constants.JobRunModeRetryFailed {
+		clients, err = runDAO.FetchFailedClients(ctx, run.SourceRunID)
+	} else if len(tracker.clients) > 0 {
+		clients = slices.Clone(tracker.clients)
+	} else {
+		clients, err = fetch()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(clients)
+	if run.Cursor != "" {
+		resumeAt := sort.SearchStrings(clients, run.Cursor)
+		if resumeAt < len(clients) && clients[resumeAt] == run.Cursor {
+			resumeAt++
+		}
+		log.Info(ctx).Msgf("run %d resumes after client %s, skipping %d clients", run.ID, run.Cursor, resumeAt)
+		clients = clients[resumeAt:]
+	}
+	return clients, nil
+}
+
+// CancelRun requests the cancellation of a running run, false when it is not running. A run of this instance is cancelled
+// right away, a run of another instance on its next heartbeat.
+func (j *Jobs) CancelRun(ctx c.Context, id int64) (bool, error) {
+	requested, err := j.jobRunDAO.RequestCancel(ctx, id)
+	if err != nil || !requested {
+		return false, err
+	}
+	if cancel, ok := activeRuns.Load(id); ok {
+		cancel.(c.CancelFunc)()
+	}
+	return true, nil
+}
+
+// runJob records and executes a scheduled run. The job runs even when the run cannot be recorded, and is skipped when a
+// run of an exclusive job is already in progress. A run of an exclusive job resumes the previous run when it was
+// interrupted midway in the current schedule period, an older run is only resumed through the API.
+func runJob(ctx c.Context, runDAO dao.JobRunDAO, name string, exclusive bool, job trackedJob) {
+	if stopping.Load() {
+		log.Warn(ctx).Msgf("instance is shutting down, skipping run of job %s", name)
+		return
+	}
+	run := entity.JobRunEntity{JobName: name, Trigger: constants.JobTriggerCron, Exclusive: exclusive, Mode: constants.JobRunModeFull}
+	if exclusive {
+		run = resumeInterrupted(ctx, runDAO, run)
+	}
+	tracker, started, err := startRun(ctx, runDAO, run)
+	if err != nil {
+		log.Error(ctx).Err(err).Msgf("recording run of job %s failed", name)
+		tracker = &runTracker{run: run}
+	} else if !started {
+		log.Warn(ctx).Msgf("job %s is already running, skipping this run", name)
+		return
+	}
+	tracker.execute(ctx, runDAO, job, tracker.snapshot().Refresher)
+}
+
+// resumeInterrupted sets the run up to resume the run of the job interrupted in the current schedule period, if any
+func resumeInterrupted(ctx c.Context, runDAO dao.JobRunDAO, run entity.JobRunEntity) entity.JobRunEntity {
+	startedAfter, ok := resumableSince(run.JobName, time.Now())
+	if !ok {
+		return run
+	}
+	source, err := runDAO.FetchResumableRun(ctx, run.JobName, getRunStaleAfter(), &startedAfter)
+	if err != nil {
+		log.Warn(ctx).Err(err).Msgf("fetching resumable run of job %s failed, starting a full run", run.JobName)
+		return run
+	}
+	if source == nil {
+		return run
+	}
+	log.Info(ctx).Msgf("resuming interrupted run %d of job %s after client %s", source.ID, run.JobName, source.Cursor)
+	resumed, _ := sourcedRun(run, constants.JobRunModeResume, source)
+	return resumed
+}
+
+// resumableSince returns the start of the schedule period of the job: since its previous scheduled run, with half a
+// period of tolerance for the delay of that run. False when the job has no valid schedule.
+func resumableSince(name string, now time.Time) (time.Time, bool) {
+	period := schedulePeriod(name, now)
+	if period <= 0 {
+		return time.Time{}, false
+	}
+	return now.Add(-period - period/2), true
+}
+
+func startRun(ctx c.Context, runDAO dao.JobRunDAO, run entity.JobRunEntity) (*runTracker, bool, error) {
+	run.Instance = instanceID
+	run, started, err := runDAO.StartRun(ctx, run, getRunStaleAfter())
+	if err != nil {
+		return nil, false, goerr.New(err, fmt.Sprintf("jobs: starting run of job %s failed", run.JobName))
+	}
+	run.Status = constants.JobRunRunning
+	return &runTracker{run: run, heartbeat: getRunHeartbeatInterval()}, started, nil
+}
+
+// execute runs the job with a heartbeat storing its progress, and records how the run ended
+func (t *runTracker) execute(ctx c.Context, runDAO dao.JobRunDAO, job trackedJob, refresher string) {
+	ctx, cancel := c.WithCancel(ctx)
+	defer cancel()
+
+	t.mu.Lock()
+	t.started = time.Now()
+	t.mu.Unlock()
+	id := t.snapshot().ID
+	// a run that could not be recorded is registered by its tracker, so that the shutdown cancels it too
+	var key any = t
+	if id != 0 {
+		key = id
+	}
+	activeRuns.Store(key, cancel)
+	defer activeRuns.Delete(key)
+	// the shutdown may have cancelled the active runs between the check of the caller and the registration
+	if stopping.Load() {
+		cancel()
+	}
+	// the heartbeat returns before the end is recorded, so that a progress it is storing can not overwrite the end
+	stopped := make(chan struct{})
+	beaten := make(chan struct{})
+	if id != 0 && t.heartbeat > 0 {
+		go func() {
+			defer close(beaten)
+			t.beat(ctx, runDAO, cancel, stopped)
+		}()
+	} else {
+		close(beaten)
+	}
+
+	err := job.execute(ctx, refresher, t)
+	close(stopped)
+	<-beaten
+
+	t.measure(time.Now())
+	t.mu.Lock()
+	switch {
+	case err == nil:
+		t.run.Status = constants.JobRunSucceeded
+	case errors.Is(ctx.Err(), c.Canceled) && stopping.Load():
+		// recorded as failed, so that the next run of the job resumes it after its cursor
+		t.run.Status = constants.JobRunFailed
+		t.run.Error = fmt.Sprintf("interrupted by shutdown: %s", err.Error())
+	case errors.Is(ctx.Err(), c.Canceled):
+		t.run.Status = constants.JobRunCancelled
+		t.run.Error = err.Error()
+	default:
+		t.run.Status = constants.JobRunFailed
+		t.run.Error = err.Error()
+	}
+	t.mu.Unlock()
+
+	run := t.snapshot()
+	log.Info(ctx).Msgf("run %d of job %s %s; processed: %d/%d, throughput: %.2f/s, latency avg: %.1fms p95: %.1fms", run.ID, run.JobName, run.Status,
+		run.ClientsProcessed, run.ClientsTotal, run.ThroughputPerSecond, run.LatencyAvgMs, run.LatencyP95Ms)
+	if id == 0 {
+		return
+	}
+	// the run context may be cancelled already, the end is recorded regardless
+	if err := runDAO.FinishRun(context.Background(run.JobName), run, t.takeFailures()); err != nil {
+		log.Error(ctx).Err(err).Msgf("recording the end of job run %d failed", run.ID)
+	}
+}
+
+// beat stores the progress of the run periodically and cancels it when a cancel was requested from any instance
+func (t *runTracker) beat(ctx c.Context, runDAO dao.JobRunDAO, cancel c.CancelFunc, stopped chan struct{}) {
+	ticker := time.NewTicker(t.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopped:
+			return
+		case <-ticker.C:
+			// the snapshot goes first, so that the failures of every client before its cursor are stored with it
+			run := t.snapshot()
+			failures := t.takeFailures()
+			cancelRequested, err := runDAO.UpdateProgress(ctx, run, failures)
+			if err != nil {
+				log.Warn(ctx).Err(err).Msg("storing job run progress failed")
+				t.putBackFailures(failures)
+				continue
+			}
+			if cancelRequested {
+				log.Info(ctx).Msgf("cancel of job run %d requested, cancelling", t.snapshot().ID)
+				cancel()
+			}
+		}
+	}
+}
+
+func getRunHeartbeatInterval() time.Duration {
+	return time.Duration(config.App().Jobs.RunHeartbeatSeconds) * time.Second
+}
+
+// a run is considered dead after missing a few heartbeats
+func getRunStaleAfter() time.Duration {
+	return 6 * getRunHeartbeatInterval()
+}
@@ -0,0 +1,240 @@
+package jobs
+
+import (
+	c "context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/goerr"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeJobRunDAO struct {
+	mu              sync.Mutex
+	finished        entity.JobRunEntity
+	cancelRequested bool
+	failures        []entity.JobRunFailureEntity
+	failedClients   []string
+	// called before a progress is stored, calls records the order of the progress and end of the runs
+	onProgress func()
+	calls      []string
+}
+
+func (f *fakeJobRunDAO) StartRun(ctx c.Context, run entity.JobRunEntity, staleAfter time.Duration) (entity.JobRunEntity, bool, error) {
+	return run, true, nil
+}
+
+func (f *fakeJobRunDAO) UpdateProgress(ctx c.Context, run entity.JobRunEntity, failures []entity.JobRunFailureEntity) (bool, error) {
+	if f.onProgress != nil {
+		f.onProgress()
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "progress")
+	f.failures = append(f.failures, failures...)
+	return f.cancelRequested, nil
+}
+
+func (f *fakeJobRunDAO) FinishRun(ctx c.Context, run entity.JobRunEntity, failures []entity.JobRunFailureEntity) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.finished = run
+	f.failures = append(f.failures, failures...)
+	f.calls = append(f.calls, "finish")
+	return nil
+}
+
+func (f *fakeJobRunDAO) RequestCancel(ctx c.Context, id int64) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeJobRunDAO) FetchRun(ctx c.Context, id int64) (*entity.JobRunEntity, error) {
+	return nil, nil
+}
+
+func (f *fakeJobRunDAO) FetchRuns(ctx c.Context, filter model.JobRunFilter) ([]entity.JobRunEntity, error) {
+	return nil, nil
+}
+
+func (f *fakeJobRunDAO) FetchResumableRun(ctx c.Context, jobName string, staleAfter time.Duration, startedAfter *time.Time) (*entity.JobRunEntity, error) {
+	return nil, nil
+}
+
+func (f *fakeJobRunDAO) FetchFailedClients(ctx c.Context, runID int64) ([]string, error) {
+	return f.failedClients, nil
+}
+
+type fakeJob func(ctx c.Context, tracker *runTracker) error
+
+func (f fakeJob) execute(ctx c.Context, refresher string, tracker *runTracker) error {
+	return f(ctx, tracker)
+}
+
+func TestRunTrackerExecute(t *testing.T) {
+	runDAO := &fakeJobRunDAO{}
+	tracker := &runTracker{run: entity.JobRunEntity{ID: 1, JobName: PortfolioUpdateCron}}
+	tracker.execute(c.Background(), runDAO, fakeJob(func(ctx c.Context, tracker *runTracker) error {
+		tracker.setTotal(3)
+		tracker.processed(false, false)
+		tracker.processed(true, true)
+		tracker.processed(true, false)
+		return nil
+	}), "")
+	assert.Equal(t, constants.JobRunSucceeded, runDAO.finished.Status)
+	assert.Equal(t, 3, runDAO.finished.ClientsProcessed)
+	assert.Equal(t, 1, runDAO.finished.Successes)
+	assert.Equal(t, 2, runDAO.finished.UpstreamErrors)
+	assert.Equal(t, 1, runDAO.finished.InvalidClients)
+
+	tracker = &runTracker{run: entity.JobRunEntity{ID: 2, JobName: PortfolioUpdateCron}}
+	tracker.execute(c.Background(), runDAO, fakeJob(func(ctx c.Context, tracker *runTracker) error {
+		return errors.New("upstream down")
+	}), "")
+	assert.Equal(t, constants.JobRunFailed, runDAO.finished.Status)
+	assert.Equal(t, "upstream down", runDAO.finished.Error)
+
+	tracker = &runTracker{run: entity.JobRunEntity{ID: 3, JobName: PortfolioUpdateCron}}
+	tracker.execute(c.Background(), runDAO, fakeJob(func(ctx c.Context, tracker *runTracker) error {
+		cancel, _ := activeRuns.Load(int64(3))
+		cancel.(c.CancelFunc)()
+		return ctx.Err()
+	}), "")
+	assert.Equal(t, constants.JobRunCancelled, runDAO.finished.Status)
+
+	// a cancel requested from another instance reaches the run through its heartbeat
+	runDAO = &fakeJobRunDAO{cancelRequested: true}
+	tracker = &runTracker{run: entity.JobRunEntity{ID: 4, JobName: PortfolioUpdateCron}, heartbeat: time.Millisecond}
+	tracker.execute(c.Background(), runDAO, fakeJob(func(ctx c.Context, tracker *runTracker) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), "")
+	assert.Equal(t, constants.JobRunCancelled, runDAO.finished.Status)
+}
+
+func TestRunTrackerExecuteWaitsForHeartbeat(t *testing.T) {
+	progressing := make(chan struct{})
+	release := make(chan struct{})
+	stored := make(chan struct{})
+	var once sync.Once
+	runDAO := &fakeJobRunDAO{onProgress: func() {
+		once.Do(func() {
+			close(progressing)
+			<-release
+			time.AfterFunc(10*time.Millisecond, func() { close(stored) })
+		})
+	}}
+	tracker := &runTracker{run: entity.JobRunEntity{ID: 8, JobName: PortfolioUpdateCron}, heartbeat: time.Millisecond}
+	go func() {
+		// the progress is held until the job returned
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	tracker.execute(c.Background(), runDAO, fakeJob(func(ctx c.Context, tracker *runTracker) error {
+		<-progressing
+		tracker.checkpoint("C009")
+		return nil
+	}), "")
+	<-stored
+	runDAO.mu.Lock()
+	defer runDAO.mu.Unlock()
+	assert.Equal(t, "finish", runDAO.calls[len(runDAO.calls)-1], "the end of the run must be stored last")
+	assert.Equal(t, "C009", runDAO.finished.Cursor)
+}
+
+func TestRunTrackerCheckpoint(t *testing.T) {
+	runDAO := &fakeJobRunDAO{}
+	tracker := &runTracker{run: entity.JobRunEntity{ID: 5, JobName: PortfolioUpdateCron}}
+	tracker.execute(c.Background(), runDAO, fakeJob(func(ctx c.Context, tracker *runTracker) error {
+		tracker.failed("C002", "ERR_UPSTREAM")
+		tracker.checkpoint("C002")
+		tracker.checkpoint("C004")
+		return errors.New("batch update failed")
+	}), "")
+	assert.Equal(t, constants.JobRunFailed, runDAO.finished.Status)
+	assert.Equal(t, "C004", runDAO.finished.Cursor)
+	assert.Equal(t, []entity.JobRunFailureEntity{{RunID: 5, ClientCode: "C002", ApiError: "ERR_UPSTREAM"}}, runDAO.failures)
+
+	// failures of a run that is not recorded are not kept
+	tracker = &runTracker{}
+	tracker.failed("C002", "ERR_UPSTREAM")
+	assert.Empty(t, tracker.takeFailures())
+}
+
+func TestSourcedRun(t *testing.T) {
+	run := entity.JobRunEntity{JobName: PortfolioUpdateCron, Refresher: "instant", Mode: constants.JobRunModeFull}
+	interrupted := &entity.JobRunEntity{ID: 7, JobName: PortfolioUpdateCron, Status: constants.JobRunFailed, Mode: constants.JobRunModeFull, Cursor: "C010"}
+
+	resumed, err := sourcedRun(run, constants.JobRunModeResume, interrupted)
+	assert.NoError(t, err)
+	assert.Equal(t, constants.JobRunModeResume, resumed.Mode)
+	assert.Equal(t, int64(7), resumed.SourceRunID)
+	assert.Equal(t, "C010", resumed.Cursor)
+	assert.Equal(t, "", resumed.Refresher)
+
+	retried, err := sourcedRun(run, constants.JobRunModeRetryFailed, interrupted)
+	assert.NoError(t, err)
+	assert.Equal(t, constants.JobRunModeRetryFailed, retried.Mode)
+	assert.Equal(t, "", retried.Cursor)
+
+	_, err = sourcedRun(run, constants.JobRunModeResume, &entity.JobRunEntity{ID: 8, Status: constants.JobRunSucceeded, Mode: constants.JobRunModeFull})
+	assert.Equal(t, http.StatusBadRequest, goerr.Code(err))
+	_, err = sourcedRun(run, constants.JobRunModeResume, &entity.JobRunEntity{ID: 9, Status: constants.JobRunFailed, Mode: constants.JobRunModeRetryFailed})
+	assert.Equal(t, http.StatusBadRequest, goerr.Code(err))
+	_, err = sourcedRun(run, constants.JobRunModeRetryFailed, &entity.JobRunEntity{ID: 10, Status: constants.JobRunRunning})
+	assert.Equal(t, http.StatusConflict, goerr.Code(err))
+	_, err = sourcedRun(run, "partial", interrupted)
+	assert.Equal(t, http.StatusBadRequest, goerr.Code(err))
+}
+
+func TestResumableSince(t *testing.T) {
+	previous := jobScheduler
+	t.Cleanup(func() { jobScheduler = previous })
+	jobScheduler = newScheduler(nil, nil)
+	jobScheduler.schedules = map[string]string{PortfolioUpdateCron: "0 2 * * *", PendingJourneyUpdateCron: "not a cron"}
+
+	now := time.Date(2026, 10, 19, 2, 0, 5, 0, time.UTC)
+	since, ok := resumableSince(PortfolioUpdateCron, now)
+	assert.True(t, ok)
+	// the run of the previous night is resumed, the one of the night before is not
+	assert.Equal(t, now.Add(-36*time.Hour), since)
+	assert.True(t, since.Before(now.Add(-24*time.Hour)))
+	assert.True(t, since.After(now.Add(-48*time.Hour)))
+
+	_, ok = resumableSince(PendingJourneyUpdateCron, now)
+	assert.False(t, ok)
+}
+
+func TestRunClients(t *testing.T) {
+	runDAO := &fakeJobRunDAO{failedClients: []string{"C003", "C007"}}
+	fetch := func() ([]string, error) {
+		return []string{"C005", "C001", "C004", "C002", "C003"}, nil
+	}
+
+	clients, err := runClients(c.Background(), runDAO, &runTracker{run: entity.JobRunEntity{Mode: constants.JobRunModeFull}}, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"C001", "C002", "C003", "C004", "C005"}, clients)
+
+	clients, err = runClients(c.Background(), runDAO, &runTracker{run: entity.JobRunEntity{Mode: constants.JobRunModeResume, Cursor: "C003"}}, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"C004", "C005"}, clients)
+
+	// the cursor client may have left the list since
+	clients, err = runClients(c.Background(), runDAO, &runTracker{run: entity.JobRunEntity{Mode: constants.JobRunModeResume, Cursor: "C0035"}}, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"C004", "C005"}, clients)
+
+	clients, err = runClients(c.Background(), runDAO, &runTracker{run: entity.JobRunEntity{Mode: constants.JobRunModeRetryFailed, SourceRunID: 7}}, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"C003", "C007"}, clients)
+
+	// the clients given on the command line replace the clients of the job
+	clients, err = runClients(c.Background(), runDAO, &runTracker{run: entity.JobRunEntity{Mode: constants.JobRunModeFull}, clients: []string{"C009", "C008"}}, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"C008", "C009"}, clients)
+}
@@ -0,0 +1,166 @@
+package jobs
+
+import (
+	c "context"
+	"sync"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/goerr"
+	"github.com/robfig/cron/v3"
+)
+
+var jobConfigs = struct {
+	sync.RWMutex
+	configs map[string]entity.JobConfigEntity
+}{configs: map[string]entity.JobConfigEntity{}}
+
+var jobScheduler *scheduler
+
+// scheduler keeps the cron entries in line with the job_config table, a changed schedule replaces the entry of the job
+type scheduler struct {
+	mu        sync.Mutex
+	cron      *cron.Cron
+	jobs      map[string]cron.Job
+	entries   map[string]cron.EntryID
+	schedules map[string]string
+	configDAO dao.JobConfigDAO
+	stopped   chan struct{}
+}
+
+func newScheduler(jobs map[string]cron.Job, configDAO dao.JobConfigDAO) *scheduler {
+	return &scheduler{cron: cron.New(), jobs: jobs, entries: map[string]cron.EntryID{}, schedules: map[string]string{}, configDAO: configDAO,
+		stopped: make(chan struct{})}
+}
+
+// reload reads job_config and reschedules the jobs whose schedule changed. When the table cannot be read the
+// last loaded configs stay in effect.
+func (s *scheduler) reload(ctx c.Context) error {
+	configs, err := s.configDAO.FetchConfigs(ctx)
+	if err != nil {
+		return goerr.New(err, "jobs: job config reload failed")
+	}
+	loaded := make(map[string]entity.JobConfigEntity)
+	for _, jobConfig := range configs {
+		loaded[jobConfig.JobName] = jobConfig
+	}
+	jobConfigs.Lock()
+	jobConfigs.configs = loaded
+	jobConfigs.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, job := range s.jobs {
+		schedule := GetSchedule(name)
+		if jobConfig, ok := loaded[name]; ok && jobConfig.Schedule != "" {
+			schedule = jobConfig.Schedule
+		}
+		if schedule == s.schedules[name] {
+			continue
+		}
+		if _, err := cron.ParseStandard(schedule); err != nil {
+			log.Error(ctx).Err(err).Msgf("invalid schedule '%s' of job %s, keeping '%s'", schedule, name, s.schedules[name])
+			continue
+		}
+		if id, ok := s.entries[name]; ok {
+			s.cron.Remove(id)
+		}
+		id, _ := s.cron.AddJob(schedule, job)
+		s.entries[name] = id
+		s.schedules[name] = schedule
+		log.Info(ctx).Msgf("job %s scheduled at '%s'", name, schedule)
+	}
+	return nil
+}
+
+// startReloading reloads the job configs periodically so edits of job_config reach every instance
+func (s *scheduler) startReloading() {
+	go func() {
+		var ctx = context.Background("jobConfigReload")
+		ticker := time.NewTicker(getReloadInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.stopped:
+				return
+			case <-ticker.C:
+			}
+			if err := s.reload(ctx); err != nil {
+				log.Error(ctx).Err(err).Msg("reloading job configs failed")
+			}
+		}
+	}()
+}
+
+// stop stops scheduling the jobs and reloading their configs, the returned context is done once the running jobs
+// returned
+func (s *scheduler) stop() c.Context {
+	close(s.stopped)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cron.Stop()
+}
+
+// ReloadJobConfig applies job_config and the schedules of the application config on this instance right away, other
+// instances pick it up on their next reload
+func ReloadJobConfig(ctx c.Context) error {
+	if jobScheduler == nil {
+		return nil
+	}
+	select {
+	case <-jobScheduler.stopped:
+		return nil
+	default:
+	}
+	return jobScheduler.reload(ctx)
+}
+
+// schedulePeriod returns the time between the next two runs of the job on the schedule in effect, 0 when the schedule
+// is invalid
+func schedulePeriod(name string, now time.Time) time.Duration {
+	schedule := GetSchedule(name)
+	if jobScheduler != nil {
+		jobScheduler.mu.Lock()
+		if scheduled, ok := jobScheduler.schedules[name]; ok {
+			schedule = scheduled
+		}
+		jobScheduler.mu.Unlock()
+	}
+	parsed, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return 0
+	}
+	next := parsed.Next(now)
+	return parsed.Next(next).Sub(next)
+}
+
+func getJobConfig(name string) (entity.JobConfigEntity, bool) {
+	jobConfigs.RLock()
+	defer jobConfigs.RUnlock()
+	jobConfig, ok := jobConfigs.configs[name]
+	return jobConfig, ok
+}
+
+// getJobBatchSize returns the batch size of job_config, falling back to the batch size of the application config
+func getJobBatchSize(name string, fallback int64) int64 {
+	if jobConfig, ok := getJobConfig(name); ok && jobConfig.BatchSize > 0 {
+		return int64(jobConfig.BatchSize)
+	}
+	return fallback
+}
+
+// getJobProvider returns the provider of job_config, falling back to the provider of the application config
+func getJobProvider(name string, fallback string) string {
+	if jobConfig, ok := getJobConfig(name); ok && jobConfig.Provider != "" {
+		return jobConfig.Provider
+	}
+	return fallback
+}
+
+func getReloadInterval() time.Duration {
+	return time.Duration(config.App().Jobs.ConfigReloadSeconds) * time.Second
+}
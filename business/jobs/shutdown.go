@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	c "context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/goerr"
+)
+
+// stopping is set once the instance shuts down, no run starts after it
+var stopping atomic.Bool
+
+// triggeredRuns are the runs triggered through the api still executing, the scheduled ones are waited for through cron
+var triggeredRuns sync.WaitGroup
+
+var jobLeaseDAO dao.JobLeaseDAO
+
+// leaseReleaseTimeout bounds the release of the job leases, which runs even when the runs did not stop in time
+const leaseReleaseTimeout = 5 * time.Second
+
+// StopJobs stops scheduling the jobs and cancels the running runs, which stop at their next batch boundary and are
+// resumed by the next run of the job. It waits for the runs until ctx is done. The job leases of this instance are
+// released either way, so another instance takes the jobs over on its next tick; a run still going keeps renewing its
+// lease until the instance exits.
+func StopJobs(ctx c.Context) (err error) {
+	stopping.Store(true)
+	defer func() {
+		err = errors.Join(err, releaseLeases(ctx))
+	}()
+	cronStopped := c.Background()
+	if jobScheduler != nil {
+		cronStopped = jobScheduler.stop()
+	}
+	activeRuns.Range(func(_, cancel any) bool {
+		cancel.(c.CancelFunc)()
+		return true
+	})
+
+	runsStopped := make(chan struct{})
+	go func() {
+		triggeredRuns.Wait()
+		if jobScheduler != nil {
+			<-cronStopped.Done()
+		}
+		close(runsStopped)
+	}()
+	select {
+	case <-runsStopped:
+		log.Info(ctx).Msg("running jobs stopped")
+		return nil
+	case <-ctx.Done():
+		return goerr.New(ctx.Err(), "jobs: running jobs did not stop in time")
+	}
+}
+
+// releaseLeases releases the job leases of this instance, also once ctx is done
+func releaseLeases(ctx c.Context) error {
+	if jobLeaseDAO == nil {
+		return nil
+	}
+	ctx, cancel := c.WithTimeout(c.WithoutCancel(ctx), leaseReleaseTimeout)
+	defer cancel()
+	released, err := jobLeaseDAO.ReleaseAll(ctx, instanceID)
+	if err != nil {
+		return goerr.New(err, "jobs: releasing job leases failed")
+	}
+	log.Info(ctx).Msgf("released %d job leases of %s", released, instanceID)
+	return nil
+}
@@ -0,0 +1,84 @@
+package jobs
+
+import (
+	c "context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/goerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStopJobs(t *testing.T) {
+	defer stopping.Store(false)
+	runDAO := &fakeJobRunDAO{}
+	tracker := &runTracker{run: entity.JobRunEntity{ID: 5, JobName: PortfolioUpdateCron}}
+	started := make(chan struct{})
+	triggeredRuns.Add(1)
+	go func() {
+		defer triggeredRuns.Done()
+		tracker.execute(c.Background(), runDAO, fakeJob(func(ctx c.Context, tracker *runTracker) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}), "")
+	}()
+	<-started
+
+	ctx, cancel := c.WithTimeout(c.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, StopJobs(ctx))
+
+	// the interrupted run is recorded as failed, so that the next run resumes it
+	assert.Equal(t, constants.JobRunFailed, runDAO.finished.Status)
+	assert.Contains(t, runDAO.finished.Error, "interrupted by shutdown")
+
+	_, err := NewJobs(Dependencies{JobRunDAO: runDAO}).Trigger(c.Background(), PortfolioUpdateCron, model.JobTriggerRequest{}, "admin")
+	assert.Equal(t, http.StatusServiceUnavailable, goerr.Code(err))
+}
+
+func TestStopJobsReleasesLeasesOnTimeout(t *testing.T) {
+	defer stopping.Store(false)
+	previous := jobLeaseDAO
+	leaseDAO := &fakeJobLeaseDAO{}
+	jobLeaseDAO = leaseDAO
+	t.Cleanup(func() { jobLeaseDAO = previous })
+
+	// a run ignoring its cancellation
+	finish := make(chan struct{})
+	triggeredRuns.Add(1)
+	go func() {
+		defer triggeredRuns.Done()
+		<-finish
+	}()
+	defer close(finish)
+
+	ctx, cancel := c.WithTimeout(c.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := StopJobs(ctx)
+	assert.ErrorIs(t, err, c.DeadlineExceeded)
+	assert.Equal(t, []string{instanceID}, leaseDAO.releasedAll)
+}
+
+func TestExecuteCancelsRunRegisteredWhileStopping(t *testing.T) {
+	defer stopping.Store(false)
+	stopping.Store(true)
+	runDAO := &fakeJobRunDAO{}
+	tracker := &runTracker{run: entity.JobRunEntity{ID: 6, JobName: PortfolioUpdateCron}}
+	tracker.execute(c.Background(), runDAO, fakeJob(func(ctx c.Context, tracker *runTracker) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), "")
+	assert.Equal(t, constants.JobRunFailed, runDAO.finished.Status)
+
+	// a run that could not be recorded is cancelled too
+	unrecorded := &runTracker{run: entity.JobRunEntity{JobName: PortfolioUpdateCron}}
+	unrecorded.execute(c.Background(), runDAO, fakeJob(func(ctx c.Context, tracker *runTracker) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), "")
+}
@@ -1,11 +1,11 @@
 package jobs
 
 import (
+	c "context"
+
 	"github.com/angel-one/fd-core/commons/context"
 	"github.com/angel-one/fd-core/commons/log"
 	"github.com/angel-one/fd-core/external"
-	"github.com/angel-one/fd-core/factory"
-	"github.com/robfig/cron/v3"
 )
 
 const (
@@ -16,21 +16,28 @@ type tokenRenewalJob struct {
 	upswing external.UpSwing
 }
 
-func DefaultTokenRenewalJob() cron.Job {
-	return &tokenRenewalJob{upswing: factory.GetUpSwingExternalService()}
+func newTokenRenewalJob(upswing external.UpSwing) *tokenRenewalJob {
+	return &tokenRenewalJob{upswing: upswing}
 }
 
 func (t *tokenRenewalJob) Run() {
 	var ctx = context.Background(TokenRenewalCron)
 	defer ctx.Done()
 
-	log.Info(ctx).Msg("starting token renewal job...")
 	enabled := isJobEnabled(ctx, TokenRenewalCron)
 	if !enabled {
 		log.Warn(ctx).Msg("renew token job is marked as disabled in config, skipping its execution")
 		return
 	}
 
-	t.upswing.ValidateToken(ctx)
-	log.Info(ctx).Msg("stopping token renewal job...")
+	// every instance renews its own token every few seconds, its scheduled runs are not recorded in job_runs
+	if err := t.execute(ctx, "", nil); err != nil {
+		log.Error(ctx).Err(err).Stack().Msg("renewing upswing token failed")
+	}
+}
+
+func (t *tokenRenewalJob) execute(ctx c.Context, refresher string, tracker *runTracker) error {
+	log.Info(ctx).Msg("starting token renewal job...")
+	defer log.Info(ctx).Msg("stopping token renewal job...")
+	return t.upswing.ValidateToken(ctx)
 }
@@ -0,0 +1,6 @@
+package model
+
+type RederiveResult struct {
+	Provider      string `json:"provider"`
+	EventsUpdated int    `json:"eventsUpdated"`
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ClientState is everything stored and live for a client, for support and ops. With Masked the PII is masked.
+type ClientState struct {
+	ClientCode     string                     `json:"clientCode"`
+	Provider       string                     `json:"provider"`
+	Masked         bool                       `json:"masked"`
+	Registration   RegistrationStatus         `json:"registration"`
+	Portfolio      *ClientPortfolioState      `json:"portfolio"`
+	PendingJourney *ClientPendingJourneyState `json:"pendingJourney"`
+	Journeys       []BookingJourney           `json:"journeys"`
+	RecentEvents   []ClientWebhookEvent       `json:"recentEvents"`
+	LiveNetWorth   *NetWorthResponse          `json:"liveNetWorth"`
+}
+
+// RegistrationStatus is the status of the client at the provider, derived from the live net worth call
+type RegistrationStatus struct {
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type ClientPortfolioState struct {
+	TotalActiveDeposits int       `json:"totalActiveDeposits"`
+	InvestedValue       float64   `json:"investedValue"`
+	CurrentValue        float64   `json:"currentValue"`
+	InterestEarned      float64   `json:"interestEarned"`
+	ReturnsValue        float64   `json:"returnsValue"`
+	ReturnsPercentage   float64   `json:"returnsPercentage"`
+	InvalidClient       bool      `json:"invalidClient"`
+	ApiError            string    `json:"apiError"`
+	ToBeRefreshed       bool      `json:"toBeRefreshed"`
+	UpdatedBy           string    `json:"updatedBy"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+}
+
+type ClientPendingJourneyState struct {
+	Pending        bool      `json:"pending"`
+	PaymentPending bool      `json:"paymentPending"`
+	KYCPending     bool      `json:"kycPending"`
+	JourneyID      string    `json:"journeyId"`
+	BlockingEvent  string    `json:"blockingEvent"`
+	FailureReason  string    `json:"failureReason"`
+	Fsi            string    `json:"fsi"`
+	Amount         float64   `json:"amount"`
+	InvalidClient  bool      `json:"invalidClient"`
+	ApiError       string    `json:"apiError"`
+	ToBeRefreshed  bool      `json:"toBeRefreshed"`
+	UpdatedBy      string    `json:"updatedBy"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+type ClientWebhookEvent struct {
+	ID             int64           `json:"id"`
+	EventType      string          `json:"eventType"`
+	JourneyID      string          `json:"journeyId"`
+	Fsi            string          `json:"fsi"`
+	Amount         float64         `json:"amount"`
+	FailureReason  string          `json:"failureReason"`
+	PayloadVersion string          `json:"payloadVersion"`
+	RawPayload     json.RawMessage `json:"rawPayload,omitempty"`
+	ReceivedAt     time.Time       `json:"receivedAt"`
+}
@@ -7,8 +7,14 @@ type Homepage struct {
 }
 
 type Journey struct {
-	Pending      bool         `json:"pending"`
-	PendingState PendingState `json:"pendingState"`
+	Pending       bool         `json:"pending"`
+	PendingState  PendingState `json:"pendingState"`
+	BlockingStep  string       `json:"blockingStep,omitempty"`
+	FailureReason string       `json:"failureReason,omitempty"`
+	JourneyID     string       `json:"journeyId,omitempty"`
+	Fsi           string       `json:"fsi,omitempty"`
+	Amount        float64      `json:"amount,omitempty"`
+	ResumeLink    string       `json:"resumeLink,omitempty"`
 }
 
 type PendingState struct {
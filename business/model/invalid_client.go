@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// InvalidClientRequest sets or clears the invalid flag of a client, in portfolio and pending journey when scope is empty
+type InvalidClientRequest struct {
+	Invalid  *bool  `json:"invalid" binding:"required"`
+	Reason   string `json:"reason" binding:"required"`
+	Scope    string `json:"scope"`
+	Provider string `json:"provider"`
+}
+
+// InvalidClientUpdate lists the scopes whose flag changed, a client set invalid is revalidated from nextRevalidationAt
+type InvalidClientUpdate struct {
+	ClientCode         string     `json:"clientCode"`
+	Provider           string     `json:"provider"`
+	Invalid            bool       `json:"invalid"`
+	ChangedScopes      []string   `json:"changedScopes"`
+	NextRevalidationAt *time.Time `json:"nextRevalidationAt,omitempty"`
+}
+
+type InvalidClientAuditTrail struct {
+	ClientCode string               `json:"clientCode"`
+	Provider   string               `json:"provider"`
+	Changes    []InvalidClientAudit `json:"changes"`
+}
+
+type InvalidClientAudit struct {
+	ID        int64     `json:"id"`
+	Scope     string    `json:"scope"`
+	Invalid   bool      `json:"invalid"`
+	Reason    string    `json:"reason"`
+	ChangedBy string    `json:"changedBy"`
+	ChangedAt time.Time `json:"changedAt"`
+}
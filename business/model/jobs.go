@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+type JobLeases struct {
+	Instance string     `json:"instance"`
+	Leases   []JobLease `json:"leases"`
+}
+
+// JobLease is the replica running a cron job, an expired lease is taken over on the next tick of the job
+type JobLease struct {
+	Job        string    `json:"job"`
+	Holder     string    `json:"holder"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	RenewedAt  time.Time `json:"renewedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Expired    bool      `json:"expired"`
+}
+
+type JobRuns struct {
+	Runs []JobRun `json:"runs"`
+}
+
+type JobRun struct {
+	ID               int64      `json:"id"`
+	Job              string     `json:"job"`
+	Trigger          string     `json:"trigger"`
+	Refresher        string     `json:"refresher,omitempty"`
+	Instance         string     `json:"instance"`
+	Status           string     `json:"status"`
+	StartedAt        time.Time  `json:"startedAt"`
+	FinishedAt       *time.Time `json:"finishedAt"`
+	ClientsTotal     int        `json:"clientsTotal"`
+	ClientsProcessed int        `json:"clientsProcessed"`
+	Successes        int        `json:"successes"`
+	UpstreamErrors   int        `json:"upstreamErrors"`
+	InvalidClients   int        `json:"invalidClients"`
+	Error            string     `json:"error,omitempty"`
+	CancelRequested  bool       `json:"cancelRequested"`
+	RequestedBy      string     `json:"requestedBy,omitempty"`
+	// last client whose result is stored, ordered by client code
+	Cursor      string `json:"cursor,omitempty"`
+	Mode        string `json:"mode"`
+	SourceRunID int64  `json:"sourceRunId,omitempty"`
+	// set once the run finished, the latencies are of the provider calls
+	ThroughputPerSecond float64 `json:"throughputPerSecond"`
+	LatencyAvgMs        float64 `json:"latencyAvgMs"`
+	LatencyP95Ms        float64 `json:"latencyP95Ms"`
+}
+
+// JobTriggerRequest is the optional body of an on demand run, refresher "instant" refreshes only the flagged clients.
+// Mode resume continues run runId after its cursor, by default the latest interrupted run, and mode retryFailed
+// processes the clients whose upstream call failed in run runId. Both keep the refresher of that run.
+type JobTriggerRequest struct {
+	Refresher string `json:"refresher"`
+	Mode      string `json:"mode"`
+	RunID     int64  `json:"runId"`
+}
+
+// JobRunFilter selects job runs, empty fields match every run
+type JobRunFilter struct {
+	JobName string
+	Status  string
+	Trigger string
+	From    *time.Time
+	To      *time.Time
+	Limit   int
+}
+
+// JobConfig is the runtime config of a cron job, Schedule falls back to the application config
+type JobConfig struct {
+	Job         string    `json:"job"`
+	Enabled     bool      `json:"enabled"`
+	Schedule    string    `json:"schedule"`
+	BatchSize   int       `json:"batchSize,omitempty"`
+	Provider    string    `json:"provider,omitempty"`
+	Concurrency int       `json:"concurrency"`
+	UpdatedBy   string    `json:"updatedBy"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// JobConfigRequest replaces the config of a job, an empty schedule, batch size or provider falls back to the application config
+type JobConfigRequest struct {
+	Enabled     *bool  `json:"enabled" binding:"required"`
+	Schedule    string `json:"schedule"`
+	BatchSize   int    `json:"batchSize"`
+	Provider    string `json:"provider"`
+	Concurrency int    `json:"concurrency"`
+}
+
+type JobRunCancelResult struct {
+	RunID           int64 `json:"runId"`
+	CancelRequested bool  `json:"cancelRequested"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type BookingJourneys struct {
+	Journeys []BookingJourney `json:"journeys"`
+}
+
+// BookingJourney is one booking attempt of the user, state is one of initiated, kyc, payment, booked, failed, withdrawn
+type BookingJourney struct {
+	JourneyID     string    `json:"journeyId"`
+	State         string    `json:"state"`
+	Fsi           string    `json:"fsi"`
+	Amount        float64   `json:"amount"`
+	LastEventType string    `json:"lastEventType"`
+	LastEventAt   time.Time `json:"lastEventAt"`
+	StartedAt     time.Time `json:"startedAt"`
+}
+
+type JourneyTimeline struct {
+	Journey BookingJourney         `json:"journey"`
+	Events  []JourneyTimelineEvent `json:"events"`
+}
+
+// JourneyTimelineEvent is a received event and the journey state right after it
+type JourneyTimelineEvent struct {
+	EventType string    `json:"eventType"`
+	State     string    `json:"state"`
+	Reason    string    `json:"reason,omitempty"`
+	Amount    float64   `json:"amount,omitempty"`
+	At        time.Time `json:"at"`
+}
@@ -0,0 +1,39 @@
+package model
+
+import "time"
+
+// ReconciliationSummary is the result of the latest portfolio reconciliation run of a provider
+type ReconciliationSummary struct {
+	Provider        string                 `json:"provider"`
+	RunID           int64                  `json:"runId"`
+	StartedAt       *time.Time             `json:"startedAt"`
+	FinishedAt      *time.Time             `json:"finishedAt"`
+	ClientsCompared int                    `json:"clientsCompared"`
+	ClientsFailed   int                    `json:"clientsFailed"`
+	Discrepancies   int                    `json:"discrepancies"`
+	BySeverity      map[string]int         `json:"bySeverity"`
+	Top             []PortfolioDiscrepancy `json:"top"`
+}
+
+type PortfolioDiscrepancy struct {
+	ClientCode           string         `json:"clientCode"`
+	Severity             string         `json:"severity"`
+	DB                   PortfolioValue `json:"db"`
+	Provider             PortfolioValue `json:"provider"`
+	DifferencePercentage float64        `json:"differencePercentage"`
+	FoundAt              time.Time      `json:"foundAt"`
+}
+
+type PortfolioValue struct {
+	ActiveDeposits int     `json:"activeDeposits"`
+	InvestedValue  float64 `json:"investedValue"`
+	CurrentValue   float64 `json:"currentValue"`
+}
+
+// Alert is posted to the alert hook
+type Alert struct {
+	Source   string      `json:"source"`
+	Severity string      `json:"severity"`
+	Summary  string      `json:"summary"`
+	Details  interface{} `json:"details,omitempty"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// ReplayRequest scopes a webhook replay; clients are picked by the filters, but their state is always
+// derived from their complete event history. No filters means every client of the provider.
+type ReplayRequest struct {
+	Provider   string     `json:"provider"`
+	ClientCode string     `json:"clientCode,omitempty"`
+	From       *time.Time `json:"from,omitempty"`
+	To         *time.Time `json:"to,omitempty"`
+	DryRun     bool       `json:"dryRun"`
+}
+
+type ReplayResult struct {
+	DryRun         bool               `json:"dryRun"`
+	ClientsScanned int                `json:"clientsScanned"`
+	ClientsChanged int                `json:"clientsChanged"`
+	EventsReplayed int                `json:"eventsReplayed"`
+	Diffs          []ClientReplayDiff `json:"diffs"`
+}
+
+type ClientReplayDiff struct {
+	ClientCode string      `json:"clientCode"`
+	Changes    []FieldDiff `json:"changes"`
+}
+
+type FieldDiff struct {
+	Field   string      `json:"field"`
+	Current interface{} `json:"current"`
+	Derived interface{} `json:"derived"`
+}
@@ -95,6 +95,7 @@ type DataIngestionName struct {
 	LastName   string `json:"lastName,omitempty"`
 }
 
+// UpSwingWebhookEvent is the v1 schema of the upswing webhook payload, the full body is stored as-is alongside
 type UpSwingWebhookEvent struct {
 	Pci             string  `json:"pci,omitempty"`
 	Fsi             string  `json:"fsi,omitempty"`
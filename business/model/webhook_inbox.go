@@ -0,0 +1,37 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookDeadLetter struct {
+	ID         int64           `json:"id"`
+	Provider   string          `json:"provider"`
+	Attempts   int             `json:"attempts"`
+	LastError  string          `json:"lastError"`
+	Payload    json.RawMessage `json:"payload"`
+	ReceivedAt time.Time       `json:"receivedAt"`
+	FailedAt   time.Time       `json:"failedAt"`
+	RequeuedAt *time.Time      `json:"requeuedAt,omitempty"`
+	RequeuedBy string          `json:"requeuedBy,omitempty"`
+}
+
+type RequeueResult struct {
+	DeadLetterID int64 `json:"deadLetterId"`
+	Requeued     bool  `json:"requeued"`
+}
+
+type WebhookBatchResult struct {
+	Accepted   int                  `json:"accepted"`
+	Duplicates int                  `json:"duplicates"`
+	Rejected   int                  `json:"rejected"`
+	Results    []WebhookEventResult `json:"results"`
+}
+
+// WebhookEventResult is the outcome of one event of a delivery, index is its position in the delivered array
+type WebhookEventResult struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
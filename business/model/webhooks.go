@@ -0,0 +1,15 @@
+package model
+
+// DepositEvent is the provider independent form of a webhook event, every provider normalizer maps to it
+type DepositEvent struct {
+	EventType      string  `json:"eventType"`
+	ClientCode     string  `json:"clientCode"`
+	Fsi            string  `json:"fsi"`
+	Amount         float64 `json:"amount"`
+	TenureMonths   int     `json:"tenureMonths"`
+	TenureDays     int     `json:"tenureDays"`
+	JourneyID      string  `json:"journeyId"`
+	DepositType    string  `json:"depositType"`
+	FailureReason  string  `json:"failureReason"`
+	PayloadVersion string  `json:"payloadVersion"`
+}
@@ -0,0 +1,66 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/goerr"
+)
+
+// ArchivalDAO moves the portfolio and pending_journey rows closed longer than the retention to their archive tables and
+// purges the finished webhook inbox events
+type ArchivalDAO interface {
+	ArchiveClosedPortfolios(ctx context.Context, retention time.Duration, limit int) (int64, error)
+	ArchiveClosedPendingJourneys(ctx context.Context, retention time.Duration, limit int) (int64, error)
+	PurgeFinishedWebhookInboxEvents(ctx context.Context, retention time.Duration, limit int) (int64, error)
+}
+
+type archivalDAOImpl struct {
+	db *database.Router
+}
+
+func NewArchivalDAO(db *database.Router) ArchivalDAO {
+	return &archivalDAOImpl{db: db}
+}
+
+// ArchiveClosedPortfolios moves up to limit closed portfolios, the longest closed first, and returns the rows moved
+func (d *archivalDAOImpl) ArchiveClosedPortfolios(ctx context.Context, retention time.Duration, limit int) (int64, error) {
+	result, err := d.db.ExecContext(ctx, ArchiveClosedPortfolios, retention.Seconds(), limit)
+	if err != nil {
+		return 0, goerr.New(err, "dao failed: archiving closed portfolios failed")
+	}
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, goerr.New(err, "dao failed: unable to read archived portfolios")
+	}
+	return moved, nil
+}
+
+// ArchiveClosedPendingJourneys moves up to limit closed pending journeys, the longest closed first, and returns the
+// rows moved
+func (d *archivalDAOImpl) ArchiveClosedPendingJourneys(ctx context.Context, retention time.Duration, limit int) (int64, error) {
+	result, err := d.db.ExecContext(ctx, ArchiveClosedPendingJourneys, retention.Seconds(), limit)
+	if err != nil {
+		return 0, goerr.New(err, "dao failed: archiving closed pending journeys failed")
+	}
+	moved, err := result.RowsAffected()
+	if err != nil {
+		return 0, goerr.New(err, "dao failed: unable to read archived pending journeys")
+	}
+	return moved, nil
+}
+
+// PurgeFinishedWebhookInboxEvents deletes up to limit inbox events done or dead longer than the retention and returns
+// the rows deleted, a redelivery of a purged event is no longer detected as a duplicate
+func (d *archivalDAOImpl) PurgeFinishedWebhookInboxEvents(ctx context.Context, retention time.Duration, limit int) (int64, error) {
+	result, err := d.db.ExecContext(ctx, PurgeFinishedWebhookInboxEvents, retention.Seconds(), limit)
+	if err != nil {
+		return 0, goerr.New(err, "dao failed: purging finished webhook inbox events failed")
+	}
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, goerr.New(err, "dao failed: unable to read purged webhook inbox events")
+	}
+	return purged, nil
+}
@@ -0,0 +1,113 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/goerr"
+)
+
+type ClientRefreshDAO interface {
+	Enqueue(ctx context.Context, provider string, clientCode string, debounce time.Duration, maxDelay time.Duration) error
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]entity.ClientRefreshEntity, error)
+	Renew(ctx context.Context, refreshes []entity.ClientRefreshEntity, lease time.Duration) error
+	Complete(ctx context.Context, refresh entity.ClientRefreshEntity) error
+	Retry(ctx context.Context, refresh entity.ClientRefreshEntity, dueAt time.Time, lastError string) error
+	Drop(ctx context.Context, refresh entity.ClientRefreshEntity) error
+}
+
+type clientRefreshDAOImpl struct {
+	db *database.Router
+}
+
+func NewClientRefreshDAO(db *database.Router) ClientRefreshDAO {
+	return &clientRefreshDAOImpl{db: db}
+}
+
+// Enqueue queues a refresh of the client debounce from now, an already queued refresh is pushed out up to maxDelay
+// after its first event
+func (d *clientRefreshDAOImpl) Enqueue(ctx context.Context, provider string, clientCode string, debounce time.Duration, maxDelay time.Duration) error {
+	_, err := d.db.ExecContext(ctx, EnqueueClientRefresh, clientCode, provider, debounce.Seconds(), maxDelay.Seconds())
+	if err != nil {
+		return goerr.New(err, fmt.Sprintf("dao failed: client refresh enqueue failed for clientCode: %s", clientCode))
+	}
+	return nil
+}
+
+// Claim locks up to limit due refreshes for the lease duration and returns them
+func (d *clientRefreshDAOImpl) Claim(ctx context.Context, limit int, lease time.Duration) ([]entity.ClientRefreshEntity, error) {
+	rows, err := d.db.DB(database.Write).QueryContext(ctx, ClaimClientRefreshes, limit, lease.Seconds())
+	if err != nil {
+		return nil, goerr.New(err, "dao failed: claiming client refreshes failed")
+	}
+	defer rows.Close()
+
+	var refreshes []entity.ClientRefreshEntity
+	for rows.Next() {
+		var refresh entity.ClientRefreshEntity
+		if err := rows.Scan(&refresh.ClientCode, &refresh.Provider, &refresh.Events, &refresh.Attempts); err != nil {
+			return nil, goerr.New(err, "dao failed: scanning client refresh failed")
+		}
+		refreshes = append(refreshes, refresh)
+	}
+	return refreshes, nil
+}
+
+// Renew extends the lease of the claimed refreshes not finished yet to lease from now
+func (d *clientRefreshDAOImpl) Renew(ctx context.Context, refreshes []entity.ClientRefreshEntity, lease time.Duration) error {
+	if len(refreshes) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(refreshes))
+	args := []interface{}{lease.Seconds()}
+	for i, refresh := range refreshes {
+		placeholders[i] = fmt.Sprintf("($%d, $%d)", len(args)+1, len(args)+2)
+		args = append(args, refresh.ClientCode, refresh.Provider)
+	}
+	_, err := d.db.ExecContext(ctx, fmt.Sprintf(RenewClientRefreshes, strings.Join(placeholders, ", ")), args...)
+	if err != nil {
+		return goerr.New(err, "dao failed: renewing client refreshes failed")
+	}
+	return nil
+}
+
+// Complete removes the refresh from the queue, or releases it when events arrived since it was claimed
+func (d *clientRefreshDAOImpl) Complete(ctx context.Context, refresh entity.ClientRefreshEntity) error {
+	result, err := d.db.ExecContext(ctx, CompleteClientRefresh, refresh.ClientCode, refresh.Provider, refresh.Events)
+	if err != nil {
+		return goerr.New(err, fmt.Sprintf("dao failed: client refresh completion failed for clientCode: %s", refresh.ClientCode))
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return goerr.New(err, "dao failed: unable to read completed client refreshes")
+	}
+	if deleted > 0 {
+		return nil
+	}
+	_, err = d.db.ExecContext(ctx, ReleaseClientRefresh, refresh.ClientCode, refresh.Provider, refresh.Events)
+	if err != nil {
+		return goerr.New(err, fmt.Sprintf("dao failed: client refresh release failed for clientCode: %s", refresh.ClientCode))
+	}
+	return nil
+}
+
+func (d *clientRefreshDAOImpl) Retry(ctx context.Context, refresh entity.ClientRefreshEntity, dueAt time.Time, lastError string) error {
+	_, err := d.db.ExecContext(ctx, RetryClientRefresh, refresh.ClientCode, refresh.Provider, dueAt, lastError)
+	if err != nil {
+		return goerr.New(err, fmt.Sprintf("dao failed: client refresh retry failed for clientCode: %s", refresh.ClientCode))
+	}
+	return nil
+}
+
+func (d *clientRefreshDAOImpl) Drop(ctx context.Context, refresh entity.ClientRefreshEntity) error {
+	_, err := d.db.ExecContext(ctx, DropClientRefresh, refresh.ClientCode, refresh.Provider)
+	if err != nil {
+		return goerr.New(err, fmt.Sprintf("dao failed: client refresh drop failed for clientCode: %s", refresh.ClientCode))
+	}
+	return nil
+}
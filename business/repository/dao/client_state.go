@@ -0,0 +1,76 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/goerr"
+)
+
+// ClientStateDAO reads the complete stored state of a client for support and ops
+type ClientStateDAO interface {
+	FetchPortfolio(ctx context.Context, provider string, clientCode string) (*entity.PortfolioEntity, error)
+	FetchPendingJourney(ctx context.Context, provider string, clientCode string) (*entity.PendingJourneyEntity, error)
+	FetchRecentEvents(ctx context.Context, provider string, clientCode string, limit int) ([]entity.WebhookEvent, error)
+}
+
+type clientStateDAOImpl struct {
+	db *database.Router
+}
+
+func NewClientStateDAO(db *database.Router) ClientStateDAO {
+	return &clientStateDAOImpl{db: db}
+}
+
+// FetchPortfolio returns nil when the client has no open portfolio, like the homepage a closed one is not shown
+func (d *clientStateDAOImpl) FetchPortfolio(ctx context.Context, provider string, clientCode string) (*entity.PortfolioEntity, error) {
+	portfolio := entity.PortfolioEntity{ClientCode: clientCode, Provider: provider}
+	err := d.db.DB(database.ReadAfterWrite).QueryRowContext(ctx, FetchClientPortfolioState, clientCode, provider).Scan(&portfolio.TotalActiveDeposits, &portfolio.InvestedValue, &portfolio.CurrentValue, &portfolio.InterestEarned, &portfolio.ReturnsValue, &portfolio.ReturnsPercentage,
+		&portfolio.InvalidClient, &portfolio.ApiError, &portfolio.ToBeRefreshed, &portfolio.UpdatedBy, &portfolio.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch portfolio state failed for clientCode: %s", clientCode))
+	}
+	return &portfolio, nil
+}
+
+// FetchPendingJourney returns nil when the client has no open pending journey
+func (d *clientStateDAOImpl) FetchPendingJourney(ctx context.Context, provider string, clientCode string) (*entity.PendingJourneyEntity, error) {
+	pendingJourney := entity.PendingJourneyEntity{ClientCode: clientCode, Provider: provider}
+	err := d.db.DB(database.ReadAfterWrite).QueryRowContext(ctx, FetchClientPendingJourneyState, clientCode, provider).Scan(&pendingJourney.Pending, &pendingJourney.Payment, &pendingJourney.KYC, &pendingJourney.InvalidClient, &pendingJourney.ApiError, &pendingJourney.ToBeRefreshed,
+		&pendingJourney.TrackingId, &pendingJourney.BlockingEvent, &pendingJourney.FailureReason, &pendingJourney.Institution, &pendingJourney.Amount, &pendingJourney.UpdatedBy, &pendingJourney.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch pending journey state failed for clientCode: %s", clientCode))
+	}
+	return &pendingJourney, nil
+}
+
+// FetchRecentEvents returns the latest events of the client first
+func (d *clientStateDAOImpl) FetchRecentEvents(ctx context.Context, provider string, clientCode string, limit int) ([]entity.WebhookEvent, error) {
+	var events []entity.WebhookEvent
+	rows, err := d.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchRecentClientWebhookEvents, provider, clientCode, limit)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch recent webhook events failed for clientCode: %s", clientCode))
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		event := entity.WebhookEvent{ClientCode: clientCode, Vendor: provider}
+		var payload []byte
+		err := rows.Scan(&event.ID, &event.TrackingId, &event.EventType, &event.Institution, &event.Amount, &event.FailureReason, &event.PayloadVersion, &payload, &event.CreatedAt)
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: scanning recent webhook event failed")
+		}
+		event.RawPayload = payload
+		events = append(events, event)
+	}
+	return events, nil
+}
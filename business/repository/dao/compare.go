@@ -16,16 +16,16 @@ type CompareDAO interface {
 }
 
 type compareDAOImpl struct {
-	db *sql.DB
+	db *database.Router
 }
 
-func DefaultCompareDAO() CompareDAO {
-	return &compareDAOImpl{db: database.GetDBPool(true)}
+func NewCompareDAO(db *database.Router) CompareDAO {
+	return &compareDAOImpl{db: db}
 }
 
 func (d *compareDAOImpl) FetchCompareList(ctx context.Context) ([]model.FsiDetails, error) {
 	var fsiList []model.FsiDetails
-	rows, err := d.db.QueryContext(ctx, CompareLandingPageQuery)
+	rows, err := d.db.DB(database.Read).QueryContext(ctx, CompareLandingPageQuery)
 	if err != nil && err != sql.ErrNoRows {
 		return fsiList, fmt.Errorf("%s%w", "Error while fetching FSI list to compare: ", err)
 	}
@@ -63,7 +63,7 @@ func (d *compareDAOImpl) FetchCompareFsiDetails(ctx context.Context, fsis []stri
 
 	query := fmt.Sprintf(CompareFsiQuery, quotedPlaceholderString)
 
-	rows, err := d.db.QueryContext(ctx, query, placeholderValues...)
+	rows, err := d.db.DB(database.Read).QueryContext(ctx, query, placeholderValues...)
 	if err != nil && err != sql.ErrNoRows {
 		return compareFSIDBDetails, fmt.Errorf("%s%w", "Error while fetching compare FSI Details: ", err)
 	}
@@ -13,16 +13,16 @@ type FAQDAO interface {
 }
 
 type faqDAOImpl struct {
-	db *sql.DB
+	db *database.Router
 }
 
-func DefaultFAQDAO() FAQDAO {
-	return &faqDAOImpl{db: database.GetDBPool(true)}
+func NewFAQDAO(db *database.Router) FAQDAO {
+	return &faqDAOImpl{db: db}
 }
 
 func (d *faqDAOImpl) FetchFAQDetails(ctx context.Context, tag string) (json.RawMessage, error) {
 	var faqData json.RawMessage
-	rows, err := d.db.QueryContext(ctx, GetFAQsByTag, tag)
+	rows, err := d.db.DB(database.Read).QueryContext(ctx, GetFAQsByTag, tag)
 	if err != nil && err != sql.ErrNoRows {
 		return faqData, err
 	}
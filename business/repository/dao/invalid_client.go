@@ -0,0 +1,122 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/goerr"
+)
+
+// InvalidClientDAO maintains the invalid flag of clients in portfolio and pending_journey, the scopes
+type InvalidClientDAO interface {
+	FetchDueClients(ctx context.Context, provider string, limit int) ([]string, error)
+	SetInvalid(ctx context.Context, change entity.InvalidClientAuditEntity, scopes []string, nextRevalidation *time.Time) ([]string, error)
+	DeferRevalidation(ctx context.Context, provider string, clientCode string, backoff time.Duration, maxBackoff time.Duration) error
+	FetchAudit(ctx context.Context, provider string, clientCode string, limit int) ([]entity.InvalidClientAuditEntity, error)
+}
+
+type invalidClientDAOImpl struct {
+	db *database.Router
+}
+
+func NewInvalidClientDAO(db *database.Router) InvalidClientDAO {
+	return &invalidClientDAOImpl{db: db}
+}
+
+var setInvalidClientQueries = map[string]string{
+	constants.InvalidClientScopePortfolio:      SetPortfolioInvalidClient,
+	constants.InvalidClientScopePendingJourney: SetPendingJourneyInvalidClient,
+}
+
+// FetchDueClients returns the invalid clients whose revalidation is due, ordered by client code
+func (d *invalidClientDAOImpl) FetchDueClients(ctx context.Context, provider string, limit int) ([]string, error) {
+	rows, err := d.db.DB(database.Write).QueryContext(ctx, FetchDueInvalidClients, provider, limit)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch due invalid clients failed for provider: %s", provider))
+	}
+	defer rows.Close()
+
+	var clients []string
+	for rows.Next() {
+		var clientCode string
+		if err := rows.Scan(&clientCode); err != nil {
+			return nil, goerr.New(err, "dao failed: scanning invalid client failed")
+		}
+		clients = append(clients, clientCode)
+	}
+	return clients, nil
+}
+
+// SetInvalid sets the invalid flag of the client in the scopes where it differs and audits every change, returns the
+// changed scopes. The revalidation attempts start over.
+func (d *invalidClientDAOImpl) SetInvalid(ctx context.Context, change entity.InvalidClientAuditEntity, scopes []string, nextRevalidation *time.Time) ([]string, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, goerr.New(err, "dao failed: unable to begin invalid client transaction")
+	}
+	defer tx.Rollback()
+
+	changed := []string{}
+	for _, scope := range scopes {
+		query, ok := setInvalidClientQueries[scope]
+		if !ok {
+			return nil, goerr.New(nil, fmt.Sprintf("dao failed: unknown invalid client scope: %s", scope))
+		}
+		result, err := tx.ExecContext(ctx, query, change.ClientCode, change.Provider, change.Invalid, change.ChangedBy, nextRevalidation)
+		if err != nil {
+			return nil, goerr.New(err, fmt.Sprintf("dao failed: setting invalid flag in %s failed for clientCode: %s", scope, change.ClientCode))
+		}
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: unable to read updated invalid clients")
+		}
+		if updated == 0 {
+			continue
+		}
+		_, err = tx.ExecContext(ctx, InsertInvalidClientAudit, change.ClientCode, change.Provider, scope, change.Invalid, change.Reason, change.ChangedBy)
+		if err != nil {
+			return nil, goerr.New(err, fmt.Sprintf("dao failed: invalid client audit insert failed for clientCode: %s", change.ClientCode))
+		}
+		changed = append(changed, scope)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, goerr.New(err, "dao failed: invalid client transaction commit failed")
+	}
+	return changed, nil
+}
+
+// DeferRevalidation counts an attempt that found the client still invalid and backs its next revalidation off
+func (d *invalidClientDAOImpl) DeferRevalidation(ctx context.Context, provider string, clientCode string, backoff time.Duration, maxBackoff time.Duration) error {
+	for _, query := range []string{DeferPortfolioRevalidation, DeferPendingJourneyRevalidation} {
+		_, err := d.db.ExecContext(ctx, query, clientCode, provider, backoff.Seconds(), maxBackoff.Seconds())
+		if err != nil {
+			return goerr.New(err, fmt.Sprintf("dao failed: deferring revalidation failed for clientCode: %s", clientCode))
+		}
+	}
+	return nil
+}
+
+// FetchAudit returns the latest changes of the invalid flag of the client first
+func (d *invalidClientDAOImpl) FetchAudit(ctx context.Context, provider string, clientCode string, limit int) ([]entity.InvalidClientAuditEntity, error) {
+	rows, err := d.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchInvalidClientAudit, clientCode, provider, limit)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch invalid client audit failed for clientCode: %s", clientCode))
+	}
+	defer rows.Close()
+
+	var audit []entity.InvalidClientAuditEntity
+	for rows.Next() {
+		var change entity.InvalidClientAuditEntity
+		err := rows.Scan(&change.ID, &change.ClientCode, &change.Provider, &change.Scope, &change.Invalid, &change.Reason, &change.ChangedBy, &change.CreatedAt)
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: scanning invalid client audit failed")
+		}
+		audit = append(audit, change)
+	}
+	return audit, nil
+}
@@ -0,0 +1,51 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/goerr"
+)
+
+type JobConfigDAO interface {
+	FetchConfigs(ctx context.Context) ([]entity.JobConfigEntity, error)
+	SaveConfig(ctx context.Context, jobConfig entity.JobConfigEntity) (entity.JobConfigEntity, error)
+}
+
+type jobConfigDAOImpl struct {
+	db *database.Router
+}
+
+func NewJobConfigDAO(db *database.Router) JobConfigDAO {
+	return &jobConfigDAOImpl{db: db}
+}
+
+func (d *jobConfigDAOImpl) FetchConfigs(ctx context.Context) ([]entity.JobConfigEntity, error) {
+	rows, err := d.db.DB(database.Write).QueryContext(ctx, FetchJobConfigs)
+	if err != nil {
+		return nil, goerr.New(err, "dao failed: fetch job configs failed")
+	}
+
+	defer rows.Close()
+	var configs []entity.JobConfigEntity
+	for rows.Next() {
+		var jobConfig entity.JobConfigEntity
+		err := rows.Scan(&jobConfig.JobName, &jobConfig.Enabled, &jobConfig.Schedule, &jobConfig.BatchSize, &jobConfig.Provider, &jobConfig.Concurrency, &jobConfig.UpdatedBy, &jobConfig.UpdatedAt)
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: scanning job config failed")
+		}
+		configs = append(configs, jobConfig)
+	}
+	return configs, nil
+}
+
+// SaveConfig inserts or overwrites the config of the job, empty schedule, batch size and provider fall back to the application config
+func (d *jobConfigDAOImpl) SaveConfig(ctx context.Context, jobConfig entity.JobConfigEntity) (entity.JobConfigEntity, error) {
+	err := d.db.DB(database.Write).QueryRowContext(ctx, UpsertJobConfig, jobConfig.JobName, jobConfig.Enabled, jobConfig.Schedule, jobConfig.BatchSize, jobConfig.Provider, jobConfig.Concurrency, jobConfig.UpdatedBy).Scan(&jobConfig.UpdatedAt)
+	if err != nil {
+		return jobConfig, goerr.New(err, fmt.Sprintf("dao failed: saving config of job %s failed", jobConfig.JobName))
+	}
+	return jobConfig, nil
+}
@@ -0,0 +1,94 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/goerr"
+)
+
+type JobLeaseDAO interface {
+	Acquire(ctx context.Context, jobName string, holder string, lease time.Duration) (bool, error)
+	Renew(ctx context.Context, jobName string, holder string, lease time.Duration) (bool, error)
+	Release(ctx context.Context, jobName string, holder string, hold time.Duration) error
+	ReleaseAll(ctx context.Context, holder string) (int64, error)
+	FetchLeases(ctx context.Context) ([]entity.JobLeaseEntity, error)
+}
+
+type jobLeaseDAOImpl struct {
+	db *database.Router
+}
+
+func NewJobLeaseDAO(db *database.Router) JobLeaseDAO {
+	return &jobLeaseDAOImpl{db: db}
+}
+
+// Acquire takes or extends the lease of the job for the holder, false when another instance holds an unexpired lease
+func (d *jobLeaseDAOImpl) Acquire(ctx context.Context, jobName string, holder string, lease time.Duration) (bool, error) {
+	var name string
+	err := d.db.DB(database.Write).QueryRowContext(ctx, AcquireJobLease, jobName, holder, lease.Seconds()).Scan(&name)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, goerr.New(err, fmt.Sprintf("dao failed: acquiring lease of job %s failed", jobName))
+	}
+	return true, nil
+}
+
+// Renew extends the lease of the job held by the holder, false when the holder no longer holds it
+func (d *jobLeaseDAOImpl) Renew(ctx context.Context, jobName string, holder string, lease time.Duration) (bool, error) {
+	result, err := d.db.ExecContext(ctx, RenewJobLease, jobName, holder, lease.Seconds())
+	if err != nil {
+		return false, goerr.New(err, fmt.Sprintf("dao failed: renewing lease of job %s failed", jobName))
+	}
+	renewed, err := result.RowsAffected()
+	if err != nil {
+		return false, goerr.New(err, "dao failed: unable to read renewed job lease")
+	}
+	return renewed > 0, nil
+}
+
+// Release gives up the lease of the job held by the holder once hold passed since it was acquired
+func (d *jobLeaseDAOImpl) Release(ctx context.Context, jobName string, holder string, hold time.Duration) error {
+	_, err := d.db.ExecContext(ctx, ReleaseJobLease, jobName, holder, hold.Seconds())
+	if err != nil {
+		return goerr.New(err, fmt.Sprintf("dao failed: releasing lease of job %s failed", jobName))
+	}
+	return nil
+}
+
+// ReleaseAll gives up every lease of the holder right away, returns the number of leases released
+func (d *jobLeaseDAOImpl) ReleaseAll(ctx context.Context, holder string) (int64, error) {
+	result, err := d.db.ExecContext(ctx, ReleaseJobLeases, holder)
+	if err != nil {
+		return 0, goerr.New(err, fmt.Sprintf("dao failed: releasing job leases of %s failed", holder))
+	}
+	released, err := result.RowsAffected()
+	if err != nil {
+		return 0, goerr.New(err, "dao failed: unable to read released job leases")
+	}
+	return released, nil
+}
+
+func (d *jobLeaseDAOImpl) FetchLeases(ctx context.Context) ([]entity.JobLeaseEntity, error) {
+	rows, err := d.db.DB(database.Write).QueryContext(ctx, FetchJobLeases)
+	if err != nil {
+		return nil, goerr.New(err, "dao failed: fetch job leases failed")
+	}
+
+	defer rows.Close()
+	var leases []entity.JobLeaseEntity
+	for rows.Next() {
+		var lease entity.JobLeaseEntity
+		if err := rows.Scan(&lease.JobName, &lease.Holder, &lease.AcquiredAt, &lease.RenewedAt, &lease.ExpiresAt, &lease.Expired); err != nil {
+			return nil, goerr.New(err, "dao failed: scanning job lease failed")
+		}
+		leases = append(leases, lease)
+	}
+	return leases, nil
+}
@@ -0,0 +1,215 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/goerr"
+)
+
+type JobRunDAO interface {
+	StartRun(ctx context.Context, run entity.JobRunEntity, staleAfter time.Duration) (entity.JobRunEntity, bool, error)
+	UpdateProgress(ctx context.Context, run entity.JobRunEntity, failures []entity.JobRunFailureEntity) (bool, error)
+	FinishRun(ctx context.Context, run entity.JobRunEntity, failures []entity.JobRunFailureEntity) error
+	RequestCancel(ctx context.Context, id int64) (bool, error)
+	FetchRun(ctx context.Context, id int64) (*entity.JobRunEntity, error)
+	FetchRuns(ctx context.Context, filter model.JobRunFilter) ([]entity.JobRunEntity, error)
+	FetchResumableRun(ctx context.Context, jobName string, staleAfter time.Duration, startedAfter *time.Time) (*entity.JobRunEntity, error)
+	FetchFailedClients(ctx context.Context, runID int64) ([]string, error)
+}
+
+type jobRunDAOImpl struct {
+	db *database.Router
+}
+
+func NewJobRunDAO(db *database.Router) JobRunDAO {
+	return &jobRunDAOImpl{db: db}
+}
+
+// StartRun records the run as running. For an exclusive job it first fails the runs that stopped heartbeating for
+// staleAfter, and returns false when another run of the job is still running.
+func (d *jobRunDAOImpl) StartRun(ctx context.Context, run entity.JobRunEntity, staleAfter time.Duration) (entity.JobRunEntity, bool, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return run, false, goerr.New(err, "dao failed: unable to begin job run transaction")
+	}
+	defer tx.Rollback()
+
+	if run.Exclusive {
+		_, err = tx.ExecContext(ctx, AbandonStaleJobRuns, run.JobName, staleAfter.Seconds())
+		if err != nil {
+			return run, false, goerr.New(err, fmt.Sprintf("dao failed: abandoning stale runs failed for job: %s", run.JobName))
+		}
+	}
+	err = tx.QueryRowContext(ctx, InsertJobRun, run.JobName, run.Trigger, run.Refresher, run.Instance, run.RequestedBy, run.Exclusive, run.Mode, run.SourceRunID, run.Cursor).Scan(&run.ID, &run.StartedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return run, false, nil
+		}
+		return run, false, goerr.New(err, fmt.Sprintf("dao failed: job run insert failed for job: %s", run.JobName))
+	}
+
+	if err = tx.Commit(); err != nil {
+		return run, false, goerr.New(err, "dao failed: job run transaction commit failed")
+	}
+	return run, true, nil
+}
+
+// UpdateProgress stores the counters, cursor and new failures of the running run as its heartbeat, returns whether
+// its cancel was requested
+func (d *jobRunDAOImpl) UpdateProgress(ctx context.Context, run entity.JobRunEntity, failures []entity.JobRunFailureEntity) (bool, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, goerr.New(err, "dao failed: unable to begin job run transaction")
+	}
+	defer tx.Rollback()
+
+	if err = insertJobRunFailures(ctx, tx, failures); err != nil {
+		return false, goerr.New(err, fmt.Sprintf("dao failed: job run %d failures insert failed", run.ID))
+	}
+	var cancelRequested bool
+	err = tx.QueryRowContext(ctx, UpdateJobRunProgress, run.ID, run.ClientsTotal, run.ClientsProcessed, run.Successes, run.UpstreamErrors, run.InvalidClients, run.Cursor).Scan(&cancelRequested)
+	if err != nil {
+		return false, goerr.New(err, fmt.Sprintf("dao failed: job run %d progress update failed", run.ID))
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, goerr.New(err, "dao failed: job run transaction commit failed")
+	}
+	return cancelRequested, nil
+}
+
+func (d *jobRunDAOImpl) FinishRun(ctx context.Context, run entity.JobRunEntity, failures []entity.JobRunFailureEntity) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return goerr.New(err, "dao failed: unable to begin job run transaction")
+	}
+	defer tx.Rollback()
+
+	if err = insertJobRunFailures(ctx, tx, failures); err != nil {
+		return goerr.New(err, fmt.Sprintf("dao failed: job run %d failures insert failed", run.ID))
+	}
+	_, err = tx.ExecContext(ctx, FinishJobRun, run.ID, run.Status, run.ClientsTotal, run.ClientsProcessed, run.Successes, run.UpstreamErrors, run.InvalidClients, run.Error,
+		run.ThroughputPerSecond, run.LatencyAvgMs, run.LatencyP95Ms, run.Cursor)
+	if err != nil {
+		return goerr.New(err, fmt.Sprintf("dao failed: job run %d update failed", run.ID))
+	}
+
+	if err = tx.Commit(); err != nil {
+		return goerr.New(err, "dao failed: job run transaction commit failed")
+	}
+	return nil
+}
+
+func insertJobRunFailures(ctx context.Context, tx *database.Tx, failures []entity.JobRunFailureEntity) error {
+	if len(failures) == 0 {
+		return nil
+	}
+	var queryBuilder strings.Builder
+	queryBuilder.WriteString(InsertJobRunFailures)
+	values := []interface{}{}
+	valueStrings := []string{}
+	paramIndex := 1
+	for _, failure := range failures {
+		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, NULLIF($%d, ''))", paramIndex, paramIndex+1, paramIndex+2))
+		values = append(values, failure.RunID, failure.ClientCode, failure.ApiError)
+		paramIndex += 3
+	}
+	queryBuilder.WriteString(strings.Join(valueStrings, ", "))
+	queryBuilder.WriteString(UpsertJobRunFailures)
+
+	_, err := tx.ExecContext(ctx, queryBuilder.String(), values...)
+	return err
+}
+
+// RequestCancel flags the run for cancellation, false when the run does not exist or is not running
+func (d *jobRunDAOImpl) RequestCancel(ctx context.Context, id int64) (bool, error) {
+	result, err := d.db.ExecContext(ctx, RequestJobRunCancel, id)
+	if err != nil {
+		return false, goerr.New(err, fmt.Sprintf("dao failed: job run %d cancel request failed", id))
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, goerr.New(err, "dao failed: unable to read cancelled job runs")
+	}
+	return updated > 0, nil
+}
+
+// FetchRun returns nil when the run does not exist
+func (d *jobRunDAOImpl) FetchRun(ctx context.Context, id int64) (*entity.JobRunEntity, error) {
+	rows, err := d.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchJobRun, id)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch job run %d failed", id))
+	}
+	runs, err := scanJobRuns(rows)
+	if err != nil || len(runs) == 0 {
+		return nil, err
+	}
+	return &runs[0], nil
+}
+
+// FetchRuns returns the latest runs matching the filter first
+func (d *jobRunDAOImpl) FetchRuns(ctx context.Context, filter model.JobRunFilter) ([]entity.JobRunEntity, error) {
+	rows, err := d.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchJobRuns, filter.JobName, filter.Status, filter.Trigger, filter.From, filter.To, filter.Limit)
+	if err != nil {
+		return nil, goerr.New(err, "dao failed: fetch job runs failed")
+	}
+	return scanJobRuns(rows)
+}
+
+// FetchResumableRun returns the latest run of the job when it was interrupted midway through a sweep, else nil. A run
+// started before startedAfter is not resumable, nil startedAfter does not limit the age.
+func (d *jobRunDAOImpl) FetchResumableRun(ctx context.Context, jobName string, staleAfter time.Duration, startedAfter *time.Time) (*entity.JobRunEntity, error) {
+	rows, err := d.db.DB(database.Write).QueryContext(ctx, FetchResumableJobRun, jobName, staleAfter.Seconds(), startedAfter)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch resumable run failed for job: %s", jobName))
+	}
+	runs, err := scanJobRuns(rows)
+	if err != nil || len(runs) == 0 {
+		return nil, err
+	}
+	return &runs[0], nil
+}
+
+// FetchFailedClients returns the clients whose upstream call failed in the run, ordered by client code
+func (d *jobRunDAOImpl) FetchFailedClients(ctx context.Context, runID int64) ([]string, error) {
+	rows, err := d.db.DB(database.Write).QueryContext(ctx, FetchJobRunFailedClients, runID)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch failed clients of job run %d failed", runID))
+	}
+	defer rows.Close()
+	var clients []string
+	for rows.Next() {
+		var clientCode string
+		if err := rows.Scan(&clientCode); err != nil {
+			return nil, goerr.New(err, "dao failed: scanning failed client failed")
+		}
+		clients = append(clients, clientCode)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, goerr.New(err, "dao failed: reading failed clients failed")
+	}
+	return clients, nil
+}
+
+func scanJobRuns(rows *sql.Rows) ([]entity.JobRunEntity, error) {
+	defer rows.Close()
+	var runs []entity.JobRunEntity
+	for rows.Next() {
+		var run entity.JobRunEntity
+		err := rows.Scan(&run.ID, &run.JobName, &run.Trigger, &run.Refresher, &run.Instance, &run.Status, &run.StartedAt, &run.FinishedAt, &run.ClientsTotal, &run.ClientsProcessed, &run.Successes,
+			&run.UpstreamErrors, &run.InvalidClients, &run.Error, &run.CancelRequested, &run.RequestedBy,
+			&run.ThroughputPerSecond, &run.LatencyAvgMs, &run.LatencyP95Ms, &run.Cursor, &run.Mode, &run.SourceRunID)
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: scanning job run failed")
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
@@ -0,0 +1,116 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/goerr"
+)
+
+type JourneyDAO interface {
+	ApplyEvent(ctx context.Context, event entity.WebhookEvent, create bool, transition func(state string) string) (bool, error)
+	FetchClientJourneys(ctx context.Context, provider string, clientCode string) ([]entity.JourneyEntity, error)
+	FetchClientJourney(ctx context.Context, provider string, clientCode string, trackingId string) (*entity.JourneyEntity, error)
+	FetchJourneyEvents(ctx context.Context, provider string, clientCode string, trackingId string) ([]entity.WebhookEvent, error)
+}
+
+type journeyDAOImpl struct {
+	db *database.Router
+}
+
+func NewJourneyDAO(db *database.Router) JourneyDAO {
+	return &journeyDAOImpl{db: db}
+}
+
+// ApplyEvent moves the journey of the event to the state returned by transition, as of the time the event was created.
+// A journey seen for the first time is created in the initiated state when create is set, otherwise the event is not
+// applied and false is returned. The journey row is locked while the transition is applied, so concurrent events are
+// serialized.
+func (d *journeyDAOImpl) ApplyEvent(ctx context.Context, event entity.WebhookEvent, create bool, transition func(state string) string) (bool, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, goerr.New(err, "dao failed: unable to begin journey transaction")
+	}
+	defer tx.Rollback()
+
+	if create {
+		_, err = tx.ExecContext(ctx, InitJourney, event.TrackingId, event.Vendor, event.ClientCode, constants.JourneyStateInitiated, event.UpdatedBy)
+		if err != nil {
+			return false, goerr.New(err, fmt.Sprintf("dao failed: journey init failed for trackingId: %s", event.TrackingId))
+		}
+	}
+
+	var state string
+	err = tx.QueryRowContext(ctx, FetchJourneyStateForUpdate, event.Vendor, event.TrackingId).Scan(&state)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, goerr.New(err, fmt.Sprintf("dao failed: journey state fetch failed for trackingId: %s", event.TrackingId))
+	}
+
+	_, err = tx.ExecContext(ctx, UpdateJourneyState, event.Vendor, event.TrackingId, transition(state), event.EventType, event.CreatedAt, event.Institution, event.Amount, event.UpdatedBy)
+	if err != nil {
+		return false, goerr.New(err, fmt.Sprintf("dao failed: journey state update failed for trackingId: %s", event.TrackingId))
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, goerr.New(err, "dao failed: journey transaction commit failed")
+	}
+	return true, nil
+}
+
+func (d *journeyDAOImpl) FetchClientJourneys(ctx context.Context, provider string, clientCode string) ([]entity.JourneyEntity, error) {
+	var journeys []entity.JourneyEntity
+	rows, err := d.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchClientJourneys, clientCode, provider)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch journeys failed for clientCode: %s", clientCode))
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		journey := entity.JourneyEntity{ClientCode: clientCode, Provider: provider}
+		err := rows.Scan(&journey.TrackingId, &journey.State, &journey.LastEventType, &journey.LastEventAt, &journey.Institution, &journey.Amount, &journey.CreatedAt, &journey.UpdatedAt)
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: scanning journey failed")
+		}
+		journeys = append(journeys, journey)
+	}
+	return journeys, nil
+}
+
+// FetchClientJourney returns nil when the journey does not exist or belongs to another client
+func (d *journeyDAOImpl) FetchClientJourney(ctx context.Context, provider string, clientCode string, trackingId string) (*entity.JourneyEntity, error) {
+	journey := entity.JourneyEntity{ClientCode: clientCode, Provider: provider}
+	err := d.db.DB(database.ReadAfterWrite).QueryRowContext(ctx, FetchClientJourney, clientCode, provider, trackingId).Scan(&journey.TrackingId, &journey.State, &journey.LastEventType, &journey.LastEventAt, &journey.Institution, &journey.Amount, &journey.CreatedAt, &journey.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch journey failed for trackingId: %s", trackingId))
+	}
+	return &journey, nil
+}
+
+func (d *journeyDAOImpl) FetchJourneyEvents(ctx context.Context, provider string, clientCode string, trackingId string) ([]entity.WebhookEvent, error) {
+	var events []entity.WebhookEvent
+	rows, err := d.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchJourneyWebhookEvents, provider, clientCode, trackingId)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch journey events failed for trackingId: %s", trackingId))
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		event := entity.WebhookEvent{ClientCode: clientCode, Vendor: provider, TrackingId: trackingId}
+		err := rows.Scan(&event.ID, &event.EventType, &event.Institution, &event.Amount, &event.FailureReason, &event.CreatedAt)
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: scanning journey event failed")
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
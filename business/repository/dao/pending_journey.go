@@ -14,22 +14,23 @@ import (
 type PendingJourneyDAO interface {
 	FetchClientList(ctx context.Context, provider string, instantRefresh bool) ([]string, error)
 	FetchPendingJourneyDetails(ctx context.Context, clientCode string, provider string) (*entity.PendingJourneyEntity, error)
+	HasClient(ctx context.Context, clientCode string, provider string) (bool, error)
 	BatchUpdatePendingJourney(ctx context.Context, pendingJourneyEntities []entity.PendingJourneyEntity) error
 	UpdateRefreshedPendingJourneyClientList(ctx context.Context, provider string, clientList []string) error
-	CleanStaleRecords(ctx context.Context) error
+	CloseStaleRecords(ctx context.Context) error
 }
 
 type pendingJourneyDAOImpl struct {
-	db *sql.DB
+	db *database.Router
 }
 
-func DefaultPendingJourneyDAO() PendingJourneyDAO {
-	return &pendingJourneyDAOImpl{db: database.GetDBPool(true)}
+func NewPendingJourneyDAO(db *database.Router) PendingJourneyDAO {
+	return &pendingJourneyDAOImpl{db: db}
 }
 
 func (p *pendingJourneyDAOImpl) FetchPendingJourneyDetails(ctx context.Context, clientCode string, provider string) (*entity.PendingJourneyEntity, error) {
 	var entity entity.PendingJourneyEntity
-	err := p.db.QueryRowContext(ctx, FetchPendingForClient, clientCode, provider).Scan(&entity.Pending, &entity.Payment, &entity.KYC)
+	err := p.db.DB(database.ReadAfterWrite).QueryRowContext(ctx, FetchPendingForClient, clientCode, provider).Scan(&entity.Pending, &entity.Payment, &entity.KYC, &entity.TrackingId, &entity.BlockingEvent, &entity.FailureReason, &entity.Institution, &entity.Amount)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -40,15 +41,25 @@ func (p *pendingJourneyDAOImpl) FetchPendingJourneyDetails(ctx context.Context,
 	return &entity, nil
 }
 
+// HasClient tells whether the client has an open pending journey, or a closed one flagged to be refreshed
+func (p *pendingJourneyDAOImpl) HasClient(ctx context.Context, clientCode string, provider string) (bool, error) {
+	var exists bool
+	err := p.db.DB(database.ReadAfterWrite).QueryRowContext(ctx, HasPendingJourneyClient, clientCode, provider).Scan(&exists)
+	if err != nil {
+		return false, goerr.New(err, fmt.Sprintf("dao failed: checking pending journey of clientCode: %s", clientCode))
+	}
+	return exists, nil
+}
+
 func (p *pendingJourneyDAOImpl) FetchClientList(ctx context.Context, provider string, instantRefresh bool) ([]string, error) {
 	var clientList []string
 	var rows *sql.Rows
 	var err error
 
 	if instantRefresh {
-		rows, err = p.db.QueryContext(ctx, FetchRefreshPendingJourneyClientListByProvider, provider, false, true)
+		rows, err = p.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchRefreshPendingJourneyClientListByProvider, provider, false, true)
 	} else {
-		rows, err = p.db.QueryContext(ctx, FetchPendingJourneyClientListByProvider, provider, false)
+		rows, err = p.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchPendingJourneyClientListByProvider, provider, false)
 	}
 	if err != nil && err != sql.ErrNoRows {
 		return clientList, err
@@ -126,9 +137,10 @@ func (p *pendingJourneyDAOImpl) UpdateRefreshedPendingJourneyClientList(ctx cont
 	return nil
 }
 
-func (p *pendingJourneyDAOImpl) CleanStaleRecords(ctx context.Context) error {
+// CloseStaleRecords closes the journeys no longer pending, the archival job moves them out after the retention
+func (p *pendingJourneyDAOImpl) CloseStaleRecords(ctx context.Context) error {
 
-	_, err := p.db.ExecContext(ctx, CleanStalePendingJourneyRecords)
+	_, err := p.db.ExecContext(ctx, CloseStalePendingJourneyRecords)
 
 	if err != nil {
 		return err
@@ -18,16 +18,16 @@ type PlansDAO interface {
 }
 
 type plansDAOImpl struct {
-	db *sql.DB
+	db *database.Router
 }
 
-func DefaultPlansDAO() PlansDAO {
-	return &plansDAOImpl{db: database.GetDBPool(true)}
+func NewPlansDAO(db *database.Router) PlansDAO {
+	return &plansDAOImpl{db: db}
 }
 
 func (d *plansDAOImpl) FetchAllFDDetails(ctx context.Context) ([]model.Plan, error) {
 	var allFDs []model.Plan
-	rows, err := d.db.QueryContext(ctx, FetchAllFDDetails)
+	rows, err := d.db.DB(database.Read).QueryContext(ctx, FetchAllFDDetails)
 	if err != nil && err != sql.ErrNoRows {
 		return allFDs, fmt.Errorf("%s%w", "Error while fetching All FD details: ", err)
 	}
@@ -62,7 +62,7 @@ func (d *plansDAOImpl) FetchAllFDDetails(ctx context.Context) ([]model.Plan, err
 }
 func (d *plansDAOImpl) FetchMostBoughtDetails(ctx context.Context) ([]model.Plan, error) {
 	var mostBoughtPlans []model.Plan
-	rows, err := d.db.QueryContext(ctx, FetchMostBoughtPlanDetails)
+	rows, err := d.db.DB(database.Read).QueryContext(ctx, FetchMostBoughtPlanDetails)
 	if err != nil && err != sql.ErrNoRows {
 		return mostBoughtPlans, fmt.Errorf("%s%w", "Error while fetching Most bought FD details: ", err)
 	}
@@ -97,7 +97,7 @@ func (d *plansDAOImpl) FetchMostBoughtDetails(ctx context.Context) ([]model.Plan
 
 func (d *plansDAOImpl) FetchAllPlansDetails(ctx context.Context) ([]model.Plan, error) {
 	var allPlans []model.Plan
-	rows, err := d.db.QueryContext(ctx, FetchAllPlansDetails)
+	rows, err := d.db.DB(database.Read).QueryContext(ctx, FetchAllPlansDetails)
 	if err != nil && err != sql.ErrNoRows {
 		return allPlans, err
 	}
@@ -136,7 +136,7 @@ func (d *plansDAOImpl) FetchFsiPlansDetails(ctx context.Context, fsi string) (mo
 	var compareFsiInterestRate float64
 	var aboutData, calculator []byte
 
-	rows, err := d.db.QueryContext(ctx, FetchFsiPlansDetails, fsi)
+	rows, err := d.db.DB(database.Read).QueryContext(ctx, FetchFsiPlansDetails, fsi)
 	if err != nil && err != sql.ErrNoRows {
 		return fsiPlans, err
 	}
@@ -5,31 +5,36 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/angel-one/fd-core/business/repository/entity"
 	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/fd-core/constants"
 	"github.com/angel-one/goerr"
 )
 
 type PortfolioDAO interface {
 	FindByClient(ctx context.Context, clientCode string, provider string) (*entity.PortfolioEntity, error)
+	HasClient(ctx context.Context, clientCode string, provider string) (bool, error)
 	FetchClientList(ctx context.Context, provider string, instantRefresh bool) ([]string, error)
+	FetchDueClientList(ctx context.Context, provider string, maxStale time.Duration) ([]string, error)
+	FetchRefreshSignals(ctx context.Context, provider string, clientList []string) (map[string]entity.RefreshSignalEntity, error)
 	BatchUpdatePortfolio(ctx context.Context, portfolioUpdateEntities []entity.PortfolioEntity) error
 	UpdateRefreshedPortfolioClientList(ctx context.Context, provider string, clientList []string) error
-	CleanStaleRecords(ctx context.Context) error
+	CloseStaleRecords(ctx context.Context) error
 }
 
 type portfolioDAOImpl struct {
-	db *sql.DB
+	db *database.Router
 }
 
-func DefaultPortfolioDAO() PortfolioDAO {
-	return &portfolioDAOImpl{db: database.GetDBPool(true)}
+func NewPortfolioDAO(db *database.Router) PortfolioDAO {
+	return &portfolioDAOImpl{db: db}
 }
 
 func (p *portfolioDAOImpl) FindByClient(ctx context.Context, clientCode string, provider string) (*entity.PortfolioEntity, error) {
 	var entity entity.PortfolioEntity
-	err := p.db.QueryRowContext(ctx, PortfolioByClientCode, clientCode, provider).Scan(&entity.ClientCode, &entity.TotalActiveDeposits, &entity.Provider, &entity.InvestedValue, &entity.CurrentValue, &entity.InterestEarned, &entity.ReturnsValue, &entity.ReturnsPercentage)
+	err := p.db.DB(database.ReadAfterWrite).QueryRowContext(ctx, PortfolioByClientCode, clientCode, provider).Scan(&entity.ClientCode, &entity.TotalActiveDeposits, &entity.Provider, &entity.InvestedValue, &entity.CurrentValue, &entity.InterestEarned, &entity.ReturnsValue, &entity.ReturnsPercentage)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -45,9 +50,9 @@ func (p *portfolioDAOImpl) FetchClientList(ctx context.Context, provider string,
 	var rows *sql.Rows
 	var err error
 	if instantRefresh {
-		rows, err = p.db.QueryContext(ctx, FetchRefreshPortfolioClientListByProvider, provider, false, true)
+		rows, err = p.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchRefreshPortfolioClientListByProvider, provider, false, true)
 	} else {
-		rows, err = p.db.QueryContext(ctx, FetchPortfolioClientListByProvider, provider, false)
+		rows, err = p.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchPortfolioClientListByProvider, provider, false)
 	}
 
 	if err != nil && err != sql.ErrNoRows {
@@ -69,6 +74,66 @@ func (p *portfolioDAOImpl) FetchClientList(ctx context.Context, provider string,
 	return clientList, nil
 }
 
+// FetchDueClientList returns the valid clients whose refresh is due
+func (p *portfolioDAOImpl) FetchDueClientList(ctx context.Context, provider string, maxStale time.Duration) ([]string, error) {
+	rows, err := p.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchDuePortfolioClientListByProvider, provider, maxStale.Seconds())
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch due portfolio clients failed for provider: %s", provider))
+	}
+	defer rows.Close()
+
+	var clientList []string
+	for rows.Next() {
+		var clientCode string
+		if err := rows.Scan(&clientCode); err != nil {
+			return nil, goerr.New(err, "dao failed: scanning due portfolio client failed")
+		}
+		clientList = append(clientList, clientCode)
+	}
+	return clientList, nil
+}
+
+// HasClient tells whether the client has an open portfolio, or a closed one flagged to be refreshed
+func (p *portfolioDAOImpl) HasClient(ctx context.Context, clientCode string, provider string) (bool, error) {
+	var exists bool
+	err := p.db.DB(database.ReadAfterWrite).QueryRowContext(ctx, HasPortfolioClient, clientCode, provider).Scan(&exists)
+	if err != nil {
+		return false, goerr.New(err, fmt.Sprintf("dao failed: checking portfolio of clientCode: %s", clientCode))
+	}
+	return exists, nil
+}
+
+// FetchRefreshSignals returns the webhook activity of the clients by client code, clients without events are left out
+func (p *portfolioDAOImpl) FetchRefreshSignals(ctx context.Context, provider string, clientList []string) (map[string]entity.RefreshSignalEntity, error) {
+	signals := map[string]entity.RefreshSignalEntity{}
+	if len(clientList) == 0 {
+		return signals, nil
+	}
+
+	placeholders := make([]string, len(clientList))
+	args := []interface{}{provider, constants.EventTDBooked}
+	for i, client := range clientList {
+		placeholders[i] = fmt.Sprintf("$%d", i+3)
+		args = append(args, client)
+	}
+	query := fmt.Sprintf(FetchPortfolioRefreshSignals, strings.Join(placeholders, ", "))
+
+	rows, err := p.db.DB(database.ReadAfterWrite).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch refresh signals failed for provider: %s", provider))
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var signal entity.RefreshSignalEntity
+		if err := rows.Scan(&signal.ClientCode, &signal.LastEventAt, &signal.NextMaturityAt); err != nil {
+			return nil, goerr.New(err, "dao failed: scanning refresh signal failed")
+		}
+		signals[signal.ClientCode] = signal
+	}
+	return signals, nil
+}
+
 func (p *portfolioDAOImpl) BatchUpdatePortfolio(ctx context.Context, portfolioUpdateEntities []entity.PortfolioEntity) error {
 	if len(portfolioUpdateEntities) == 0 {
 		return nil
@@ -82,10 +147,10 @@ func (p *portfolioDAOImpl) BatchUpdatePortfolio(ctx context.Context, portfolioUp
 	paramIndex := 1
 
 	for _, portfolioUpdateEntity := range portfolioUpdateEntities {
-		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
-			paramIndex, paramIndex+1, paramIndex+2, paramIndex+3, paramIndex+4, paramIndex+5, paramIndex+6, paramIndex+7, paramIndex+8, paramIndex+9, paramIndex+10, paramIndex+11))
-		values = append(values, portfolioUpdateEntity.ClientCode, portfolioUpdateEntity.Provider, portfolioUpdateEntity.TotalActiveDeposits, portfolioUpdateEntity.InvestedValue, portfolioUpdateEntity.CurrentValue, portfolioUpdateEntity.InterestEarned, portfolioUpdateEntity.InterestEarned, portfolioUpdateEntity.ReturnsPercentage, portfolioUpdateEntity.CreatedBy, portfolioUpdateEntity.UpdatedBy, portfolioUpdateEntity.InvalidClient, portfolioUpdateEntity.ApiError)
-		paramIndex += 12
+		valueStrings = append(valueStrings, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			paramIndex, paramIndex+1, paramIndex+2, paramIndex+3, paramIndex+4, paramIndex+5, paramIndex+6, paramIndex+7, paramIndex+8, paramIndex+9, paramIndex+10, paramIndex+11, paramIndex+12))
+		values = append(values, portfolioUpdateEntity.ClientCode, portfolioUpdateEntity.Provider, portfolioUpdateEntity.TotalActiveDeposits, portfolioUpdateEntity.InvestedValue, portfolioUpdateEntity.CurrentValue, portfolioUpdateEntity.InterestEarned, portfolioUpdateEntity.InterestEarned, portfolioUpdateEntity.ReturnsPercentage, portfolioUpdateEntity.CreatedBy, portfolioUpdateEntity.UpdatedBy, portfolioUpdateEntity.InvalidClient, portfolioUpdateEntity.ApiError, portfolioUpdateEntity.NextRefreshAt)
+		paramIndex += 13
 	}
 
 	queryBuilder.WriteString(strings.Join(valueStrings, ", "))
@@ -126,9 +191,10 @@ func (p *portfolioDAOImpl) UpdateRefreshedPortfolioClientList(ctx context.Contex
 	return nil
 }
 
-func (p *portfolioDAOImpl) CleanStaleRecords(ctx context.Context) error {
+// CloseStaleRecords closes the portfolios without active deposits, the archival job moves them out after the retention
+func (p *portfolioDAOImpl) CloseStaleRecords(ctx context.Context) error {
 
-	_, err := p.db.ExecContext(ctx, CleanStalePortfolioRecords)
+	_, err := p.db.ExecContext(ctx, CloseStalePortfolioRecords)
 
 	if err != nil {
 		return err
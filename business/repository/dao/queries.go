@@ -1,7 +1,6 @@
 package dao
 
 const (
-	InsertWebookEvent = `INSERT INTO webhook_events (client_code, vendor, tracking_id, event_type, institution, type, amount, tenure_months, tenure_days, failure_reason, created_by, updated_by) VALUES($1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, 0), NULLIF($8, 0), NULLIF($9, 0), NULLIF($10, ''), $11, $12)`
 	FetchAllFDDetails = `WITH RankedPlans AS (
 		SELECT
 			p.fsi AS "fsi",
@@ -137,9 +136,9 @@ const (
 
 	FetchMostBoughtPlanDetails = BaseFetchPlanQuery + " AND p.is_mostbought = true"
 
-	FetchPendingJourneyDetails = `select pending, payment_pending, kyc_pending from pending_journey`
+	FetchPendingJourneyDetails = `select pending, payment_pending, kyc_pending, coalesce(tracking_id, ''), coalesce(blocking_event, ''), coalesce(failure_reason, ''), coalesce(institution, ''), coalesce(amount, 0) from pending_journey`
 
-	FetchPendingForClient = FetchPendingJourneyDetails + ` where client_code = $1 and provider = $2`
+	FetchPendingForClient = FetchPendingJourneyDetails + ` where client_code = $1 and provider = $2 and closed_at is null`
 
 	GetFAQsByTag = `select faq from faqs where tag=$1 and is_active=true`
 
@@ -200,17 +199,31 @@ ORDER BY
 const (
 	SelectPortfolio = " select client_code, total_active_deposits, provider, invested_value, current_value, interest_earned, returns_value, returns_percentage from portfolio"
 
-	PortfolioByClientCode = SelectPortfolio + " where client_code = $1 and provider = $2"
+	PortfolioByClientCode = SelectPortfolio + " where client_code = $1 and provider = $2 and closed_at is null"
 
-	FetchPortfolioClientListByProvider = "select client_code from portfolio where provider = $1 and invalid_client = $2"
+	FetchPortfolioClientListByProvider = "select client_code from portfolio where provider = $1 and invalid_client = $2 and closed_at is null"
+
+	// an open portfolio, or a closed one flagged by a webhook event
+	HasPortfolioClient = "select exists(select 1 from portfolio where client_code = $1 and provider = $2 and (closed_at is null or to_be_refreshed))"
+
+	// open clients due by their schedule, clients flagged by a webhook event, or not refreshed for longer than the max
+	// staleness
+	FetchDuePortfolioClientListByProvider = `select client_code from portfolio where provider = $1 and invalid_client = false and (closed_at is null or to_be_refreshed)
+	and (next_refresh_at is null or next_refresh_at <= current_timestamp or to_be_refreshed or updated_at < current_timestamp - make_interval(secs => $2))`
+
+	// a booked deposit matures its tenure after the booking event
+	FetchPortfolioRefreshSignals = `select client_code, max(created_at),
+	min(created_at + make_interval(months => coalesce(tenure_months, 0)::int, days => coalesce(tenure_days, 0)::int))
+		filter (where event_type = $2 and created_at + make_interval(months => coalesce(tenure_months, 0)::int, days => coalesce(tenure_days, 0)::int) > current_timestamp)
+	from webhook_events where vendor = $1 and client_code IN (%s) group by client_code`
 
 	FetchRefreshPortfolioClientListByProvider = "select client_code from portfolio where provider = $1 and invalid_client = $2 and to_be_refreshed = $3"
 
 	UpdateRefreshPortfolioClientList = "UPDATE portfolio SET to_be_refreshed = false, updated_by = 'portfolio_refresher_api', updated_at = current_timestamp WHERE client_code IN (%s);"
 
-	CleanStalePortfolioRecords = "delete from portfolio where total_active_deposits = 0"
+	CloseStalePortfolioRecords = "update portfolio set closed_at = current_timestamp where total_active_deposits = 0 and closed_at is null"
 
-	InsertClientPortfolio = `INSERT INTO portfolio (client_code, provider, total_active_deposits, invested_value, current_value, interest_earned, returns_value, returns_percentage, created_by, updated_by, invalid_client, api_error)
+	InsertClientPortfolio = `INSERT INTO portfolio (client_code, provider, total_active_deposits, invested_value, current_value, interest_earned, returns_value, returns_percentage, created_by, updated_by, invalid_client, api_error, next_refresh_at)
 	VALUES `
 
 	UpdateClientPortfolio = `ON CONFLICT (client_code, provider) DO UPDATE SET
@@ -223,16 +236,20 @@ const (
 	updated_by = EXCLUDED.updated_by,
 	updated_at =current_timestamp,
 	invalid_client = EXCLUDED.invalid_client,
-	api_error = EXCLUDED.api_error;`
+	api_error = EXCLUDED.api_error,
+	next_refresh_at = coalesce(EXCLUDED.next_refresh_at, portfolio.next_refresh_at);`
 )
 
 // pending journey
 const (
-	FetchPendingJourneyClientListByProvider = "select client_code from pending_journey where provider = $1  and invalid_client = $2"
+	FetchPendingJourneyClientListByProvider = "select client_code from pending_journey where provider = $1  and invalid_client = $2 and closed_at is null"
+
+	// an open pending journey, or a closed one flagged by a webhook event
+	HasPendingJourneyClient = "select exists(select 1 from pending_journey where client_code = $1 and provider = $2 and (closed_at is null or to_be_refreshed))"
 
 	FetchRefreshPendingJourneyClientListByProvider = "select client_code from pending_journey where provider = $1 and invalid_client = $2 and to_be_refreshed = $3"
 
-	CleanStalePendingJourneyRecords = "delete from pending_journey where pending = false"
+	CloseStalePendingJourneyRecords = "update pending_journey set closed_at = current_timestamp where pending = false and closed_at is null"
 
 	UpdateRefreshPendingJourneyClientList = "UPDATE pending_journey SET to_be_refreshed = false, updated_by = 'pending_journey_refresher_api', updated_at = current_timestamp WHERE client_code IN (%s);"
 
@@ -249,3 +266,326 @@ const (
 	invalid_client = EXCLUDED.invalid_client,
 	api_error = EXCLUDED.api_error;`
 )
+
+// webhook events
+const (
+	// an event of an inbox event already registered is skipped
+	InsertWebookEvent = `INSERT INTO webhook_events (client_code, vendor, tracking_id, event_type, institution, type, amount, tenure_months, tenure_days, failure_reason, created_by, updated_by, raw_payload, payload_version, inbox_id) VALUES($1, NULLIF($2, ''), NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, 0), NULLIF($8, 0), NULLIF($9, 0), NULLIF($10, ''), $11, $12, NULLIF($13, '')::jsonb, NULLIF($14, ''), NULLIF($15, 0)) ON CONFLICT (inbox_id) DO NOTHING RETURNING id, created_at`
+
+	FetchRawWebhookEvents = `select id, vendor, raw_payload, coalesce(payload_version, '') from webhook_events where vendor = $1 and raw_payload is not null and id > $2 order by id limit $3`
+
+	UpdateNormalizedWebhookEvent = `UPDATE webhook_events SET client_code = $2, tracking_id = NULLIF($3, ''), event_type = NULLIF($4, ''), institution = NULLIF($5, ''), type = NULLIF($6, ''), amount = NULLIF($7, 0), tenure_months = NULLIF($8, 0), tenure_days = NULLIF($9, 0), failure_reason = NULLIF($10, ''), payload_version = NULLIF($11, ''), updated_by = $12, updated_at = current_timestamp WHERE id = $1`
+)
+
+// webhook replay
+const (
+	FetchReplayClientList = "select distinct client_code from webhook_events where vendor = $1"
+
+	FetchClientWebhookEvents = "select id, coalesce(tracking_id, ''), coalesce(event_type, ''), coalesce(institution, ''), coalesce(amount, 0), coalesce(failure_reason, ''), created_at from webhook_events where vendor = $1 and client_code = $2 order by id"
+
+	FetchReplayPortfolioState = "select total_active_deposits, coalesce(invested_value, 0), coalesce(current_value, 0), to_be_refreshed, closed_at from portfolio where client_code = $1 and provider = $2"
+
+	FetchReplayPendingJourneyState = "select coalesce(pending, false), coalesce(payment_pending, false), coalesce(kyc_pending, false), to_be_refreshed, coalesce(tracking_id, ''), coalesce(blocking_event, ''), coalesce(failure_reason, '') from pending_journey where client_code = $1 and provider = $2 and closed_at is null"
+
+	// an open portfolio keeps the values of the provider, it is only marked to be refreshed from it. A closed one is
+	// reopened with the derived values, the way a missing one is inserted.
+	ReplayClientPortfolio = `INSERT INTO portfolio (client_code, provider, total_active_deposits, invested_value, current_value, interest_earned, returns_value, returns_percentage, created_by, updated_by, to_be_refreshed)
+	VALUES ($1, $2, $3, $4, $5, 0, 0, 0, $6, $6, $7)
+	ON CONFLICT (client_code, provider) DO UPDATE SET
+	total_active_deposits = CASE WHEN portfolio.closed_at IS NULL THEN portfolio.total_active_deposits ELSE EXCLUDED.total_active_deposits END,
+	invested_value = CASE WHEN portfolio.closed_at IS NULL THEN portfolio.invested_value ELSE EXCLUDED.invested_value END,
+	current_value = CASE WHEN portfolio.closed_at IS NULL THEN portfolio.current_value ELSE EXCLUDED.current_value END,
+	interest_earned = CASE WHEN portfolio.closed_at IS NULL THEN portfolio.interest_earned ELSE EXCLUDED.interest_earned END,
+	returns_value = CASE WHEN portfolio.closed_at IS NULL THEN portfolio.returns_value ELSE EXCLUDED.returns_value END,
+	returns_percentage = CASE WHEN portfolio.closed_at IS NULL THEN portfolio.returns_percentage ELSE EXCLUDED.returns_percentage END,
+	closed_at = NULL,
+	updated_by = EXCLUDED.updated_by,
+	updated_at = current_timestamp,
+	to_be_refreshed = EXCLUDED.to_be_refreshed;`
+
+	ReplayClientPendingJourney = `INSERT INTO pending_journey (client_code, provider, pending, payment_pending, kyc_pending, created_by, updated_by, to_be_refreshed, tracking_id, blocking_event, failure_reason, institution, amount)
+	VALUES ($1, $2, $3, $4, $5, $6, $6, $7, $8, $9, $10, NULLIF($11, ''), NULLIF($12, 0))
+	ON CONFLICT (client_code, provider) DO UPDATE SET
+	updated_by = EXCLUDED.updated_by,
+	updated_at = current_timestamp,
+	to_be_refreshed = EXCLUDED.to_be_refreshed,
+	tracking_id = EXCLUDED.tracking_id,
+	blocking_event = EXCLUDED.blocking_event,
+	failure_reason = EXCLUDED.failure_reason,
+	institution = EXCLUDED.institution,
+	amount = EXCLUDED.amount;`
+)
+
+// journeys
+const (
+	InitJourney = `INSERT INTO journeys (tracking_id, provider, client_code, state, created_by, updated_by)
+	VALUES ($1, $2, $3, $4, $5, $5)
+	ON CONFLICT (provider, tracking_id) DO NOTHING;`
+
+	FetchJourneyStateForUpdate = "select state from journeys where provider = $1 and tracking_id = $2 for update"
+
+	UpdateJourneyState = `UPDATE journeys SET state = $3, last_event_type = $4, last_event_at = $5,
+	institution = COALESCE(NULLIF($6, ''), institution), amount = COALESCE(NULLIF($7, 0), amount),
+	updated_by = $8, updated_at = current_timestamp
+	WHERE provider = $1 and tracking_id = $2;`
+
+	FetchClientJourneys = "select tracking_id, state, coalesce(last_event_type, ''), coalesce(last_event_at, created_at), coalesce(institution, ''), coalesce(amount, 0), created_at, updated_at from journeys where client_code = $1 and provider = $2 order by updated_at desc"
+
+	FetchClientJourney = "select tracking_id, state, coalesce(last_event_type, ''), coalesce(last_event_at, created_at), coalesce(institution, ''), coalesce(amount, 0), created_at, updated_at from journeys where client_code = $1 and provider = $2 and tracking_id = $3"
+
+	FetchJourneyWebhookEvents = "select id, coalesce(event_type, ''), coalesce(institution, ''), coalesce(amount, 0), coalesce(failure_reason, ''), created_at from webhook_events where vendor = $1 and client_code = $2 and tracking_id = $3 order by id"
+
+	FetchReplayJourneyStates = "select tracking_id, state from journeys where client_code = $1 and provider = $2"
+
+	ReplayClientJourney = `INSERT INTO journeys (tracking_id, provider, client_code, state, last_event_type, last_event_at, institution, amount, created_by, updated_by)
+	VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, 0), $9, $9)
+	ON CONFLICT (provider, tracking_id) DO UPDATE SET
+	state = EXCLUDED.state,
+	last_event_type = EXCLUDED.last_event_type,
+	last_event_at = EXCLUDED.last_event_at,
+	institution = EXCLUDED.institution,
+	amount = EXCLUDED.amount,
+	updated_by = EXCLUDED.updated_by,
+	updated_at = current_timestamp;`
+)
+
+// webhook inbox
+const (
+	// no row is returned for a delivery already received
+	InsertWebhookInboxEvent = "insert into webhook_inbox (vendor, payload, dedup_key) values ($1, $2::jsonb, $3) on conflict (vendor, dedup_key) do nothing returning id"
+
+	// claims due events and events whose processing lease expired (worker died mid-way), skipping rows claimed by others
+	ClaimWebhookInboxEvents = `UPDATE webhook_inbox SET status = 'processing', attempts = attempts + 1, locked_until = current_timestamp + make_interval(secs => $2), updated_at = current_timestamp
+	WHERE id IN (
+		SELECT id FROM webhook_inbox
+		WHERE (status = 'pending' AND next_attempt_at <= current_timestamp) OR (status = 'processing' AND locked_until < current_timestamp)
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING id, vendor, payload, attempts, created_at`
+
+	CompleteWebhookInboxEvent = "update webhook_inbox set status = 'done', locked_until = null, last_error = null, updated_at = current_timestamp where id = $1"
+
+	RetryWebhookInboxEvent = "update webhook_inbox set status = 'pending', next_attempt_at = $2, locked_until = null, last_error = $3, updated_at = current_timestamp where id = $1"
+
+	DeadWebhookInboxEvent = "update webhook_inbox set status = 'dead', locked_until = null, last_error = $2, updated_at = current_timestamp where id = $1"
+
+	InsertWebhookDeadLetter = "insert into webhook_dead_letters (inbox_id, vendor, payload, attempts, last_error, received_at) values ($1, $2, $3::jsonb, $4, $5, $6)"
+
+	FetchWebhookDeadLetters = "select id, inbox_id, vendor, payload, attempts, coalesce(last_error, ''), received_at, created_at, requeued_at, coalesce(requeued_by, '') from webhook_dead_letters where vendor = $1 and ($2 or requeued_at is null) order by id desc limit $3"
+
+	FetchWebhookDeadLetterForUpdate = "select inbox_id from webhook_dead_letters where id = $1 and requeued_at is null for update"
+
+	// the inbox event may have been purged already, it is restored from the dead letter then
+	RequeueWebhookInboxEvent = `INSERT INTO webhook_inbox (id, vendor, payload) SELECT inbox_id, vendor, payload FROM webhook_dead_letters WHERE id = $1
+		ON CONFLICT (id) DO UPDATE SET status = 'pending', attempts = 0, next_attempt_at = current_timestamp, locked_until = null, updated_at = current_timestamp`
+
+	MarkWebhookDeadLetterRequeued = "update webhook_dead_letters set requeued_at = current_timestamp, requeued_by = $2 where id = $1"
+)
+
+// client state
+const (
+	FetchClientPortfolioState = "select total_active_deposits, coalesce(invested_value, 0), coalesce(current_value, 0), coalesce(interest_earned, 0), coalesce(returns_value, 0), coalesce(returns_percentage, 0), coalesce(invalid_client, false), coalesce(api_error, ''), to_be_refreshed, coalesce(updated_by, ''), updated_at from portfolio where client_code = $1 and provider = $2 and closed_at is null"
+
+	FetchClientPendingJourneyState = "select coalesce(pending, false), coalesce(payment_pending, false), coalesce(kyc_pending, false), coalesce(invalid_client, false), coalesce(api_error, ''), to_be_refreshed, coalesce(tracking_id, ''), coalesce(blocking_event, ''), coalesce(failure_reason, ''), coalesce(institution, ''), coalesce(amount, 0), coalesce(updated_by, ''), updated_at from pending_journey where client_code = $1 and provider = $2 and closed_at is null"
+
+	FetchRecentClientWebhookEvents = "select id, coalesce(tracking_id, ''), coalesce(event_type, ''), coalesce(institution, ''), coalesce(amount, 0), coalesce(failure_reason, ''), coalesce(payload_version, ''), raw_payload, created_at from webhook_events where vendor = $1 and client_code = $2 order by id desc limit $3"
+)
+
+// portfolio reconciliation
+const (
+	// the portfolios flagged to be refreshed are known to differ from the provider until refreshed, they are not compared
+	FetchReconciliationPortfolios = "select client_code, total_active_deposits, coalesce(invested_value, 0), coalesce(current_value, 0) from portfolio where provider = $1 and coalesce(invalid_client, false) = false and closed_at is null and coalesce(to_be_refreshed, false) = false"
+
+	SampleReconciliationPortfolios = FetchReconciliationPortfolios + " order by random() limit $2"
+
+	InsertReconciliationRun = "insert into reconciliation_runs (provider) values ($1) returning id, started_at"
+
+	FinishReconciliationRun = "update reconciliation_runs set finished_at = current_timestamp, clients_compared = $2, clients_failed = $3, discrepancies = $4 where id = $1"
+
+	InsertPortfolioDiscrepancy = `INSERT INTO portfolio_discrepancies (run_id, client_code, provider, severity, db_active_deposits, provider_active_deposits, db_invested_value, provider_invested_value, db_current_value, provider_current_value, difference_percentage)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
+
+	FetchLatestReconciliationRun = "select id, provider, started_at, finished_at, clients_compared, clients_failed, discrepancies from reconciliation_runs where provider = $1 and finished_at is not null order by id desc limit 1"
+
+	FetchDiscrepancySeverityCounts = "select severity, count(*) from portfolio_discrepancies where run_id = $1 group by severity"
+
+	FetchRunDiscrepancies = "select id, client_code, provider, severity, db_active_deposits, provider_active_deposits, db_invested_value, provider_invested_value, db_current_value, provider_current_value, difference_percentage, created_at from portfolio_discrepancies where run_id = $1 order by difference_percentage desc, id limit $2"
+)
+
+// job leases
+const (
+	// takes the lease when it is free, expired or already held by the holder; no row is returned while another instance holds it
+	AcquireJobLease = `INSERT INTO job_leases (job_name, holder, acquired_at, renewed_at, expires_at)
+	VALUES ($1, $2, current_timestamp, current_timestamp, current_timestamp + make_interval(secs => $3))
+	ON CONFLICT (job_name) DO UPDATE SET
+	holder = EXCLUDED.holder,
+	acquired_at = CASE WHEN job_leases.holder = EXCLUDED.holder AND job_leases.expires_at >= current_timestamp THEN job_leases.acquired_at ELSE EXCLUDED.acquired_at END,
+	renewed_at = EXCLUDED.renewed_at,
+	expires_at = EXCLUDED.expires_at
+	WHERE job_leases.holder = EXCLUDED.holder OR job_leases.expires_at < current_timestamp
+	RETURNING job_name`
+
+	RenewJobLease = "update job_leases set renewed_at = current_timestamp, expires_at = current_timestamp + make_interval(secs => $3) where job_name = $1 and holder = $2"
+
+	// an expired lease is taken over by the next tick of another instance, a released lease expires $3 seconds after it
+	// was acquired at the earliest
+	ReleaseJobLease = `update job_leases set expires_at = greatest(current_timestamp, acquired_at + make_interval(secs => $3))
+	where job_name = $1 and holder = $2 and expires_at > current_timestamp`
+
+	ReleaseJobLeases = "update job_leases set expires_at = current_timestamp where holder = $1"
+
+	FetchJobLeases = "select job_name, holder, acquired_at, renewed_at, expires_at, expires_at < current_timestamp from job_leases order by job_name"
+)
+
+// job runs
+const (
+	// no row is returned while another run of an exclusive job is running
+	InsertJobRun = `insert into job_runs (job_name, "trigger", refresher, instance, status, requested_by, exclusive, mode, source_run_id, "cursor")
+	values ($1, $2, NULLIF($3, ''), $4, 'running', NULLIF($5, ''), $6, $7, NULLIF($8, 0), NULLIF($9, ''))
+	on conflict (job_name) where status = 'running' and exclusive do nothing
+	returning id, started_at`
+
+	// a running run whose instance stopped heartbeating died with it
+	AbandonStaleJobRuns = "update job_runs set status = 'failed', finished_at = current_timestamp, error = 'abandoned: no heartbeat' where job_name = $1 and status = 'running' and heartbeat_at < current_timestamp - make_interval(secs => $2)"
+
+	// returns whether a cancel of the run was requested
+	UpdateJobRunProgress = `update job_runs set heartbeat_at = current_timestamp, clients_total = $2, clients_processed = $3, successes = $4, upstream_errors = $5, invalid_clients = $6, "cursor" = NULLIF($7, '')
+	where id = $1 returning cancel_requested`
+
+	FinishJobRun = `update job_runs set status = $2, finished_at = current_timestamp, heartbeat_at = current_timestamp, clients_total = $3, clients_processed = $4, successes = $5, upstream_errors = $6, invalid_clients = $7, error = NULLIF($8, ''),
+	throughput_per_second = $9, latency_avg_ms = $10, latency_p95_ms = $11, "cursor" = NULLIF($12, '') where id = $1`
+
+	InsertJobRunFailures = "insert into job_run_failures (run_id, client_code, api_error) values "
+
+	UpsertJobRunFailures = " on conflict (run_id, client_code) do update set api_error = EXCLUDED.api_error, created_at = current_timestamp"
+
+	RequestJobRunCancel = "update job_runs set cancel_requested = true where id = $1 and status = 'running'"
+
+	SelectJobRuns = `select id, job_name, "trigger", coalesce(refresher, ''), instance, status, started_at, finished_at, clients_total, clients_processed, successes, upstream_errors, invalid_clients, coalesce(error, ''), cancel_requested, coalesce(requested_by, ''),
+	coalesce(throughput_per_second, 0), coalesce(latency_avg_ms, 0), coalesce(latency_p95_ms, 0), coalesce("cursor", ''), mode, coalesce(source_run_id, 0) from job_runs`
+
+	FetchJobRun = SelectJobRuns + " where id = $1"
+
+	// empty filters match every run
+	FetchJobRuns = SelectJobRuns + `
+	where ($1 = '' or job_name = $1) and ($2 = '' or status = $2) and ($3 = '' or "trigger" = $3) and ($4::timestamptz is null or started_at >= $4) and ($5::timestamptz is null or started_at < $5)
+	order by id desc limit $6`
+
+	// the latest run of the job when it died or failed midway through a full sweep, a run still marked running is
+	// resumable once it missed its heartbeats for $2 seconds
+	FetchResumableJobRun = SelectJobRuns + `
+	where id = (select max(id) from job_runs where job_name = $1) and "cursor" is not null and mode in ('full', 'resume')
+	and (status = 'failed' or (status = 'running' and heartbeat_at < current_timestamp - make_interval(secs => $2)))
+	and ($3::timestamptz is null or started_at >= $3)`
+
+	// the failed clients of the run and, for a resumed run, of the runs it resumed
+	FetchJobRunFailedClients = `with recursive chain (id, mode, source_run_id) as (
+		select id, mode, source_run_id from job_runs where id = $1
+		union all
+		select r.id, r.mode, r.source_run_id from job_runs r join chain on r.id = chain.source_run_id where chain.mode = 'resume'
+	)
+	select distinct f.client_code from job_run_failures f join chain on f.run_id = chain.id order by f.client_code`
+)
+
+// job config
+const (
+	FetchJobConfigs = "select job_name, enabled, coalesce(schedule, ''), coalesce(batch_size, 0), coalesce(provider, ''), concurrency, updated_by, updated_at from job_config order by job_name"
+
+	UpsertJobConfig = `INSERT INTO job_config (job_name, enabled, schedule, batch_size, provider, concurrency, updated_by)
+	VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, 0), NULLIF($5, ''), $6, $7)
+	ON CONFLICT (job_name) DO UPDATE SET
+	enabled = EXCLUDED.enabled,
+	schedule = EXCLUDED.schedule,
+	batch_size = EXCLUDED.batch_size,
+	provider = EXCLUDED.provider,
+	concurrency = EXCLUDED.concurrency,
+	updated_by = EXCLUDED.updated_by,
+	updated_at = current_timestamp
+	RETURNING updated_at`
+)
+
+// invalid clients
+const (
+	// the clients invalid in an open portfolio or pending_journey whose revalidation is due
+	FetchDueInvalidClients = `select client_code from portfolio where provider = $1 and invalid_client and closed_at is null
+	and (next_revalidation_at is null or next_revalidation_at <= current_timestamp)
+	union
+	select client_code from pending_journey where provider = $1 and invalid_client and closed_at is null
+	and (next_revalidation_at is null or next_revalidation_at <= current_timestamp)
+	order by client_code limit $2`
+
+	// a cleared client is picked up by the next instant refresh, $5 is the first revalidation of a client set invalid
+	SetPortfolioInvalidClient = `update portfolio set invalid_client = $3, revalidation_attempts = 0, next_revalidation_at = $5::timestamptz, to_be_refreshed = to_be_refreshed or not $3,
+	updated_by = $4, updated_at = current_timestamp where client_code = $1 and provider = $2 and coalesce(invalid_client, false) <> $3`
+
+	SetPendingJourneyInvalidClient = `update pending_journey set invalid_client = $3, revalidation_attempts = 0, next_revalidation_at = $5::timestamptz, to_be_refreshed = to_be_refreshed or not $3,
+	updated_by = $4, updated_at = current_timestamp where client_code = $1 and provider = $2 and coalesce(invalid_client, false) <> $3`
+
+	// the wait after an attempt is $3 seconds doubled with every earlier attempt, up to $4 seconds
+	DeferPortfolioRevalidation = `update portfolio set revalidation_attempts = revalidation_attempts + 1,
+	next_revalidation_at = current_timestamp + make_interval(secs => least($3::float8 * power(2, revalidation_attempts), $4::float8)) where client_code = $1 and provider = $2 and invalid_client and closed_at is null`
+
+	DeferPendingJourneyRevalidation = `update pending_journey set revalidation_attempts = revalidation_attempts + 1,
+	next_revalidation_at = current_timestamp + make_interval(secs => least($3::float8 * power(2, revalidation_attempts), $4::float8)) where client_code = $1 and provider = $2 and invalid_client and closed_at is null`
+
+	InsertInvalidClientAudit = `insert into invalid_client_audit (client_code, provider, "scope", invalid, reason, changed_by) values ($1, $2, $3, $4, $5, $6)`
+
+	FetchInvalidClientAudit = `select id, client_code, provider, "scope", invalid, reason, changed_by, created_at from invalid_client_audit where client_code = $1 and provider = $2 order by id desc limit $3`
+)
+
+// client refresh queue
+const (
+	// every event pushes the refresh $3 seconds out, but no further than $4 seconds after the first queued event
+	EnqueueClientRefresh = `insert into client_refresh_queue (client_code, provider, due_at) values ($1, $2, current_timestamp + make_interval(secs => $3))
+	on conflict (client_code, provider) do update set events = client_refresh_queue.events + 1, last_event_at = current_timestamp,
+	due_at = least(current_timestamp + make_interval(secs => $3), client_refresh_queue.first_event_at + make_interval(secs => $4))`
+
+	// claims due refreshes and refreshes whose lease expired, skipping rows claimed by others
+	ClaimClientRefreshes = `UPDATE client_refresh_queue SET attempts = attempts + 1, locked_until = current_timestamp + make_interval(secs => $2)
+	WHERE (client_code, provider) IN (
+		SELECT client_code, provider FROM client_refresh_queue
+		WHERE due_at <= current_timestamp AND (locked_until IS NULL OR locked_until < current_timestamp)
+		ORDER BY due_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING client_code, provider, events, attempts`
+
+	// extends the lease of claimed refreshes still running, the finished ones are unlocked or deleted already
+	RenewClientRefreshes = "update client_refresh_queue set locked_until = current_timestamp + make_interval(secs => $1) where locked_until is not null and (client_code, provider) in (%s)"
+
+	// no row is deleted when an event arrived during the refresh
+	CompleteClientRefresh = "delete from client_refresh_queue where client_code = $1 and provider = $2 and events = $3"
+
+	// the events seen by the finished refresh are done, the ones after it wait for their due_at
+	ReleaseClientRefresh = "update client_refresh_queue set events = events - $3, first_event_at = last_event_at, attempts = 0, locked_until = null, last_error = null where client_code = $1 and provider = $2"
+
+	RetryClientRefresh = "update client_refresh_queue set due_at = $3, locked_until = null, last_error = $4 where client_code = $1 and provider = $2"
+
+	DropClientRefresh = "delete from client_refresh_queue where client_code = $1 and provider = $2"
+)
+
+// closed record archival
+const (
+	// moves up to $2 rows closed longer than $1 seconds ago into the archive, returns the rows moved
+	ArchiveClosedPortfolios = `WITH moved AS (
+		DELETE FROM portfolio WHERE id IN (
+			SELECT id FROM portfolio WHERE closed_at < current_timestamp - make_interval(secs => $1) ORDER BY closed_at LIMIT $2 FOR UPDATE SKIP LOCKED
+		) RETURNING *
+	)
+	INSERT INTO portfolio_archive (client_code, provider, closed_at, record) SELECT client_code, provider, closed_at, to_jsonb(moved) FROM moved`
+
+	ArchiveClosedPendingJourneys = `WITH moved AS (
+		DELETE FROM pending_journey WHERE id IN (
+			SELECT id FROM pending_journey WHERE closed_at < current_timestamp - make_interval(secs => $1) ORDER BY closed_at LIMIT $2 FOR UPDATE SKIP LOCKED
+		) RETURNING *
+	)
+	INSERT INTO pending_journey_archive (client_code, provider, closed_at, record) SELECT client_code, provider, closed_at, to_jsonb(moved) FROM moved`
+
+	// deletes up to $2 inbox events done or dead longer than $1 seconds ago, a dead one stays in its dead letter
+	PurgeFinishedWebhookInboxEvents = `DELETE FROM webhook_inbox WHERE id IN (
+		SELECT id FROM webhook_inbox WHERE status IN ('done', 'dead') AND updated_at < current_timestamp - make_interval(secs => $1) LIMIT $2 FOR UPDATE SKIP LOCKED
+	)`
+)
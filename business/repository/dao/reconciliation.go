@@ -0,0 +1,142 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/goerr"
+)
+
+type ReconciliationDAO interface {
+	FetchPortfolios(ctx context.Context, provider string, sampleSize int) ([]entity.PortfolioEntity, error)
+	StartRun(ctx context.Context, provider string) (entity.ReconciliationRunEntity, error)
+	FinishRun(ctx context.Context, run entity.ReconciliationRunEntity, discrepancies []entity.PortfolioDiscrepancyEntity) error
+	FetchLatestRun(ctx context.Context, provider string) (*entity.ReconciliationRunEntity, error)
+	FetchSeverityCounts(ctx context.Context, runID int64) (map[string]int, error)
+	FetchRunDiscrepancies(ctx context.Context, runID int64, limit int) ([]entity.PortfolioDiscrepancyEntity, error)
+}
+
+type reconciliationDAOImpl struct {
+	db *database.Router
+}
+
+func NewReconciliationDAO(db *database.Router) ReconciliationDAO {
+	return &reconciliationDAOImpl{db: db}
+}
+
+// FetchPortfolios returns the portfolios of the valid clients, a random sample of them when sampleSize is positive
+func (d *reconciliationDAOImpl) FetchPortfolios(ctx context.Context, provider string, sampleSize int) ([]entity.PortfolioEntity, error) {
+	var rows *sql.Rows
+	var err error
+	if sampleSize > 0 {
+		rows, err = d.db.DB(database.ReadAfterWrite).QueryContext(ctx, SampleReconciliationPortfolios, provider, sampleSize)
+	} else {
+		rows, err = d.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchReconciliationPortfolios, provider)
+	}
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch reconciliation portfolios failed for provider: %s", provider))
+	}
+
+	defer rows.Close()
+	var portfolios []entity.PortfolioEntity
+	for rows.Next() {
+		portfolio := entity.PortfolioEntity{Provider: provider}
+		err := rows.Scan(&portfolio.ClientCode, &portfolio.TotalActiveDeposits, &portfolio.InvestedValue, &portfolio.CurrentValue)
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: scanning reconciliation portfolio failed")
+		}
+		portfolios = append(portfolios, portfolio)
+	}
+	return portfolios, nil
+}
+
+func (d *reconciliationDAOImpl) StartRun(ctx context.Context, provider string) (entity.ReconciliationRunEntity, error) {
+	run := entity.ReconciliationRunEntity{Provider: provider}
+	err := d.db.DB(database.Write).QueryRowContext(ctx, InsertReconciliationRun, provider).Scan(&run.ID, &run.StartedAt)
+	if err != nil {
+		return run, goerr.New(err, fmt.Sprintf("dao failed: reconciliation run insert failed for provider: %s", provider))
+	}
+	return run, nil
+}
+
+// FinishRun stores the discrepancies found and the counters of the run in one transaction
+func (d *reconciliationDAOImpl) FinishRun(ctx context.Context, run entity.ReconciliationRunEntity, discrepancies []entity.PortfolioDiscrepancyEntity) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return goerr.New(err, "dao failed: unable to begin reconciliation transaction")
+	}
+	defer tx.Rollback()
+
+	for _, discrepancy := range discrepancies {
+		_, err = tx.ExecContext(ctx, InsertPortfolioDiscrepancy, run.ID, discrepancy.ClientCode, discrepancy.Provider, discrepancy.Severity, discrepancy.DBActiveDeposits, discrepancy.ProviderActiveDeposits,
+			discrepancy.DBInvestedValue, discrepancy.ProviderInvestedValue, discrepancy.DBCurrentValue, discrepancy.ProviderCurrentValue, discrepancy.DifferencePercentage)
+		if err != nil {
+			return goerr.New(err, fmt.Sprintf("dao failed: portfolio discrepancy insert failed for clientCode: %s", discrepancy.ClientCode))
+		}
+	}
+	_, err = tx.ExecContext(ctx, FinishReconciliationRun, run.ID, run.ClientsCompared, run.ClientsFailed, len(discrepancies))
+	if err != nil {
+		return goerr.New(err, fmt.Sprintf("dao failed: reconciliation run %d update failed", run.ID))
+	}
+
+	if err = tx.Commit(); err != nil {
+		return goerr.New(err, "dao failed: reconciliation transaction commit failed")
+	}
+	return nil
+}
+
+// FetchLatestRun returns the latest finished run of the provider, nil when there is none
+func (d *reconciliationDAOImpl) FetchLatestRun(ctx context.Context, provider string) (*entity.ReconciliationRunEntity, error) {
+	var run entity.ReconciliationRunEntity
+	err := d.db.DB(database.ReadAfterWrite).QueryRowContext(ctx, FetchLatestReconciliationRun, provider).Scan(&run.ID, &run.Provider, &run.StartedAt, &run.FinishedAt, &run.ClientsCompared, &run.ClientsFailed, &run.Discrepancies)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch latest reconciliation run failed for provider: %s", provider))
+	}
+	return &run, nil
+}
+
+func (d *reconciliationDAOImpl) FetchSeverityCounts(ctx context.Context, runID int64) (map[string]int, error) {
+	rows, err := d.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchDiscrepancySeverityCounts, runID)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch discrepancy counts failed for run: %d", runID))
+	}
+
+	defer rows.Close()
+	counts := make(map[string]int)
+	for rows.Next() {
+		var severity string
+		var count int
+		if err := rows.Scan(&severity, &count); err != nil {
+			return nil, goerr.New(err, "dao failed: scanning discrepancy count failed")
+		}
+		counts[severity] = count
+	}
+	return counts, nil
+}
+
+// FetchRunDiscrepancies returns the largest discrepancies of the run first
+func (d *reconciliationDAOImpl) FetchRunDiscrepancies(ctx context.Context, runID int64, limit int) ([]entity.PortfolioDiscrepancyEntity, error) {
+	rows, err := d.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchRunDiscrepancies, runID, limit)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch discrepancies failed for run: %d", runID))
+	}
+
+	defer rows.Close()
+	var discrepancies []entity.PortfolioDiscrepancyEntity
+	for rows.Next() {
+		discrepancy := entity.PortfolioDiscrepancyEntity{RunID: runID}
+		err := rows.Scan(&discrepancy.ID, &discrepancy.ClientCode, &discrepancy.Provider, &discrepancy.Severity, &discrepancy.DBActiveDeposits, &discrepancy.ProviderActiveDeposits, &discrepancy.DBInvestedValue,
+			&discrepancy.ProviderInvestedValue, &discrepancy.DBCurrentValue, &discrepancy.ProviderCurrentValue, &discrepancy.DifferencePercentage, &discrepancy.CreatedAt)
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: scanning portfolio discrepancy failed")
+		}
+		discrepancies = append(discrepancies, discrepancy)
+	}
+	return discrepancies, nil
+}
@@ -0,0 +1,169 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/goerr"
+)
+
+type ReplayDAO interface {
+	FetchClientList(ctx context.Context, provider string, clientCode string, from *time.Time, to *time.Time) ([]string, error)
+	FetchClientEvents(ctx context.Context, provider string, clientCode string) ([]entity.WebhookEvent, error)
+	FetchPortfolioState(ctx context.Context, provider string, clientCode string) (*entity.PortfolioEntity, error)
+	FetchPendingJourneyState(ctx context.Context, provider string, clientCode string) (*entity.PendingJourneyEntity, error)
+	FetchJourneyStates(ctx context.Context, provider string, clientCode string) (map[string]string, error)
+	ApplyClientState(ctx context.Context, portfolio *entity.PortfolioEntity, pendingJourney *entity.PendingJourneyEntity, journeys []entity.JourneyEntity) error
+}
+
+type replayDAOImpl struct {
+	db *database.Router
+}
+
+func NewReplayDAO(db *database.Router) ReplayDAO {
+	return &replayDAOImpl{db: db}
+}
+
+// FetchClientList returns the clients having webhook events in the given scope, empty filters are not applied
+func (r *replayDAOImpl) FetchClientList(ctx context.Context, provider string, clientCode string, from *time.Time, to *time.Time) ([]string, error) {
+	var clientList []string
+	var queryBuilder strings.Builder
+	queryBuilder.WriteString(FetchReplayClientList)
+	args := []interface{}{provider}
+
+	if clientCode != "" {
+		args = append(args, clientCode)
+		queryBuilder.WriteString(fmt.Sprintf(" and client_code = $%d", len(args)))
+	}
+	if from != nil {
+		args = append(args, *from)
+		queryBuilder.WriteString(fmt.Sprintf(" and created_at >= $%d", len(args)))
+	}
+	if to != nil {
+		args = append(args, *to)
+		queryBuilder.WriteString(fmt.Sprintf(" and created_at < $%d", len(args)))
+	}
+	queryBuilder.WriteString(" order by client_code")
+
+	rows, err := r.db.DB(database.Write).QueryContext(ctx, queryBuilder.String(), args...)
+	if err != nil {
+		return nil, goerr.New(err, "dao failed: fetch replay client list failed")
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var clientCode string
+		err := rows.Scan(&clientCode)
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: scanning replay client list failed")
+		}
+		clientList = append(clientList, clientCode)
+	}
+	return clientList, nil
+}
+
+// FetchClientEvents returns the complete event history of a client in the order the events were received
+func (r *replayDAOImpl) FetchClientEvents(ctx context.Context, provider string, clientCode string) ([]entity.WebhookEvent, error) {
+	var events []entity.WebhookEvent
+	rows, err := r.db.DB(database.Write).QueryContext(ctx, FetchClientWebhookEvents, provider, clientCode)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch webhook events failed for clientCode: %s", clientCode))
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		event := entity.WebhookEvent{ClientCode: clientCode, Vendor: provider}
+		err := rows.Scan(&event.ID, &event.TrackingId, &event.EventType, &event.Institution, &event.Amount, &event.FailureReason, &event.CreatedAt)
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: scanning webhook event failed")
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// FetchPortfolioState returns nil when the client has no portfolio, a closed one is returned with its ClosedAt
+func (r *replayDAOImpl) FetchPortfolioState(ctx context.Context, provider string, clientCode string) (*entity.PortfolioEntity, error) {
+	portfolio := entity.PortfolioEntity{ClientCode: clientCode, Provider: provider}
+	err := r.db.DB(database.Write).QueryRowContext(ctx, FetchReplayPortfolioState, clientCode, provider).Scan(&portfolio.TotalActiveDeposits, &portfolio.InvestedValue, &portfolio.CurrentValue, &portfolio.ToBeRefreshed, &portfolio.ClosedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch portfolio state failed for clientCode: %s", clientCode))
+	}
+	return &portfolio, nil
+}
+
+// FetchPendingJourneyState returns nil when the client has no open pending journey
+func (r *replayDAOImpl) FetchPendingJourneyState(ctx context.Context, provider string, clientCode string) (*entity.PendingJourneyEntity, error) {
+	pendingJourney := entity.PendingJourneyEntity{ClientCode: clientCode, Provider: provider}
+	err := r.db.DB(database.Write).QueryRowContext(ctx, FetchReplayPendingJourneyState, clientCode, provider).Scan(&pendingJourney.Pending, &pendingJourney.Payment, &pendingJourney.KYC, &pendingJourney.ToBeRefreshed, &pendingJourney.TrackingId, &pendingJourney.BlockingEvent, &pendingJourney.FailureReason)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch pending journey state failed for clientCode: %s", clientCode))
+	}
+	return &pendingJourney, nil
+}
+
+// FetchJourneyStates returns the current state of every journey of the client keyed by tracking id
+func (r *replayDAOImpl) FetchJourneyStates(ctx context.Context, provider string, clientCode string) (map[string]string, error) {
+	states := make(map[string]string)
+	rows, err := r.db.DB(database.Write).QueryContext(ctx, FetchReplayJourneyStates, clientCode, provider)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch journey states failed for clientCode: %s", clientCode))
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var trackingId, state string
+		err := rows.Scan(&trackingId, &state)
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: scanning journey state failed")
+		}
+		states[trackingId] = state
+	}
+	return states, nil
+}
+
+// ApplyClientState writes the derived state of one client in a single transaction, nil entities are left untouched. The
+// derived portfolio is inserted when the client has none and reopened over a closed one, an open one is marked to be
+// refreshed.
+func (r *replayDAOImpl) ApplyClientState(ctx context.Context, portfolio *entity.PortfolioEntity, pendingJourney *entity.PendingJourneyEntity, journeys []entity.JourneyEntity) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return goerr.New(err, "dao failed: unable to begin replay transaction")
+	}
+	defer tx.Rollback()
+
+	if portfolio != nil {
+		_, err = tx.ExecContext(ctx, ReplayClientPortfolio, portfolio.ClientCode, portfolio.Provider, portfolio.TotalActiveDeposits, portfolio.InvestedValue, portfolio.CurrentValue, portfolio.UpdatedBy, portfolio.ToBeRefreshed)
+		if err != nil {
+			return goerr.New(err, fmt.Sprintf("dao failed: replay portfolio write failed for clientCode: %s", portfolio.ClientCode))
+		}
+	}
+	if pendingJourney != nil {
+		_, err = tx.ExecContext(ctx, ReplayClientPendingJourney, pendingJourney.ClientCode, pendingJourney.Provider, pendingJourney.Pending, pendingJourney.Payment, pendingJourney.KYC, pendingJourney.UpdatedBy, pendingJourney.ToBeRefreshed, pendingJourney.TrackingId, pendingJourney.BlockingEvent, pendingJourney.FailureReason, pendingJourney.Institution, pendingJourney.Amount)
+		if err != nil {
+			return goerr.New(err, fmt.Sprintf("dao failed: replay pending journey write failed for clientCode: %s", pendingJourney.ClientCode))
+		}
+	}
+	for _, journey := range journeys {
+		_, err = tx.ExecContext(ctx, ReplayClientJourney, journey.TrackingId, journey.Provider, journey.ClientCode, journey.State, journey.LastEventType, journey.LastEventAt, journey.Institution, journey.Amount, journey.UpdatedBy)
+		if err != nil {
+			return goerr.New(err, fmt.Sprintf("dao failed: replay journey write failed for trackingId: %s", journey.TrackingId))
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return goerr.New(err, "dao failed: replay transaction commit failed")
+	}
+	return nil
+}
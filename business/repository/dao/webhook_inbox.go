@@ -0,0 +1,173 @@
+package dao
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/goerr"
+)
+
+type WebhookInboxDAO interface {
+	EnqueueBatch(ctx context.Context, events []entity.WebhookInboxEvent) ([]bool, error)
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookInboxEvent, error)
+	Complete(ctx context.Context, id int64) error
+	Retry(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+	DeadLetter(ctx context.Context, event entity.WebhookInboxEvent, lastError string) error
+	FetchDeadLetters(ctx context.Context, vendor string, includeRequeued bool, limit int) ([]entity.WebhookDeadLetter, error)
+	Requeue(ctx context.Context, deadLetterID int64, requeuedBy string) (bool, error)
+}
+
+type webhookInboxDAOImpl struct {
+	db *database.Router
+}
+
+func NewWebhookInboxDAO(db *database.Router) WebhookInboxDAO {
+	return &webhookInboxDAOImpl{db: db}
+}
+
+// EnqueueBatch stores the deliveries in one transaction, the result tells for each event whether it was queued (true)
+// or skipped as a duplicate of an earlier delivery with the same dedup key (false)
+func (d *webhookInboxDAOImpl) EnqueueBatch(ctx context.Context, events []entity.WebhookInboxEvent) ([]bool, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, goerr.New(err, "dao failed: unable to begin webhook inbox transaction")
+	}
+	defer tx.Rollback()
+
+	queued := make([]bool, len(events))
+	for i, event := range events {
+		var id int64
+		err := tx.QueryRowContext(ctx, InsertWebhookInboxEvent, event.Vendor, string(event.Payload), event.DedupKey).Scan(&id)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				continue
+			}
+			return nil, goerr.New(err, fmt.Sprintf("dao failed: webhook inbox insert failed for vendor: %s", event.Vendor))
+		}
+		queued[i] = true
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, goerr.New(err, "dao failed: webhook inbox transaction commit failed")
+	}
+	return queued, nil
+}
+
+// Claim marks up to limit due events as processing for the lease duration and returns them
+func (d *webhookInboxDAOImpl) Claim(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookInboxEvent, error) {
+	var events []entity.WebhookInboxEvent
+	rows, err := d.db.DB(database.Write).QueryContext(ctx, ClaimWebhookInboxEvents, limit, lease.Seconds())
+	if err != nil {
+		return nil, goerr.New(err, "dao failed: claiming webhook inbox events failed")
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var event entity.WebhookInboxEvent
+		var payload []byte
+		err := rows.Scan(&event.ID, &event.Vendor, &payload, &event.Attempts, &event.CreatedAt)
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: scanning webhook inbox event failed")
+		}
+		event.Payload = payload
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (d *webhookInboxDAOImpl) Complete(ctx context.Context, id int64) error {
+	_, err := d.db.ExecContext(ctx, CompleteWebhookInboxEvent, id)
+	if err != nil {
+		return goerr.New(err, fmt.Sprintf("dao failed: webhook inbox event %d completion failed", id))
+	}
+	return nil
+}
+
+func (d *webhookInboxDAOImpl) Retry(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	_, err := d.db.ExecContext(ctx, RetryWebhookInboxEvent, id, nextAttemptAt, lastError)
+	if err != nil {
+		return goerr.New(err, fmt.Sprintf("dao failed: webhook inbox event %d retry scheduling failed", id))
+	}
+	return nil
+}
+
+// DeadLetter copies the event into webhook_dead_letters and parks the inbox row, it is kept for de-duplication
+func (d *webhookInboxDAOImpl) DeadLetter(ctx context.Context, event entity.WebhookInboxEvent, lastError string) error {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return goerr.New(err, "dao failed: unable to begin dead letter transaction")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, InsertWebhookDeadLetter, event.ID, event.Vendor, string(event.Payload), event.Attempts, lastError, event.CreatedAt)
+	if err != nil {
+		return goerr.New(err, fmt.Sprintf("dao failed: dead letter insert failed for inbox event %d", event.ID))
+	}
+	_, err = tx.ExecContext(ctx, DeadWebhookInboxEvent, event.ID, lastError)
+	if err != nil {
+		return goerr.New(err, fmt.Sprintf("dao failed: inbox update failed for dead lettered event %d", event.ID))
+	}
+
+	if err = tx.Commit(); err != nil {
+		return goerr.New(err, "dao failed: dead letter transaction commit failed")
+	}
+	return nil
+}
+
+func (d *webhookInboxDAOImpl) FetchDeadLetters(ctx context.Context, vendor string, includeRequeued bool, limit int) ([]entity.WebhookDeadLetter, error) {
+	var deadLetters []entity.WebhookDeadLetter
+	rows, err := d.db.DB(database.ReadAfterWrite).QueryContext(ctx, FetchWebhookDeadLetters, vendor, includeRequeued, limit)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch dead letters failed for vendor: %s", vendor))
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var deadLetter entity.WebhookDeadLetter
+		var payload []byte
+		err := rows.Scan(&deadLetter.ID, &deadLetter.InboxID, &deadLetter.Vendor, &payload, &deadLetter.Attempts, &deadLetter.LastError, &deadLetter.ReceivedAt, &deadLetter.CreatedAt, &deadLetter.RequeuedAt, &deadLetter.RequeuedBy)
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: scanning dead letter failed")
+		}
+		deadLetter.Payload = payload
+		deadLetters = append(deadLetters, deadLetter)
+	}
+	return deadLetters, nil
+}
+
+// Requeue makes the inbox event of a dead letter due again with fresh attempts, restoring it when it was purged. Returns
+// false when the dead letter does not exist or is already requeued
+func (d *webhookInboxDAOImpl) Requeue(ctx context.Context, deadLetterID int64, requeuedBy string) (bool, error) {
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, goerr.New(err, "dao failed: unable to begin requeue transaction")
+	}
+	defer tx.Rollback()
+
+	var inboxID int64
+	err = tx.QueryRowContext(ctx, FetchWebhookDeadLetterForUpdate, deadLetterID).Scan(&inboxID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, goerr.New(err, fmt.Sprintf("dao failed: fetch dead letter %d failed", deadLetterID))
+	}
+
+	_, err = tx.ExecContext(ctx, RequeueWebhookInboxEvent, deadLetterID)
+	if err != nil {
+		return true, goerr.New(err, fmt.Sprintf("dao failed: requeue of inbox event %d failed for dead letter %d", inboxID, deadLetterID))
+	}
+	_, err = tx.ExecContext(ctx, MarkWebhookDeadLetterRequeued, deadLetterID, requeuedBy)
+	if err != nil {
+		return true, goerr.New(err, fmt.Sprintf("dao failed: marking dead letter %d requeued failed", deadLetterID))
+	}
+
+	if err = tx.Commit(); err != nil {
+		return true, goerr.New(err, "dao failed: requeue transaction commit failed")
+	}
+	return true, nil
+}
@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/angel-one/fd-core/business/repository/entity"
 	"github.com/angel-one/fd-core/commons/database"
@@ -10,21 +11,59 @@ import (
 )
 
 type WebhooksEventsDAO interface {
-	SaveNewEvent(ctx context.Context, entity entity.WebhookEvent) error
+	SaveNewEvent(ctx context.Context, event entity.WebhookEvent) (entity.WebhookEvent, bool, error)
+	FetchRawEvents(ctx context.Context, vendor string, afterID int64, limit int) ([]entity.WebhookEvent, error)
+	UpdateNormalizedEvent(ctx context.Context, entity entity.WebhookEvent) error
 }
 
 type webhooksDAOImpl struct {
-	db *sql.DB
+	db *database.Router
 }
 
-func DefaultWebhookEventsDAO() WebhooksEventsDAO {
-	return &webhooksDAOImpl{db: database.GetDBPool(true)}
+func NewWebhookEventsDAO(db *database.Router) WebhooksEventsDAO {
+	return &webhooksDAOImpl{db: db}
 }
 
-func (d *webhooksDAOImpl) SaveNewEvent(ctx context.Context, entity entity.WebhookEvent) error {
-	_, err := d.db.ExecContext(ctx, InsertWebookEvent, entity.ClientCode, entity.Vendor, entity.TrackingId, entity.EventType, entity.Institution, entity.Type, entity.Amount, entity.TenureMonths, entity.TenureDays, entity.FailureReason, entity.CreatedBy, entity.UpdatedBy)
+// SaveNewEvent returns the event with its id and creation time, or false when the inbox event of the event was already
+// saved
+func (d *webhooksDAOImpl) SaveNewEvent(ctx context.Context, event entity.WebhookEvent) (entity.WebhookEvent, bool, error) {
+	err := d.db.DB(database.Write).QueryRowContext(ctx, InsertWebookEvent, event.ClientCode, event.Vendor, event.TrackingId, event.EventType, event.Institution, event.Type, event.Amount, event.TenureMonths, event.TenureDays, event.FailureReason, event.CreatedBy, event.UpdatedBy, string(event.RawPayload), event.PayloadVersion, event.InboxID).Scan(&event.ID, &event.CreatedAt)
+	if err == sql.ErrNoRows {
+		return event, false, nil
+	}
+	if err != nil {
+		return event, false, goerr.New(err, "dao failed: new webhook save failed")
+	}
+	return event, true, nil
+}
+
+// FetchRawEvents returns the next page of events (ordered by id) that have the raw vendor payload stored
+func (d *webhooksDAOImpl) FetchRawEvents(ctx context.Context, vendor string, afterID int64, limit int) ([]entity.WebhookEvent, error) {
+	var events []entity.WebhookEvent
+	rows, err := d.db.DB(database.Write).QueryContext(ctx, FetchRawWebhookEvents, vendor, afterID, limit)
+	if err != nil {
+		return nil, goerr.New(err, fmt.Sprintf("dao failed: fetch raw webhook events failed for vendor: %s", vendor))
+	}
+
+	defer rows.Close()
+	for rows.Next() {
+		var event entity.WebhookEvent
+		var payload []byte
+		err := rows.Scan(&event.ID, &event.Vendor, &payload, &event.PayloadVersion)
+		if err != nil {
+			return nil, goerr.New(err, "dao failed: scanning raw webhook event failed")
+		}
+		event.RawPayload = payload
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// UpdateNormalizedEvent overwrites the normalized columns of an already stored event, raw payload is left untouched
+func (d *webhooksDAOImpl) UpdateNormalizedEvent(ctx context.Context, entity entity.WebhookEvent) error {
+	_, err := d.db.ExecContext(ctx, UpdateNormalizedWebhookEvent, entity.ID, entity.ClientCode, entity.TrackingId, entity.EventType, entity.Institution, entity.Type, entity.Amount, entity.TenureMonths, entity.TenureDays, entity.FailureReason, entity.PayloadVersion, entity.UpdatedBy)
 	if err != nil {
-		return goerr.New(err, "dao failed: new webhook save failed")
+		return goerr.New(err, fmt.Sprintf("dao failed: webhook event %d normalization update failed", entity.ID))
 	}
 	return nil
 }
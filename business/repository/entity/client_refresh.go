@@ -0,0 +1,9 @@
+package entity
+
+// ClientRefreshEntity is a queued refresh of a client, events is the number of events seen when it was claimed
+type ClientRefreshEntity struct {
+	ClientCode string
+	Provider   string
+	Events     int
+	Attempts   int
+}
@@ -0,0 +1,15 @@
+package entity
+
+import "time"
+
+// InvalidClientAuditEntity is a change of the invalid flag of a client in the table of scope
+type InvalidClientAuditEntity struct {
+	ID         int64
+	ClientCode string
+	Provider   string
+	Scope      string
+	Invalid    bool
+	Reason     string
+	ChangedBy  string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+type JobConfigEntity struct {
+	JobName     string
+	Enabled     bool
+	Schedule    string
+	BatchSize   int
+	Provider    string
+	Concurrency int
+	UpdatedBy   string
+	UpdatedAt   time.Time
+}
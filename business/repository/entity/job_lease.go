@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+type JobLeaseEntity struct {
+	JobName    string
+	Holder     string
+	AcquiredAt time.Time
+	RenewedAt  time.Time
+	ExpiresAt  time.Time
+	Expired    bool
+}
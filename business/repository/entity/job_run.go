@@ -0,0 +1,36 @@
+package entity
+
+import "time"
+
+type JobRunEntity struct {
+	ID               int64
+	JobName          string
+	Trigger          string
+	Refresher        string
+	Instance         string
+	Status           string
+	StartedAt        time.Time
+	FinishedAt       *time.Time
+	ClientsTotal     int
+	ClientsProcessed int
+	Successes        int
+	UpstreamErrors   int
+	InvalidClients   int
+	Error            string
+	CancelRequested  bool
+	RequestedBy      string
+	Exclusive        bool
+	Cursor           string
+	Mode             string
+	SourceRunID      int64
+
+	ThroughputPerSecond float64
+	LatencyAvgMs        float64
+	LatencyP95Ms        float64
+}
+
+type JobRunFailureEntity struct {
+	RunID      int64
+	ClientCode string
+	ApiError   string
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type JourneyEntity struct {
+	TrackingId    string
+	ClientCode    string
+	Provider      string
+	State         string
+	LastEventType string
+	LastEventAt   time.Time
+	Institution   string
+	Amount        float64
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	UpdatedBy     string
+}
@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type PendingJourneyEntity struct {
 	ClientCode    string
 	Provider      string
@@ -10,4 +12,11 @@ type PendingJourneyEntity struct {
 	UpdatedBy     string
 	InvalidClient bool
 	ApiError      string
+	ToBeRefreshed bool
+	TrackingId    string
+	BlockingEvent string
+	FailureReason string
+	Institution   string
+	Amount        float64
+	UpdatedAt     time.Time
 }
@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type PortfolioEntity struct {
 	ClientCode          string
 	TotalActiveDeposits int
@@ -13,4 +15,16 @@ type PortfolioEntity struct {
 	UpdatedBy           string
 	InvalidClient       bool
 	ApiError            string
+	ToBeRefreshed       bool
+	UpdatedAt           time.Time
+	NextRefreshAt       *time.Time
+	ClosedAt            *time.Time
+}
+
+// RefreshSignalEntity is the webhook activity of a client deciding its next refresh, the next maturity is of the
+// deposits booked that did not mature yet
+type RefreshSignalEntity struct {
+	ClientCode     string
+	LastEventAt    *time.Time
+	NextMaturityAt *time.Time
 }
@@ -0,0 +1,29 @@
+package entity
+
+import "time"
+
+type ReconciliationRunEntity struct {
+	ID              int64
+	Provider        string
+	StartedAt       time.Time
+	FinishedAt      *time.Time
+	ClientsCompared int
+	ClientsFailed   int
+	Discrepancies   int
+}
+
+type PortfolioDiscrepancyEntity struct {
+	ID                     int64
+	RunID                  int64
+	ClientCode             string
+	Provider               string
+	Severity               string
+	DBActiveDeposits       int
+	ProviderActiveDeposits int
+	DBInvestedValue        float64
+	ProviderInvestedValue  float64
+	DBCurrentValue         float64
+	ProviderCurrentValue   float64
+	DifferencePercentage   float64
+	CreatedAt              time.Time
+}
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookInboxEvent is a raw webhook delivery waiting to be processed
+type WebhookInboxEvent struct {
+	ID        int64
+	Vendor    string
+	Payload   json.RawMessage
+	DedupKey  string
+	Attempts  int
+	CreatedAt time.Time
+}
+
+type WebhookDeadLetter struct {
+	ID         int64
+	InboxID    int64
+	Vendor     string
+	Payload    json.RawMessage
+	Attempts   int
+	LastError  string
+	ReceivedAt time.Time
+	CreatedAt  time.Time
+	RequeuedAt *time.Time
+	RequeuedBy string
+}
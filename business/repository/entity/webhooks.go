@@ -1,16 +1,26 @@
 package entity
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type WebhookEvent struct {
-	ClientCode    string
-	Vendor        string
-	TrackingId    string
-	EventType     string
-	Institution   string
-	Type          string
-	Amount        float64
-	TenureMonths  int
-	TenureDays    int
-	FailureReason string
-	CreatedBy     string
-	UpdatedBy     string
+	ID             int64
+	ClientCode     string
+	Vendor         string
+	TrackingId     string
+	EventType      string
+	Institution    string
+	Type           string
+	Amount         float64
+	TenureMonths   int
+	TenureDays     int
+	FailureReason  string
+	RawPayload     json.RawMessage
+	PayloadVersion string
+	InboxID        int64
+	CreatedBy      string
+	UpdatedBy      string
+	CreatedAt      time.Time
 }
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/goerr"
+)
+
+type ClientRefreshService interface {
+	Enqueue(ctx context.Context, provider string, clientCode string) error
+	ProcessBatch(ctx context.Context) (int, error)
+}
+
+type clientRefreshServiceImpl struct {
+	refreshDAO dao.ClientRefreshDAO
+	refresh    func(ctx context.Context, provider string, clientCode string) error
+}
+
+// NewClientRefreshService refreshes a client with refresh, which stores the portfolio and pending journey of the client
+// from the provider
+func NewClientRefreshService(refreshDAO dao.ClientRefreshDAO, refresh func(ctx context.Context, provider string, clientCode string) error) ClientRefreshService {
+	return &clientRefreshServiceImpl{refreshDAO: refreshDAO, refresh: refresh}
+}
+
+// clientRefreshRetries is how failed refreshes are retried
+type clientRefreshRetries struct {
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+}
+
+// Enqueue queues a debounced refresh of the client, further events of the client push it out
+func (s *clientRefreshServiceImpl) Enqueue(ctx context.Context, provider string, clientCode string) error {
+	settings := config.App().ClientRefresh
+	debounce := time.Duration(settings.DebounceSeconds) * time.Second
+	maxDelay := time.Duration(settings.MaxDelaySeconds) * time.Second
+	if err := s.refreshDAO.Enqueue(ctx, provider, clientCode, debounce, maxDelay); err != nil {
+		return goerr.New(err, "service: queueing client refresh failed")
+	}
+	return nil
+}
+
+// ProcessBatch claims a batch of due refreshes and refreshes the clients. Failed refreshes are retried with an
+// exponential backoff, a refresh out of attempts is dropped and the client left to the instant refresher. Returns
+// the refreshes claimed.
+func (s *clientRefreshServiceImpl) ProcessBatch(ctx context.Context) (int, error) {
+	settings := config.App().ClientRefresh
+	batchSize := int(settings.BatchSize)
+	lease := time.Duration(settings.LeaseSeconds) * time.Second
+	retries := clientRefreshRetries{
+		maxAttempts: int(settings.MaxAttempts),
+		backoff:     time.Duration(settings.BackoffSeconds) * time.Second,
+		maxBackoff:  time.Duration(settings.MaxBackoffSeconds) * time.Second,
+	}
+	refreshes, err := s.refreshDAO.Claim(ctx, batchSize, lease)
+	if err != nil {
+		return 0, goerr.New(err, "service: claiming client refreshes failed")
+	}
+	s.processClaimed(ctx, refreshes, lease, retries)
+	return len(refreshes), nil
+}
+
+// processClaimed refreshes the claimed clients one after the other. The lease of the refreshes not finished yet is
+// renewed while the batch runs, so another replica does not claim them once a long batch outlived the first lease.
+func (s *clientRefreshServiceImpl) processClaimed(ctx context.Context, refreshes []entity.ClientRefreshEntity, lease time.Duration, retries clientRefreshRetries) {
+	var mu sync.Mutex
+	unfinished := refreshes
+	stop := make(chan struct{})
+	heartbeatStopped := make(chan struct{})
+	go func() {
+		defer close(heartbeatStopped)
+		ticker := time.NewTicker(lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+			mu.Lock()
+			renewing := unfinished
+			mu.Unlock()
+			if len(renewing) == 0 {
+				continue
+			}
+			if err := s.refreshDAO.Renew(ctx, renewing, lease); err != nil {
+				log.Error(ctx).Err(err).Msgf("renewing lease of %d client refreshes failed", len(renewing))
+			}
+		}
+	}()
+	defer func() {
+		close(stop)
+		<-heartbeatStopped
+	}()
+
+	for i, refresh := range refreshes {
+		s.process(ctx, refresh, retries)
+		mu.Lock()
+		unfinished = refreshes[i+1:]
+		mu.Unlock()
+	}
+}
+
+func (s *clientRefreshServiceImpl) process(ctx context.Context, refresh entity.ClientRefreshEntity, retries clientRefreshRetries) {
+	err := s.refresh(ctx, refresh.Provider, refresh.ClientCode)
+	if err == nil {
+		if err = s.refreshDAO.Complete(ctx, refresh); err != nil {
+			log.Error(ctx).Err(err).Msgf("unable to complete refresh of clientCode: %s", refresh.ClientCode)
+		}
+		return
+	}
+
+	if refresh.Attempts >= retries.maxAttempts {
+		log.Error(ctx).Err(err).Msgf("dropping refresh of clientCode: %s after %d attempts, left to the instant refresher", refresh.ClientCode, refresh.Attempts)
+		if err = s.refreshDAO.Drop(ctx, refresh); err != nil {
+			log.Error(ctx).Err(err).Msgf("unable to drop refresh of clientCode: %s", refresh.ClientCode)
+		}
+		return
+	}
+
+	delay := inboxRetryDelay(refresh.Attempts, retries.backoff, retries.maxBackoff)
+	log.Warn(ctx).Err(err).Msgf("refresh of clientCode: %s failed on attempt %d, retrying in %s", refresh.ClientCode, refresh.Attempts, delay)
+	if err = s.refreshDAO.Retry(ctx, refresh, time.Now().Add(delay), err.Error()); err != nil {
+		log.Error(ctx).Err(err).Msgf("unable to schedule retry of refresh of clientCode: %s", refresh.ClientCode)
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClientRefreshDAO struct {
+	completed []string
+	retried   map[string]time.Time
+	dropped   []string
+	mu        sync.Mutex
+	renewals  [][]string
+}
+
+func (f *fakeClientRefreshDAO) Enqueue(ctx context.Context, provider string, clientCode string, debounce time.Duration, maxDelay time.Duration) error {
+	return nil
+}
+
+func (f *fakeClientRefreshDAO) Claim(ctx context.Context, limit int, lease time.Duration) ([]entity.ClientRefreshEntity, error) {
+	return nil, nil
+}
+
+func (f *fakeClientRefreshDAO) Renew(ctx context.Context, refreshes []entity.ClientRefreshEntity, lease time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var clients []string
+	for _, refresh := range refreshes {
+		clients = append(clients, refresh.ClientCode)
+	}
+	f.renewals = append(f.renewals, clients)
+	return nil
+}
+
+func (f *fakeClientRefreshDAO) Complete(ctx context.Context, refresh entity.ClientRefreshEntity) error {
+	f.completed = append(f.completed, refresh.ClientCode)
+	return nil
+}
+
+func (f *fakeClientRefreshDAO) Retry(ctx context.Context, refresh entity.ClientRefreshEntity, dueAt time.Time, lastError string) error {
+	f.retried[refresh.ClientCode] = dueAt
+	return nil
+}
+
+func (f *fakeClientRefreshDAO) Drop(ctx context.Context, refresh entity.ClientRefreshEntity) error {
+	f.dropped = append(f.dropped, refresh.ClientCode)
+	return nil
+}
+
+func TestClientRefreshProcess(t *testing.T) {
+	refreshDAO := &fakeClientRefreshDAO{retried: map[string]time.Time{}}
+	s := &clientRefreshServiceImpl{refreshDAO: refreshDAO, refresh: func(ctx context.Context, provider string, clientCode string) error {
+		if clientCode == "A1" {
+			return nil
+		}
+		return errors.New("upswing unavailable")
+	}}
+	retries := clientRefreshRetries{maxAttempts: 3, backoff: 30 * time.Second, maxBackoff: 10 * time.Minute}
+	ctx := context.Background()
+
+	start := time.Now()
+	s.process(ctx, entity.ClientRefreshEntity{ClientCode: "A1", Provider: "upswing", Events: 2, Attempts: 1}, retries)
+	s.process(ctx, entity.ClientRefreshEntity{ClientCode: "B2", Provider: "upswing", Events: 1, Attempts: 2}, retries)
+	s.process(ctx, entity.ClientRefreshEntity{ClientCode: "C3", Provider: "upswing", Events: 1, Attempts: 3}, retries)
+
+	assert.Equal(t, []string{"A1"}, refreshDAO.completed)
+	assert.Equal(t, []string{"C3"}, refreshDAO.dropped)
+	assert.Len(t, refreshDAO.retried, 1)
+	assert.WithinDuration(t, start.Add(time.Minute), refreshDAO.retried["B2"], 5*time.Second)
+}
+
+func TestClientRefreshRenewsLeaseOfLongBatch(t *testing.T) {
+	refreshDAO := &fakeClientRefreshDAO{retried: map[string]time.Time{}}
+	s := &clientRefreshServiceImpl{refreshDAO: refreshDAO, refresh: func(ctx context.Context, provider string, clientCode string) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}}
+	refreshes := []entity.ClientRefreshEntity{{ClientCode: "A1", Provider: "upswing"}, {ClientCode: "B2", Provider: "upswing"},
+		{ClientCode: "C3", Provider: "upswing"}}
+
+	// the batch takes twice the lease
+	s.processClaimed(context.Background(), refreshes, 30*time.Millisecond, clientRefreshRetries{maxAttempts: 3})
+	assert.Equal(t, []string{"A1", "B2", "C3"}, refreshDAO.completed)
+
+	refreshDAO.mu.Lock()
+	defer refreshDAO.mu.Unlock()
+	if assert.NotEmpty(t, refreshDAO.renewals, "the lease must be renewed while the batch runs") {
+		last := refreshDAO.renewals[len(refreshDAO.renewals)-1]
+		assert.NotContains(t, last, "A1", "a finished refresh must not be locked again")
+		assert.Contains(t, last, "C3")
+	}
+}
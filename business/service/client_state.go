@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/fd-core/external"
+	"github.com/angel-one/goerr"
+)
+
+type ClientStateService interface {
+	GetClientState(ctx context.Context, provider string, clientCode string, events int, masked bool) (model.ClientState, error)
+}
+
+type clientStateServiceImpl struct {
+	clientStateDAO dao.ClientStateDAO
+	journeyDAO     dao.JourneyDAO
+	upswing        external.UpSwing
+}
+
+func NewClientStateService(clientStateDAO dao.ClientStateDAO, journeyDAO dao.JourneyDAO, upswing external.UpSwing) ClientStateService {
+	return &clientStateServiceImpl{clientStateDAO: clientStateDAO, journeyDAO: journeyDAO, upswing: upswing}
+}
+
+// GetClientState collects the stored portfolio, pending journey, journeys and recent events of the client along with
+// the live net worth from the provider. With masked the client code is masked and raw payloads are left out.
+func (s *clientStateServiceImpl) GetClientState(ctx context.Context, provider string, clientCode string, events int, masked bool) (model.ClientState, error) {
+	state := model.ClientState{ClientCode: clientCode, Provider: provider, Masked: masked, Journeys: []model.BookingJourney{}, RecentEvents: []model.ClientWebhookEvent{}}
+
+	portfolio, err := s.clientStateDAO.FetchPortfolio(ctx, provider, clientCode)
+	if err != nil {
+		return state, goerr.New(err, "service: fetching client portfolio failed")
+	}
+	if portfolio != nil {
+		state.Portfolio = &model.ClientPortfolioState{TotalActiveDeposits: portfolio.TotalActiveDeposits, InvestedValue: portfolio.InvestedValue, CurrentValue: portfolio.CurrentValue, InterestEarned: portfolio.InterestEarned,
+			ReturnsValue: portfolio.ReturnsValue, ReturnsPercentage: portfolio.ReturnsPercentage, InvalidClient: portfolio.InvalidClient, ApiError: portfolio.ApiError, ToBeRefreshed: portfolio.ToBeRefreshed,
+			UpdatedBy: portfolio.UpdatedBy, UpdatedAt: portfolio.UpdatedAt}
+	}
+
+	pendingJourney, err := s.clientStateDAO.FetchPendingJourney(ctx, provider, clientCode)
+	if err != nil {
+		return state, goerr.New(err, "service: fetching client pending journey failed")
+	}
+	if pendingJourney != nil {
+		state.PendingJourney = &model.ClientPendingJourneyState{Pending: pendingJourney.Pending, PaymentPending: pendingJourney.Payment, KYCPending: pendingJourney.KYC, JourneyID: pendingJourney.TrackingId,
+			BlockingEvent: pendingJourney.BlockingEvent, FailureReason: pendingJourney.FailureReason, Fsi: pendingJourney.Institution, Amount: pendingJourney.Amount, InvalidClient: pendingJourney.InvalidClient,
+			ApiError: pendingJourney.ApiError, ToBeRefreshed: pendingJourney.ToBeRefreshed, UpdatedBy: pendingJourney.UpdatedBy, UpdatedAt: pendingJourney.UpdatedAt}
+	}
+
+	journeys, err := s.journeyDAO.FetchClientJourneys(ctx, provider, clientCode)
+	if err != nil {
+		return state, goerr.New(err, "service: fetching client journeys failed")
+	}
+	for _, journey := range journeys {
+		state.Journeys = append(state.Journeys, toBookingJourney(journey))
+	}
+
+	recentEvents, err := s.clientStateDAO.FetchRecentEvents(ctx, provider, clientCode, events)
+	if err != nil {
+		return state, goerr.New(err, "service: fetching client webhook events failed")
+	}
+	for _, event := range recentEvents {
+		state.RecentEvents = append(state.RecentEvents, model.ClientWebhookEvent{ID: event.ID, EventType: event.EventType, JourneyID: event.TrackingId, Fsi: event.Institution, Amount: event.Amount,
+			FailureReason: event.FailureReason, PayloadVersion: event.PayloadVersion, RawPayload: event.RawPayload, ReceivedAt: event.CreatedAt})
+	}
+
+	// the live call failing is part of the answer, it is reported in the registration status instead of failing the request
+	if provider == constants.UpSwingProvider {
+		netWorth, err := s.upswing.GetNetWorthData(ctx, clientCode)
+		state.LiveNetWorth = netWorth
+		state.Registration = registrationStatus(clientCode, err)
+		if err != nil {
+			log.Warn(ctx).Err(err).Msgf("live net worth call failed for client state of clientCode: %s", clientCode)
+		}
+	} else {
+		state.Registration = model.RegistrationStatus{Status: constants.RegistrationUnknown, Detail: fmt.Sprintf("no live api for provider: %s", provider)}
+	}
+
+	if masked {
+		maskClientState(&state)
+	}
+	return state, nil
+}
+
+// registrationStatus derives the registration of the client at upswing from the error of the live net worth call,
+// the upswing error response body is one of the stacks of the error
+func registrationStatus(clientCode string, err error) model.RegistrationStatus {
+	if err == nil {
+		return model.RegistrationStatus{Status: constants.RegistrationRegistered}
+	}
+	errorCode := external.UpSwingErrorCode(err)
+	if errorCode == constants.ErrClientNotFound || errorCode == fmt.Sprintf(constants.ErrPciNotFound, clientCode) {
+		return model.RegistrationStatus{Status: constants.RegistrationNotRegistered, Detail: errorCode}
+	}
+	if errorCode != "" {
+		return model.RegistrationStatus{Status: constants.RegistrationUnknown, Detail: errorCode}
+	}
+	return model.RegistrationStatus{Status: constants.RegistrationUnknown, Detail: err.Error()}
+}
+
+// maskClientState masks the client code wherever it appears and drops the raw payloads
+func maskClientState(state *model.ClientState) {
+	clientCode := state.ClientCode
+	mask := func(value string) string {
+		if clientCode == "" {
+			return value
+		}
+		return strings.ReplaceAll(value, clientCode, maskClientCode(clientCode))
+	}
+
+	state.ClientCode = maskClientCode(clientCode)
+	state.Registration.Detail = mask(state.Registration.Detail)
+	if state.Portfolio != nil {
+		state.Portfolio.ApiError = mask(state.Portfolio.ApiError)
+	}
+	if state.PendingJourney != nil {
+		state.PendingJourney.ApiError = mask(state.PendingJourney.ApiError)
+		state.PendingJourney.FailureReason = mask(state.PendingJourney.FailureReason)
+	}
+	for i := range state.RecentEvents {
+		state.RecentEvents[i].FailureReason = mask(state.RecentEvents[i].FailureReason)
+		state.RecentEvents[i].RawPayload = nil
+	}
+}
+
+// maskClientCode keeps the first and last two characters, short codes are masked fully
+func maskClientCode(clientCode string) string {
+	if len(clientCode) <= 4 {
+		return strings.Repeat("*", len(clientCode))
+	}
+	return clientCode[:2] + strings.Repeat("*", len(clientCode)-4) + clientCode[len(clientCode)-2:]
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskClientCode(t *testing.T) {
+	assert.Equal(t, "S1****97", maskClientCode("S1614297"))
+	assert.Equal(t, "****", maskClientCode("S161"))
+	assert.Equal(t, "", maskClientCode(""))
+}
+
+func TestMaskClientState(t *testing.T) {
+	state := model.ClientState{
+		ClientCode:     "S1614297",
+		Registration:   model.RegistrationStatus{Detail: "[PCI:S1614297] not found"},
+		Portfolio:      &model.ClientPortfolioState{ApiError: "[PCI:S1614297] not found"},
+		PendingJourney: &model.ClientPendingJourneyState{ApiError: "INTERNAL_CUSTOMER_DETAILS_NOT_FOUND_FOR_PCI"},
+		RecentEvents:   []model.ClientWebhookEvent{{RawPayload: json.RawMessage(`{"pci":"S1614297"}`)}},
+	}
+	maskClientState(&state)
+
+	assert.Equal(t, "S1****97", state.ClientCode)
+	assert.Equal(t, "[PCI:S1****97] not found", state.Registration.Detail)
+	assert.Equal(t, "[PCI:S1****97] not found", state.Portfolio.ApiError)
+	assert.Equal(t, "INTERNAL_CUSTOMER_DETAILS_NOT_FOUND_FOR_PCI", state.PendingJourney.ApiError)
+	assert.Nil(t, state.RecentEvents[0].RawPayload, "raw payloads must not be returned masked")
+}
@@ -17,8 +17,8 @@ type compareServiceImpl struct {
 	compareDAO dao.CompareDAO
 }
 
-func DefaultCompareService() CompareService {
-	return &compareServiceImpl{compareDAO: dao.DefaultCompareDAO()}
+func NewCompareService(compareDAO dao.CompareDAO) CompareService {
+	return &compareServiceImpl{compareDAO: compareDAO}
 }
 
 func (service *compareServiceImpl) GetCompareList(ctx context.Context) (model.FsiList, error) {
@@ -17,8 +17,8 @@ type FAQServiceImpl struct {
 	faqDAO dao.FAQDAO
 }
 
-func DefaultFAQService() FAQService {
-	return &FAQServiceImpl{faqDAO: dao.DefaultFAQDAO()}
+func NewFAQService(faqDAO dao.FAQDAO) FAQService {
+	return &FAQServiceImpl{faqDAO: faqDAO}
 }
 
 func (service *FAQServiceImpl) GetFAQDetails(ctx context.Context, tag string) (model.FAQResponse, error) {
@@ -2,9 +2,14 @@ package service
 
 import (
 	"context"
+	"net/url"
+	"strings"
 
 	"github.com/angel-one/fd-core/business/model"
 	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/constants"
 )
 
 type HomepageService interface {
@@ -16,8 +21,8 @@ type HomepageServiceImpl struct {
 	pendingJourneyDAO dao.PendingJourneyDAO
 }
 
-func DefaultHomepageService() HomepageService {
-	return &HomepageServiceImpl{plansDAO: dao.DefaultPlansDAO(), pendingJourneyDAO: dao.DefaultPendingJourneyDAO()}
+func NewHomepageService(plansDAO dao.PlansDAO, pendingJourneyDAO dao.PendingJourneyDAO) HomepageService {
+	return &HomepageServiceImpl{plansDAO: plansDAO, pendingJourneyDAO: pendingJourneyDAO}
 }
 
 func (service *HomepageServiceImpl) GetHomePageDetails(ctx context.Context, clientCode string, provider string) (model.Homepage, error) {
@@ -38,8 +43,30 @@ func (service *HomepageServiceImpl) GetHomePageDetails(ctx context.Context, clie
 	response.AllFDS = allFDs
 	response.MostBought = mostBoughtPlans
 	if pendingJourney != nil {
-		response.Journey = model.Journey{Pending: pendingJourney.Pending, PendingState: model.PendingState{Payment: pendingJourney.Payment, KYC: pendingJourney.KYC}}
+		resumeLink := config.App().PendingJourney.ResumeLink
+		response.Journey = buildJourney(pendingJourney, resumeLink)
 	}
 
 	return response, nil
 }
+
+// buildJourney adds the blocking step details of the latest failure event, only while the journey is still pending
+func buildJourney(pendingJourney *entity.PendingJourneyEntity, resumeLink string) model.Journey {
+	journey := model.Journey{Pending: pendingJourney.Pending, PendingState: model.PendingState{Payment: pendingJourney.Payment, KYC: pendingJourney.KYC}}
+	if !pendingJourney.Pending || pendingJourney.BlockingEvent == "" {
+		return journey
+	}
+	journey.BlockingStep = constants.PendingJourneyBlockingSteps[pendingJourney.BlockingEvent]
+	journey.FailureReason = pendingJourney.FailureReason
+	journey.JourneyID = pendingJourney.TrackingId
+	journey.Fsi = pendingJourney.Institution
+	journey.Amount = pendingJourney.Amount
+	if resumeLink != "" && journey.JourneyID != "" {
+		journey.ResumeLink = strings.NewReplacer(
+			constants.ResumeLinkJourneyIDField, url.QueryEscape(journey.JourneyID),
+			constants.ResumeLinkStepField, url.QueryEscape(journey.BlockingStep),
+			constants.ResumeLinkFSIField, url.QueryEscape(journey.Fsi),
+		).Replace(resumeLink)
+	}
+	return journey
+}
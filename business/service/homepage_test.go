@@ -0,0 +1,29 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildJourney(t *testing.T) {
+	resumeLink := "angelone://fd/journey/resume?journeyId={journeyId}&step={step}&fsi={fsi}"
+	pendingJourney := &entity.PendingJourneyEntity{Pending: true, KYC: true, TrackingId: "TD 1", BlockingEvent: constants.EventVKYCRetryRequired, FailureReason: "face not visible", Institution: "SMCBIN", Amount: 25000}
+
+	journey := buildJourney(pendingJourney, resumeLink)
+	assert.True(t, journey.PendingState.KYC)
+	assert.Equal(t, constants.BlockingStepVKYCRetry, journey.BlockingStep)
+	assert.Equal(t, "face not visible", journey.FailureReason)
+	assert.Equal(t, "SMCBIN", journey.Fsi)
+	assert.Equal(t, 25000.0, journey.Amount)
+	assert.Equal(t, "angelone://fd/journey/resume?journeyId=TD+1&step=vkyc_retry&fsi=SMCBIN", journey.ResumeLink)
+
+	journey = buildJourney(pendingJourney, "")
+	assert.Empty(t, journey.ResumeLink, "no link without a configured template")
+
+	pendingJourney.Pending = false
+	journey = buildJourney(pendingJourney, resumeLink)
+	assert.Empty(t, journey.BlockingStep, "completed journeys carry no blocking details")
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/angel-one/fd-core/business/jobs"
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/goerr"
+)
+
+type InvalidClientService interface {
+	SetInvalid(ctx context.Context, clientCode string, request model.InvalidClientRequest, changedBy string) (model.InvalidClientUpdate, error)
+	GetAudit(ctx context.Context, provider string, clientCode string, limit int) (model.InvalidClientAuditTrail, error)
+}
+
+type invalidClientServiceImpl struct {
+	invalidClientDAO dao.InvalidClientDAO
+}
+
+func NewInvalidClientService(invalidClientDAO dao.InvalidClientDAO) InvalidClientService {
+	return &invalidClientServiceImpl{invalidClientDAO: invalidClientDAO}
+}
+
+// SetInvalid sets or clears the invalid flag of the client and audits the change. A client set invalid is still
+// revalidated, after the first backoff, and a cleared client is refreshed by the next instant refresh.
+func (s *invalidClientServiceImpl) SetInvalid(ctx context.Context, clientCode string, request model.InvalidClientRequest, changedBy string) (model.InvalidClientUpdate, error) {
+	scopes, err := invalidClientScopes(request)
+	if err != nil {
+		return model.InvalidClientUpdate{}, err
+	}
+	response := model.InvalidClientUpdate{ClientCode: clientCode, Provider: request.Provider, Invalid: *request.Invalid}
+	if *request.Invalid {
+		backoff, _ := jobs.GetRevalidationBackoff()
+		nextRevalidation := time.Now().Add(backoff)
+		response.NextRevalidationAt = &nextRevalidation
+	}
+
+	change := entity.InvalidClientAuditEntity{ClientCode: clientCode, Provider: request.Provider, Invalid: *request.Invalid, Reason: request.Reason, ChangedBy: changedBy}
+	response.ChangedScopes, err = s.invalidClientDAO.SetInvalid(ctx, change, scopes, response.NextRevalidationAt)
+	if err != nil {
+		return model.InvalidClientUpdate{}, goerr.New(err, "service: setting invalid flag failed")
+	}
+	return response, nil
+}
+
+// invalidClientScopes returns the scopes the request changes, every scope when it names none
+func invalidClientScopes(request model.InvalidClientRequest) ([]string, error) {
+	if request.Scope == "" {
+		return constants.InvalidClientScopes, nil
+	}
+	if !slices.Contains(constants.InvalidClientScopes, request.Scope) {
+		return nil, goerr.New(nil, http.StatusBadRequest, fmt.Sprintf("unknown scope: %s, expected one of %v", request.Scope, constants.InvalidClientScopes))
+	}
+	return []string{request.Scope}, nil
+}
+
+func (s *invalidClientServiceImpl) GetAudit(ctx context.Context, provider string, clientCode string, limit int) (model.InvalidClientAuditTrail, error) {
+	response := model.InvalidClientAuditTrail{ClientCode: clientCode, Provider: provider, Changes: []model.InvalidClientAudit{}}
+	audit, err := s.invalidClientDAO.FetchAudit(ctx, provider, clientCode, limit)
+	if err != nil {
+		return response, goerr.New(err, "service: fetching invalid client audit failed")
+	}
+	for _, change := range audit {
+		response.Changes = append(response.Changes, model.InvalidClientAudit{ID: change.ID, Scope: change.Scope, Invalid: change.Invalid, Reason: change.Reason, ChangedBy: change.ChangedBy, ChangedAt: change.CreatedAt})
+	}
+	return response, nil
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/goerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvalidClientScopes(t *testing.T) {
+	scopes, err := invalidClientScopes(model.InvalidClientRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, constants.InvalidClientScopes, scopes)
+
+	scopes, err = invalidClientScopes(model.InvalidClientRequest{Scope: constants.InvalidClientScopePendingJourney})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{constants.InvalidClientScopePendingJourney}, scopes)
+
+	_, err = invalidClientScopes(model.InvalidClientRequest{Scope: "journeys"})
+	assert.Equal(t, http.StatusBadRequest, goerr.Code(err))
+}
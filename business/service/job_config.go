@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/angel-one/fd-core/business/jobs"
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/goerr"
+	"github.com/robfig/cron/v3"
+)
+
+type JobConfigService interface {
+	GetConfigs(ctx context.Context) ([]model.JobConfig, error)
+	SaveConfig(ctx context.Context, name string, request model.JobConfigRequest, updatedBy string) (model.JobConfig, error)
+}
+
+type jobConfigServiceImpl struct {
+	jobConfigDAO dao.JobConfigDAO
+}
+
+func NewJobConfigService(jobConfigDAO dao.JobConfigDAO) JobConfigService {
+	return &jobConfigServiceImpl{jobConfigDAO: jobConfigDAO}
+}
+
+func (s *jobConfigServiceImpl) GetConfigs(ctx context.Context) ([]model.JobConfig, error) {
+	configs, err := s.jobConfigDAO.FetchConfigs(ctx)
+	if err != nil {
+		return nil, goerr.New(err, "service: fetching job configs failed")
+	}
+	response := []model.JobConfig{}
+	for _, jobConfig := range configs {
+		response = append(response, toJobConfig(jobConfig))
+	}
+	return response, nil
+}
+
+// SaveConfig validates and stores the config of the job, this instance applies it right away and the others on their next reload
+func (s *jobConfigServiceImpl) SaveConfig(ctx context.Context, name string, request model.JobConfigRequest, updatedBy string) (model.JobConfig, error) {
+	if err := validateJobConfig(name, request); err != nil {
+		return model.JobConfig{}, err
+	}
+	if request.Concurrency == 0 {
+		request.Concurrency = 1
+	}
+
+	saved, err := s.jobConfigDAO.SaveConfig(ctx, entity.JobConfigEntity{JobName: name, Enabled: *request.Enabled, Schedule: request.Schedule, BatchSize: request.BatchSize,
+		Provider: request.Provider, Concurrency: request.Concurrency, UpdatedBy: updatedBy})
+	if err != nil {
+		return model.JobConfig{}, goerr.New(err, "service: saving job config failed")
+	}
+	if err := jobs.ReloadJobConfig(ctx); err != nil {
+		log.Error(ctx).Err(err).Msgf("applying config of job %s failed, it applies on the next reload", name)
+	}
+	return toJobConfig(saved), nil
+}
+
+func validateJobConfig(name string, request model.JobConfigRequest) error {
+	if !slices.Contains(jobs.JobNames, name) {
+		return goerr.New(nil, http.StatusNotFound, fmt.Sprintf("unknown job: %s", name))
+	}
+	if request.Schedule != "" {
+		if _, err := cron.ParseStandard(request.Schedule); err != nil {
+			return goerr.New(err, http.StatusBadRequest, fmt.Sprintf("invalid schedule: %s", request.Schedule))
+		}
+	}
+	if request.BatchSize < 0 || request.Concurrency < 0 {
+		return goerr.New(nil, http.StatusBadRequest, "batch size and concurrency can not be negative")
+	}
+	return nil
+}
+
+func toJobConfig(jobConfig entity.JobConfigEntity) model.JobConfig {
+	schedule := jobConfig.Schedule
+	if schedule == "" {
+		schedule = jobs.GetSchedule(jobConfig.JobName)
+	}
+	return model.JobConfig{Job: jobConfig.JobName, Enabled: jobConfig.Enabled, Schedule: schedule, BatchSize: jobConfig.BatchSize, Provider: jobConfig.Provider,
+		Concurrency: jobConfig.Concurrency, UpdatedBy: jobConfig.UpdatedBy, UpdatedAt: jobConfig.UpdatedAt}
+}
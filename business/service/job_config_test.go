@@ -0,0 +1,26 @@
+package service
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/angel-one/fd-core/business/jobs"
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/goerr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateJobConfig(t *testing.T) {
+	enabled := true
+	assert.NoError(t, validateJobConfig(jobs.PortfolioUpdateCron, model.JobConfigRequest{Enabled: &enabled, Schedule: "0 6 * * *", BatchSize: 100}))
+	assert.NoError(t, validateJobConfig(jobs.TokenRenewalCron, model.JobConfigRequest{Enabled: &enabled, Schedule: "@every 10s"}))
+
+	err := validateJobConfig("unknownCron", model.JobConfigRequest{Enabled: &enabled})
+	assert.Equal(t, http.StatusNotFound, goerr.Code(err))
+
+	err = validateJobConfig(jobs.PortfolioUpdateCron, model.JobConfigRequest{Enabled: &enabled, Schedule: "every morning"})
+	assert.Equal(t, http.StatusBadRequest, goerr.Code(err))
+
+	err = validateJobConfig(jobs.PortfolioUpdateCron, model.JobConfigRequest{Enabled: &enabled, BatchSize: -1})
+	assert.Equal(t, http.StatusBadRequest, goerr.Code(err))
+}
@@ -4,26 +4,94 @@ import (
 	"context"
 
 	"github.com/angel-one/fd-core/business/jobs"
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/goerr"
 )
 
 type JobsService interface {
-	InvokePortfolioJob(ctx context.Context, refresher string)
-	InvokePendingJourneyJob(ctx context.Context, refresher string)
+	TriggerJob(ctx context.Context, name string, request model.JobTriggerRequest, requestedBy string) (model.JobRun, error)
+	GetRun(ctx context.Context, id int64) (*model.JobRun, error)
+	CancelRun(ctx context.Context, id int64) (bool, error)
+	GetLeases(ctx context.Context) (model.JobLeases, error)
+	GetRuns(ctx context.Context, filter model.JobRunFilter) (model.JobRuns, error)
+}
+
+// JobRunner starts and cancels the runs of the jobs
+type JobRunner interface {
+	Trigger(ctx context.Context, name string, request model.JobTriggerRequest, requestedBy string) (entity.JobRunEntity, error)
+	CancelRun(ctx context.Context, id int64) (bool, error)
 }
 
 type jobsServiceImpl struct {
-	portfolioJob      jobs.PortfolioUpdater
-	pendingJourneyJob jobs.PendingJourneyUpdater
+	jobLeaseDAO dao.JobLeaseDAO
+	jobRunDAO   dao.JobRunDAO
+	runner      JobRunner
+}
+
+func NewJobsService(jobLeaseDAO dao.JobLeaseDAO, jobRunDAO dao.JobRunDAO, runner JobRunner) JobsService {
+	return &jobsServiceImpl{jobLeaseDAO: jobLeaseDAO, jobRunDAO: jobRunDAO, runner: runner}
+}
+
+// TriggerJob starts a run of the job in the background and returns it as running, fails with a conflict while the job is running
+func (service *jobsServiceImpl) TriggerJob(ctx context.Context, name string, request model.JobTriggerRequest, requestedBy string) (model.JobRun, error) {
+	run, err := service.runner.Trigger(ctx, name, request, requestedBy)
+	if err != nil {
+		return model.JobRun{}, err
+	}
+	return toJobRun(run), nil
+}
+
+// GetRun returns nil when the run does not exist
+func (service *jobsServiceImpl) GetRun(ctx context.Context, id int64) (*model.JobRun, error) {
+	run, err := service.jobRunDAO.FetchRun(ctx, id)
+	if err != nil {
+		return nil, goerr.New(err, "service: fetching job run failed")
+	}
+	if run == nil {
+		return nil, nil
+	}
+	response := toJobRun(*run)
+	return &response, nil
+}
+
+// CancelRun returns false when the run is not running
+func (service *jobsServiceImpl) CancelRun(ctx context.Context, id int64) (bool, error) {
+	cancelled, err := service.runner.CancelRun(ctx, id)
+	if err != nil {
+		return false, goerr.New(err, "service: cancelling job run failed")
+	}
+	return cancelled, nil
 }
 
-func DefaultJobsService() JobsService {
-	return &jobsServiceImpl{portfolioJob: jobs.DefaultPortfolioUpdater(), pendingJourneyJob: jobs.DefaultPendingJourneyUpdater()}
+// GetLeases returns the lease holder of every leased cron job along with the id of this instance
+func (service *jobsServiceImpl) GetLeases(ctx context.Context) (model.JobLeases, error) {
+	response := model.JobLeases{Instance: jobs.InstanceID(), Leases: []model.JobLease{}}
+	leases, err := service.jobLeaseDAO.FetchLeases(ctx)
+	if err != nil {
+		return response, goerr.New(err, "service: fetching job leases failed")
+	}
+	for _, lease := range leases {
+		response.Leases = append(response.Leases, model.JobLease{Job: lease.JobName, Holder: lease.Holder, AcquiredAt: lease.AcquiredAt, RenewedAt: lease.RenewedAt, ExpiresAt: lease.ExpiresAt, Expired: lease.Expired})
+	}
+	return response, nil
 }
 
-func (service *jobsServiceImpl) InvokePortfolioJob(ctx context.Context, refresher string) {
-	service.portfolioJob.DoJob(ctx, refresher)
+func (service *jobsServiceImpl) GetRuns(ctx context.Context, filter model.JobRunFilter) (model.JobRuns, error) {
+	response := model.JobRuns{Runs: []model.JobRun{}}
+	runs, err := service.jobRunDAO.FetchRuns(ctx, filter)
+	if err != nil {
+		return response, goerr.New(err, "service: fetching job runs failed")
+	}
+	for _, run := range runs {
+		response.Runs = append(response.Runs, toJobRun(run))
+	}
+	return response, nil
 }
 
-func (service *jobsServiceImpl) InvokePendingJourneyJob(ctx context.Context, refresher string) {
-	service.pendingJourneyJob.DoJob(ctx, refresher)
+func toJobRun(run entity.JobRunEntity) model.JobRun {
+	return model.JobRun{ID: run.ID, Job: run.JobName, Trigger: run.Trigger, Refresher: run.Refresher, Instance: run.Instance, Status: run.Status, StartedAt: run.StartedAt, FinishedAt: run.FinishedAt,
+		ClientsTotal: run.ClientsTotal, ClientsProcessed: run.ClientsProcessed, Successes: run.Successes, UpstreamErrors: run.UpstreamErrors, InvalidClients: run.InvalidClients, Error: run.Error,
+		CancelRequested: run.CancelRequested, RequestedBy: run.RequestedBy, Cursor: run.Cursor, Mode: run.Mode, SourceRunID: run.SourceRunID, ThroughputPerSecond: run.ThroughputPerSecond, LatencyAvgMs: run.LatencyAvgMs, LatencyP95Ms: run.LatencyP95Ms}
 }
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeJobRunDAO struct {
+	dao.JobRunDAO
+	runs   []entity.JobRunEntity
+	err    error
+	filter model.JobRunFilter
+}
+
+func (f *fakeJobRunDAO) FetchRuns(ctx context.Context, filter model.JobRunFilter) ([]entity.JobRunEntity, error) {
+	f.filter = filter
+	return f.runs, f.err
+}
+
+func TestGetRuns(t *testing.T) {
+	startedAt := time.Date(2026, 10, 19, 6, 0, 0, 0, time.UTC)
+	runDAO := &fakeJobRunDAO{runs: []entity.JobRunEntity{
+		{ID: 2, JobName: "portfolioUpdateCron", Trigger: constants.JobTriggerCron, Status: constants.JobRunFailed, StartedAt: startedAt, ClientsTotal: 10, ClientsProcessed: 4,
+			UpstreamErrors: 1, Error: "upswing unavailable", Mode: constants.JobRunModeFull, Cursor: "C004"},
+	}}
+	jobsService := &jobsServiceImpl{jobRunDAO: runDAO}
+	filter := model.JobRunFilter{JobName: "portfolioUpdateCron", Status: constants.JobRunFailed, Limit: 10}
+
+	runs, err := jobsService.GetRuns(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.Equal(t, filter, runDAO.filter)
+	assert.Equal(t, []model.JobRun{{ID: 2, Job: "portfolioUpdateCron", Trigger: constants.JobTriggerCron, Status: constants.JobRunFailed, StartedAt: startedAt, ClientsTotal: 10,
+		ClientsProcessed: 4, UpstreamErrors: 1, Error: "upswing unavailable", Mode: constants.JobRunModeFull, Cursor: "C004"}}, runs.Runs)
+
+	// no run is an empty list, not null
+	runDAO.runs = nil
+	runs, err = jobsService.GetRuns(context.Background(), filter)
+	assert.NoError(t, err)
+	assert.NotNil(t, runs.Runs)
+	assert.Empty(t, runs.Runs)
+
+	runDAO.err = errors.New("connection refused")
+	_, err = jobsService.GetRuns(context.Background(), filter)
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/goerr"
+)
+
+type JourneyService interface {
+	GetJourneys(ctx context.Context, clientCode string, provider string) (model.BookingJourneys, error)
+	GetJourneyTimeline(ctx context.Context, clientCode string, provider string, journeyID string) (*model.JourneyTimeline, error)
+}
+
+type journeyServiceImpl struct {
+	journeyDAO dao.JourneyDAO
+}
+
+func NewJourneyService(journeyDAO dao.JourneyDAO) JourneyService {
+	return &journeyServiceImpl{journeyDAO: journeyDAO}
+}
+
+func (s *journeyServiceImpl) GetJourneys(ctx context.Context, clientCode string, provider string) (model.BookingJourneys, error) {
+	response := model.BookingJourneys{Journeys: []model.BookingJourney{}}
+	journeys, err := s.journeyDAO.FetchClientJourneys(ctx, provider, clientCode)
+	if err != nil {
+		return response, goerr.New(err, "service: fetching journeys failed")
+	}
+	for _, journey := range journeys {
+		response.Journeys = append(response.Journeys, toBookingJourney(journey))
+	}
+	return response, nil
+}
+
+// GetJourneyTimeline returns nil when the journey is not found for the client
+func (s *journeyServiceImpl) GetJourneyTimeline(ctx context.Context, clientCode string, provider string, journeyID string) (*model.JourneyTimeline, error) {
+	journey, err := s.journeyDAO.FetchClientJourney(ctx, provider, clientCode, journeyID)
+	if err != nil {
+		return nil, goerr.New(err, "service: fetching journey failed")
+	}
+	if journey == nil {
+		return nil, nil
+	}
+	events, err := s.journeyDAO.FetchJourneyEvents(ctx, provider, clientCode, journeyID)
+	if err != nil {
+		return nil, goerr.New(err, "service: fetching journey events failed")
+	}
+
+	timeline := model.JourneyTimeline{Journey: toBookingJourney(*journey), Events: []model.JourneyTimelineEvent{}}
+	var state string
+	for _, event := range events {
+		state, _ = nextJourneyState(state, event.EventType)
+		timeline.Events = append(timeline.Events, model.JourneyTimelineEvent{EventType: event.EventType, State: state, Reason: event.FailureReason, Amount: event.Amount, At: event.CreatedAt})
+	}
+	return &timeline, nil
+}
+
+func toBookingJourney(journey entity.JourneyEntity) model.BookingJourney {
+	return model.BookingJourney{JourneyID: journey.TrackingId, State: journey.State, Fsi: journey.Institution, Amount: journey.Amount, LastEventType: journey.LastEventType, LastEventAt: journey.LastEventAt, StartedAt: journey.CreatedAt}
+}
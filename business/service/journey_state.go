@@ -0,0 +1,88 @@
+package service
+
+import (
+	"slices"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/constants"
+)
+
+// journeyEventStates is the state a journey moves to on an event, events not listed here do not move the journey
+var journeyEventStates = map[string]string{
+	constants.EventAadhaarFailed:              constants.JourneyStateKYC,
+	constants.EventVKYCInitiated:              constants.JourneyStateKYC,
+	constants.EventVKYCFailure:                constants.JourneyStateKYC,
+	constants.EventVKYCRequired:               constants.JourneyStateKYC,
+	constants.EventVKYCRetryRequired:          constants.JourneyStateKYC,
+	constants.EventDigilockerFailed:           constants.JourneyStateKYC,
+	constants.EventPANFailure:                 constants.JourneyStateKYC,
+	constants.EventPaymentFailure:             constants.JourneyStatePayment,
+	constants.EventTDBooked:                   constants.JourneyStateBooked,
+	constants.EventTDFailed:                   constants.JourneyStateFailed,
+	constants.EventPrematureWithdrawalSuccess: constants.JourneyStateWithdrawn,
+	constants.EventWithdrawalBankSuccess:      constants.JourneyStateWithdrawn,
+}
+
+// journeyTransitions lists the allowed next states, kyc and payment can be re-entered as the user retries a step
+var journeyTransitions = map[string][]string{
+	constants.JourneyStateInitiated: {constants.JourneyStateKYC, constants.JourneyStatePayment, constants.JourneyStateBooked, constants.JourneyStateFailed},
+	constants.JourneyStateKYC:       {constants.JourneyStateKYC, constants.JourneyStatePayment, constants.JourneyStateBooked, constants.JourneyStateFailed},
+	constants.JourneyStatePayment:   {constants.JourneyStateKYC, constants.JourneyStatePayment, constants.JourneyStateBooked, constants.JourneyStateFailed},
+	constants.JourneyStateBooked:    {constants.JourneyStateWithdrawn},
+	constants.JourneyStateFailed:    {},
+	constants.JourneyStateWithdrawn: {},
+}
+
+// nextJourneyState returns the state after applying the event to a journey in the current state, an empty current
+// state is a journey seen for the first time. Invalid transitions keep the current state and report false.
+func nextJourneyState(current string, eventType string) (string, bool) {
+	if current == "" {
+		current = constants.JourneyStateInitiated
+	}
+	next, ok := journeyEventStates[eventType]
+	if !ok {
+		return current, true
+	}
+	if !slices.Contains(journeyTransitions[current], next) {
+		return current, false
+	}
+	return next, true
+}
+
+// startsJourney tells whether the event may create its journey, which is whether a journey just initiated accepts it
+func startsJourney(eventType string) bool {
+	_, ok := nextJourneyState("", eventType)
+	return ok
+}
+
+// deriveJourneys folds the event history of a client into one journey per tracking id, in the order first seen. As on
+// the live path, the events of a journey before one that starts it are skipped.
+func deriveJourneys(provider string, clientCode string, events []entity.WebhookEvent) []entity.JourneyEntity {
+	var journeys []entity.JourneyEntity
+	index := make(map[string]int)
+	for _, event := range events {
+		if event.TrackingId == "" {
+			continue
+		}
+		i, ok := index[event.TrackingId]
+		if !ok && !startsJourney(event.EventType) {
+			continue
+		}
+		if !ok {
+			journeys = append(journeys, entity.JourneyEntity{TrackingId: event.TrackingId, ClientCode: clientCode, Provider: provider})
+			i = len(journeys) - 1
+			index[event.TrackingId] = i
+		}
+		journey := &journeys[i]
+		journey.State, _ = nextJourneyState(journey.State, event.EventType)
+		journey.LastEventType = event.EventType
+		journey.LastEventAt = event.CreatedAt
+		if event.Institution != "" {
+			journey.Institution = event.Institution
+		}
+		if event.Amount != 0 {
+			journey.Amount = event.Amount
+		}
+	}
+	return journeys
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextJourneyState(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   string
+		eventType string
+		want      string
+		valid     bool
+	}{
+		{"new journey into kyc", "", constants.EventVKYCInitiated, constants.JourneyStateKYC, true},
+		{"unknown event keeps state", constants.JourneyStateKYC, "SOMETHING_NEW", constants.JourneyStateKYC, true},
+		{"kyc retry", constants.JourneyStateKYC, constants.EventVKYCRetryRequired, constants.JourneyStateKYC, true},
+		{"kyc to payment", constants.JourneyStateKYC, constants.EventPaymentFailure, constants.JourneyStatePayment, true},
+		{"payment to booked", constants.JourneyStatePayment, constants.EventTDBooked, constants.JourneyStateBooked, true},
+		{"booked to withdrawn", constants.JourneyStateBooked, constants.EventWithdrawalBankSuccess, constants.JourneyStateWithdrawn, true},
+		{"initiated cannot be withdrawn", constants.JourneyStateInitiated, constants.EventPrematureWithdrawalSuccess, constants.JourneyStateInitiated, false},
+		{"booked cannot go back to kyc", constants.JourneyStateBooked, constants.EventPANFailure, constants.JourneyStateBooked, false},
+		{"failed is terminal", constants.JourneyStateFailed, constants.EventTDBooked, constants.JourneyStateFailed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, valid := nextJourneyState(tt.current, tt.eventType)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.valid, valid)
+		})
+	}
+}
+
+func TestDeriveJourneys(t *testing.T) {
+	now := time.Now()
+	events := []entity.WebhookEvent{
+		{TrackingId: "j1", EventType: constants.EventVKYCRequired, Institution: "bank", CreatedAt: now},
+		{TrackingId: "j2", EventType: constants.EventPaymentFailure, Amount: 5000, CreatedAt: now},
+		// a withdrawal does not start a journey
+		{TrackingId: "j3", EventType: constants.EventWithdrawalBankSuccess, CreatedAt: now},
+		{EventType: constants.EventTDBooked, Amount: 100},
+		{TrackingId: "j1", EventType: constants.EventTDBooked, Amount: 10000, CreatedAt: now.Add(time.Minute)},
+	}
+
+	journeys := deriveJourneys(constants.UpSwingProvider, "C1", events)
+	assert.Len(t, journeys, 2)
+	assert.Equal(t, "j1", journeys[0].TrackingId)
+	assert.Equal(t, constants.JourneyStateBooked, journeys[0].State)
+	assert.Equal(t, "bank", journeys[0].Institution)
+	assert.Equal(t, 10000.0, journeys[0].Amount)
+	assert.Equal(t, now.Add(time.Minute), journeys[0].LastEventAt)
+	assert.Equal(t, constants.JourneyStatePayment, journeys[1].State)
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/goerr"
+)
+
+var avgDays float64 = 30.417
+
+// WebhookNormalizer maps the payloads of one provider, in any of its schema versions, to the canonical DepositEvent
+type WebhookNormalizer interface {
+	Normalize(payload json.RawMessage) (model.DepositEvent, error)
+}
+
+// normalizers per provider, a new provider is onboarded by adding its normalizer here
+var webhookNormalizers = map[string]WebhookNormalizer{
+	constants.UpSwingProvider: upSwingNormalizer{},
+}
+
+func IsWebhookProviderSupported(provider string) bool {
+	_, ok := webhookNormalizers[provider]
+	return ok
+}
+
+// payloadVersion reads the optional version of a payload, payloads without a version are treated as v1
+func payloadVersion(payload json.RawMessage) (string, error) {
+	var envelope map[string]interface{}
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return "", goerr.New(err, "webhook payload is not a valid json object")
+	}
+	if v, ok := envelope[constants.WebhookPayloadVersionKey].(string); ok && v != "" {
+		return v, nil
+	}
+	return constants.WebhookPayloadV1, nil
+}
+
+type upSwingNormalizer struct{}
+
+// Normalize supports the upswing schema versions below; add a new version here when upswing changes its schema
+func (n upSwingNormalizer) Normalize(payload json.RawMessage) (model.DepositEvent, error) {
+	version, err := payloadVersion(payload)
+	if err != nil {
+		return model.DepositEvent{}, err
+	}
+	switch version {
+	case constants.WebhookPayloadV1:
+		return n.normalizeV1(payload)
+	default:
+		return model.DepositEvent{}, goerr.New(nil, fmt.Sprintf("no webhook normalizer for vendor: %s; version: %s", constants.UpSwingProvider, version))
+	}
+}
+
+func (n upSwingNormalizer) normalizeV1(payload json.RawMessage) (model.DepositEvent, error) {
+	var event model.UpSwingWebhookEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return model.DepositEvent{}, goerr.New(err, "upswing v1 webhook payload decoding failed")
+	}
+	depositEvent := model.DepositEvent{EventType: event.EventType, ClientCode: event.Pci, Fsi: event.Fsi, Amount: event.Amount, JourneyID: event.JourneyID, DepositType: event.TermDepositType, FailureReason: event.Reason, PayloadVersion: constants.WebhookPayloadV1}
+	months, days, err := parseTenure(event.Tenure)
+	if err != nil {
+		return model.DepositEvent{}, goerr.New(err, "upswing v1 webhook payload decoding failed")
+	}
+	depositEvent.TenureMonths, depositEvent.TenureDays = months, days
+	return depositEvent, nil
+}
+
+// sample formats: 22m0d 0m1826d 0m582d 11m11d
+func parseTenure(tenure string) (int, int, error) {
+	if tenure == "" {
+		return 0, 0, nil
+	}
+	monthsIndex := strings.Index(tenure, "m")
+	daysIndex := strings.Index(tenure, "d")
+	if monthsIndex < 0 || daysIndex != len(tenure)-1 || daysIndex < monthsIndex {
+		return 0, 0, fmt.Errorf("invalid tenure: %s", tenure)
+	}
+	months, err := strconv.Atoi(tenure[0:monthsIndex])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid tenure months: %s", tenure)
+	}
+	days, err := strconv.Atoi(tenure[monthsIndex+1 : daysIndex])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid tenure days: %s", tenure)
+	}
+	if months == 0 && days > 31 {
+		months = int(math.Round(float64(days) / avgDays))
+		days = 0
+	}
+	return months, days, nil
+}
@@ -16,8 +16,8 @@ type PlansServiceImpl struct {
 	plansDAO dao.PlansDAO
 }
 
-func DefaultPlansService() PlansService {
-	return &PlansServiceImpl{plansDAO: dao.DefaultPlansDAO()}
+func NewPlansService(plansDAO dao.PlansDAO) PlansService {
+	return &PlansServiceImpl{plansDAO: plansDAO}
 }
 
 func (service *PlansServiceImpl) GetAllPlans(ctx context.Context) (model.Plans, error) {
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"context"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/goerr"
+)
+
+type ReconciliationService interface {
+	GetSummary(ctx context.Context, provider string, limit int) (*model.ReconciliationSummary, error)
+}
+
+type reconciliationServiceImpl struct {
+	reconciliationDAO dao.ReconciliationDAO
+}
+
+func NewReconciliationService(reconciliationDAO dao.ReconciliationDAO) ReconciliationService {
+	return &reconciliationServiceImpl{reconciliationDAO: reconciliationDAO}
+}
+
+// GetSummary returns the counters and the largest discrepancies of the latest finished run, nil when the provider was never reconciled
+func (s *reconciliationServiceImpl) GetSummary(ctx context.Context, provider string, limit int) (*model.ReconciliationSummary, error) {
+	run, err := s.reconciliationDAO.FetchLatestRun(ctx, provider)
+	if err != nil {
+		return nil, goerr.New(err, "service: fetching latest reconciliation run failed")
+	}
+	if run == nil {
+		return nil, nil
+	}
+
+	counts, err := s.reconciliationDAO.FetchSeverityCounts(ctx, run.ID)
+	if err != nil {
+		return nil, goerr.New(err, "service: fetching discrepancy counts failed")
+	}
+	discrepancies, err := s.reconciliationDAO.FetchRunDiscrepancies(ctx, run.ID, limit)
+	if err != nil {
+		return nil, goerr.New(err, "service: fetching discrepancies failed")
+	}
+
+	summary := model.ReconciliationSummary{Provider: provider, RunID: run.ID, StartedAt: &run.StartedAt, FinishedAt: run.FinishedAt, ClientsCompared: run.ClientsCompared,
+		ClientsFailed: run.ClientsFailed, Discrepancies: run.Discrepancies, BySeverity: counts, Top: []model.PortfolioDiscrepancy{}}
+	for _, discrepancy := range discrepancies {
+		summary.Top = append(summary.Top, model.PortfolioDiscrepancy{ClientCode: discrepancy.ClientCode, Severity: discrepancy.Severity,
+			DB:                   model.PortfolioValue{ActiveDeposits: discrepancy.DBActiveDeposits, InvestedValue: discrepancy.DBInvestedValue, CurrentValue: discrepancy.DBCurrentValue},
+			Provider:             model.PortfolioValue{ActiveDeposits: discrepancy.ProviderActiveDeposits, InvestedValue: discrepancy.ProviderInvestedValue, CurrentValue: discrepancy.ProviderCurrentValue},
+			DifferencePercentage: discrepancy.DifferencePercentage, FoundAt: discrepancy.CreatedAt})
+	}
+	return &summary, nil
+}
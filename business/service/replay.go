@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/goerr"
+)
+
+const replayUpdatedBy = "webhook_replay"
+
+type ReplayService interface {
+	Replay(ctx context.Context, request model.ReplayRequest) (*model.ReplayResult, error)
+}
+
+type replayServiceImpl struct {
+	replayDAO dao.ReplayDAO
+}
+
+func NewReplayService(replayDAO dao.ReplayDAO) ReplayService {
+	return &replayServiceImpl{replayDAO: replayDAO}
+}
+
+// derivedClientState is what the webhook_events triggers would have built for a client, nil when no event applies
+type derivedClientState struct {
+	portfolio      *entity.PortfolioEntity
+	pendingJourney *entity.PendingJourneyEntity
+	journeys       []entity.JourneyEntity
+}
+
+// Replay rebuilds pending_journey and journeys rows from webhook_events, marks the open portfolios differing from them to
+// be refreshed from the provider and reopens the closed ones. With DryRun only the diff is computed.
+func (r *replayServiceImpl) Replay(ctx context.Context, request model.ReplayRequest) (*model.ReplayResult, error) {
+	clientList, err := r.replayDAO.FetchClientList(ctx, request.Provider, request.ClientCode, request.From, request.To)
+	if err != nil {
+		return nil, goerr.New(err, "service: fetching replay client list failed")
+	}
+	log.Info(ctx).Msgf("replaying webhook events of %d clients; dryRun: %t", len(clientList), request.DryRun)
+
+	result := model.ReplayResult{DryRun: request.DryRun, Diffs: []model.ClientReplayDiff{}}
+	for _, clientCode := range clientList {
+		if ctx.Err() != nil {
+			return &result, goerr.New(ctx.Err(), "service: replay interrupted")
+		}
+		events, err := r.replayDAO.FetchClientEvents(ctx, request.Provider, clientCode)
+		if err != nil {
+			return &result, goerr.New(err, "service: fetching client webhook events failed")
+		}
+		currentPortfolio, err := r.replayDAO.FetchPortfolioState(ctx, request.Provider, clientCode)
+		if err != nil {
+			return &result, goerr.New(err, "service: fetching current portfolio failed")
+		}
+		currentPendingJourney, err := r.replayDAO.FetchPendingJourneyState(ctx, request.Provider, clientCode)
+		if err != nil {
+			return &result, goerr.New(err, "service: fetching current pending journey failed")
+		}
+		currentJourneyStates, err := r.replayDAO.FetchJourneyStates(ctx, request.Provider, clientCode)
+		if err != nil {
+			return &result, goerr.New(err, "service: fetching current journey states failed")
+		}
+
+		derived := deriveClientState(request.Provider, clientCode, events)
+		portfolioChanges := diffPortfolio(currentPortfolio, derived.portfolio)
+		pendingJourneyChanges := diffPendingJourney(currentPendingJourney, derived.pendingJourney)
+		journeyChanges, changedJourneys := diffJourneys(currentJourneyStates, derived.journeys)
+
+		result.ClientsScanned++
+		result.EventsReplayed += len(events)
+		changes := append(append(portfolioChanges, pendingJourneyChanges...), journeyChanges...)
+		if len(changes) == 0 {
+			continue
+		}
+		result.ClientsChanged++
+		result.Diffs = append(result.Diffs, model.ClientReplayDiff{ClientCode: clientCode, Changes: changes})
+		if request.DryRun {
+			continue
+		}
+
+		// only write what actually differs
+		if len(portfolioChanges) == 0 {
+			derived.portfolio = nil
+		}
+		if len(pendingJourneyChanges) == 0 {
+			derived.pendingJourney = nil
+		}
+		err = r.replayDAO.ApplyClientState(ctx, derived.portfolio, derived.pendingJourney, changedJourneys)
+		if err != nil {
+			return &result, goerr.New(err, "service: applying replayed client state failed")
+		}
+	}
+	log.Info(ctx).Msgf("webhook replay complete; scanned: %d; changed: %d; events: %d", result.ClientsScanned, result.ClientsChanged, result.EventsReplayed)
+	return &result, nil
+}
+
+// deriveClientState folds the event history the same way the webhook_events insert triggers do
+func deriveClientState(provider string, clientCode string, events []entity.WebhookEvent) derivedClientState {
+	var state derivedClientState
+	for _, event := range events {
+		if slices.Contains(constants.PortfolioEventTypes, event.EventType) {
+			if state.portfolio == nil {
+				state.portfolio = &entity.PortfolioEntity{ClientCode: clientCode, Provider: provider, CreatedBy: replayUpdatedBy, UpdatedBy: replayUpdatedBy}
+			}
+			state.portfolio.TotalActiveDeposits++
+			state.portfolio.InvestedValue += event.Amount
+			state.portfolio.CurrentValue += event.Amount
+			state.portfolio.ToBeRefreshed = true
+		}
+		if slices.Contains(constants.PendingJourneyEventTypes, event.EventType) {
+			if state.pendingJourney == nil {
+				state.pendingJourney = &entity.PendingJourneyEntity{ClientCode: clientCode, Provider: provider, CreatedBy: replayUpdatedBy, UpdatedBy: replayUpdatedBy}
+			}
+			if state.pendingJourney.TrackingId != event.TrackingId {
+				state.pendingJourney.Institution, state.pendingJourney.Amount = "", 0
+			}
+			if event.Institution != "" {
+				state.pendingJourney.Institution = event.Institution
+			}
+			if event.Amount != 0 {
+				state.pendingJourney.Amount = event.Amount
+			}
+			state.pendingJourney.TrackingId = event.TrackingId
+			state.pendingJourney.BlockingEvent = event.EventType
+			state.pendingJourney.FailureReason = event.FailureReason
+			state.pendingJourney.ToBeRefreshed = true
+		}
+	}
+	state.journeys = deriveJourneys(provider, clientCode, events)
+	for i := range state.journeys {
+		state.journeys[i].UpdatedBy = replayUpdatedBy
+	}
+	return state
+}
+
+// diffPortfolio returns the changes written for the derived portfolio. The values of an open portfolio come from the
+// provider, the events it adds up to are only counted on top of them by the triggers, so a portfolio differing from its
+// events is marked to be refreshed from the provider instead of being overwritten. A closed portfolio is reopened with
+// the derived values like a missing one is inserted.
+func diffPortfolio(current *entity.PortfolioEntity, derived *entity.PortfolioEntity) []model.FieldDiff {
+	var changes []model.FieldDiff
+	if derived == nil {
+		// rows without any portfolio event are not derivable from webhooks, they are left as they are
+		return changes
+	}
+	if current == nil {
+		return []model.FieldDiff{
+			{Field: "portfolio.totalActiveDeposits", Derived: derived.TotalActiveDeposits},
+			{Field: "portfolio.investedValue", Derived: derived.InvestedValue},
+			{Field: "portfolio.currentValue", Derived: derived.CurrentValue},
+			{Field: "portfolio.toBeRefreshed", Derived: derived.ToBeRefreshed},
+		}
+	}
+	if current.ClosedAt != nil {
+		changes = append(changes, model.FieldDiff{Field: "portfolio.closed", Current: true, Derived: false})
+		if current.TotalActiveDeposits != derived.TotalActiveDeposits {
+			changes = append(changes, model.FieldDiff{Field: "portfolio.totalActiveDeposits", Current: current.TotalActiveDeposits, Derived: derived.TotalActiveDeposits})
+		}
+		if !amountEquals(current.InvestedValue, derived.InvestedValue) {
+			changes = append(changes, model.FieldDiff{Field: "portfolio.investedValue", Current: current.InvestedValue, Derived: derived.InvestedValue})
+		}
+		if !amountEquals(current.CurrentValue, derived.CurrentValue) {
+			changes = append(changes, model.FieldDiff{Field: "portfolio.currentValue", Current: current.CurrentValue, Derived: derived.CurrentValue})
+		}
+		if current.ToBeRefreshed != derived.ToBeRefreshed {
+			changes = append(changes, model.FieldDiff{Field: "portfolio.toBeRefreshed", Current: current.ToBeRefreshed, Derived: derived.ToBeRefreshed})
+		}
+		return changes
+	}
+	differs := current.TotalActiveDeposits != derived.TotalActiveDeposits || !amountEquals(current.InvestedValue, derived.InvestedValue) ||
+		!amountEquals(current.CurrentValue, derived.CurrentValue)
+	if differs && !current.ToBeRefreshed {
+		changes = append(changes, model.FieldDiff{Field: "portfolio.toBeRefreshed", Current: current.ToBeRefreshed, Derived: true})
+	}
+	return changes
+}
+
+func diffPendingJourney(current *entity.PendingJourneyEntity, derived *entity.PendingJourneyEntity) []model.FieldDiff {
+	var changes []model.FieldDiff
+	if derived == nil {
+		return changes
+	}
+	if current == nil {
+		return []model.FieldDiff{
+			{Field: "pendingJourney.toBeRefreshed", Derived: derived.ToBeRefreshed},
+			{Field: "pendingJourney.blockingEvent", Derived: derived.BlockingEvent},
+		}
+	}
+	if current.TrackingId != derived.TrackingId {
+		changes = append(changes, model.FieldDiff{Field: "pendingJourney.journeyId", Current: current.TrackingId, Derived: derived.TrackingId})
+	}
+	if current.BlockingEvent != derived.BlockingEvent {
+		changes = append(changes, model.FieldDiff{Field: "pendingJourney.blockingEvent", Current: current.BlockingEvent, Derived: derived.BlockingEvent})
+	}
+	if current.FailureReason != derived.FailureReason {
+		changes = append(changes, model.FieldDiff{Field: "pendingJourney.failureReason", Current: current.FailureReason, Derived: derived.FailureReason})
+	}
+	return changes
+}
+
+// diffJourneys compares journey states only, also returns the derived journeys that need to be written
+func diffJourneys(current map[string]string, derived []entity.JourneyEntity) ([]model.FieldDiff, []entity.JourneyEntity) {
+	var changes []model.FieldDiff
+	var changed []entity.JourneyEntity
+	for _, journey := range derived {
+		state, ok := current[journey.TrackingId]
+		if ok && state == journey.State {
+			continue
+		}
+		diff := model.FieldDiff{Field: fmt.Sprintf("journey.%s.state", journey.TrackingId), Derived: journey.State}
+		if ok {
+			diff.Current = state
+		}
+		changes = append(changes, diff)
+		changed = append(changed, journey)
+	}
+	return changes, changed
+}
+
+// amounts are stored as numeric(19, 4)
+func amountEquals(a float64, b float64) bool {
+	return math.Abs(a-b) < 0.0001
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeriveClientState(t *testing.T) {
+	events := []entity.WebhookEvent{
+		{EventType: constants.EventVKYCRequired},
+		{EventType: constants.EventTDBooked, Amount: 10000},
+		{EventType: "UNKNOWN_EVENT", Amount: 500},
+		{EventType: constants.EventTDBooked, Amount: 5000},
+	}
+	derived := deriveClientState(constants.UpSwingProvider, "S1614297", events)
+	if assert.NotNil(t, derived.portfolio, "portfolio must be derived from booked events") {
+		assert.Equal(t, 2, derived.portfolio.TotalActiveDeposits)
+		assert.Equal(t, 15000.0, derived.portfolio.InvestedValue)
+		assert.Equal(t, 15000.0, derived.portfolio.CurrentValue)
+		assert.True(t, derived.portfolio.ToBeRefreshed)
+	}
+	if assert.NotNil(t, derived.pendingJourney, "pending journey must be derived from failure events") {
+		assert.Equal(t, constants.EventVKYCRequired, derived.pendingJourney.BlockingEvent)
+	}
+
+	derived = deriveClientState(constants.UpSwingProvider, "S1614297", []entity.WebhookEvent{{EventType: "UNKNOWN_EVENT"}})
+	assert.Nil(t, derived.portfolio)
+	assert.Nil(t, derived.pendingJourney)
+}
+
+func TestDiffPortfolio(t *testing.T) {
+	derived := &entity.PortfolioEntity{TotalActiveDeposits: 2, InvestedValue: 15000, CurrentValue: 15000, ToBeRefreshed: true}
+
+	assert.Len(t, diffPortfolio(nil, derived), 4, "missing row must report every derived field")
+	assert.Empty(t, diffPortfolio(&entity.PortfolioEntity{TotalActiveDeposits: 2, InvestedValue: 15000.00001, CurrentValue: 15000}, derived))
+	assert.Empty(t, diffPortfolio(&entity.PortfolioEntity{TotalActiveDeposits: 7}, nil), "rows without portfolio events are never touched")
+
+	// the values of an existing portfolio are left to the provider, the portfolio is marked to be refreshed
+	changes := diffPortfolio(&entity.PortfolioEntity{TotalActiveDeposits: 1, InvestedValue: 10000, CurrentValue: 15000}, derived)
+	assert.Equal(t, []model.FieldDiff{{Field: "portfolio.toBeRefreshed", Current: false, Derived: true}}, changes)
+	assert.Empty(t, diffPortfolio(&entity.PortfolioEntity{TotalActiveDeposits: 1, InvestedValue: 10000, CurrentValue: 15000, ToBeRefreshed: true}, derived),
+		"a portfolio already marked is not written")
+
+	// a closed portfolio is reopened with the derived values, the way the replay writes it
+	closedAt := time.Now()
+	changes = diffPortfolio(&entity.PortfolioEntity{TotalActiveDeposits: 0, InvestedValue: 0, CurrentValue: 0, ClosedAt: &closedAt}, derived)
+	assert.Equal(t, []model.FieldDiff{
+		{Field: "portfolio.closed", Current: true, Derived: false},
+		{Field: "portfolio.totalActiveDeposits", Current: 0, Derived: 2},
+		{Field: "portfolio.investedValue", Current: 0.0, Derived: 15000.0},
+		{Field: "portfolio.currentValue", Current: 0.0, Derived: 15000.0},
+		{Field: "portfolio.toBeRefreshed", Current: false, Derived: true},
+	}, changes)
+	changes = diffPortfolio(&entity.PortfolioEntity{TotalActiveDeposits: 2, InvestedValue: 15000, CurrentValue: 15000, ToBeRefreshed: true, ClosedAt: &closedAt}, derived)
+	assert.Equal(t, []model.FieldDiff{{Field: "portfolio.closed", Current: true, Derived: false}}, changes,
+		"a closed portfolio matching its events is still reopened")
+}
@@ -17,8 +17,8 @@ type portfolioServiceImpl struct {
 	portfolioDAO dao.PortfolioDAO
 }
 
-func DefaultPortfolioService() PortfolioService {
-	return &portfolioServiceImpl{portfolioDAO: dao.DefaultPortfolioDAO()}
+func NewPortfolioService(portfolioDAO dao.PortfolioDAO) PortfolioService {
+	return &portfolioServiceImpl{portfolioDAO: portfolioDAO}
 }
 
 func (p *portfolioServiceImpl) GetPortfolio(ctx context.Context, clientCode string, provider string) (*model.Portfolio, error) {
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/goerr"
+)
+
+type WebhookInboxService interface {
+	ProcessBatch(ctx context.Context) (int, error)
+	FetchDeadLetters(ctx context.Context, vendor string, includeRequeued bool, limit int) ([]model.WebhookDeadLetter, error)
+	RequeueDeadLetter(ctx context.Context, id int64, requeuedBy string) (bool, error)
+}
+
+type webhookInboxServiceImpl struct {
+	inboxDAO       dao.WebhookInboxDAO
+	webhookService WebhookService
+}
+
+func NewWebhookInboxService(inboxDAO dao.WebhookInboxDAO, webhookService WebhookService) WebhookInboxService {
+	return &webhookInboxServiceImpl{inboxDAO: inboxDAO, webhookService: webhookService}
+}
+
+// ProcessBatch claims a batch of due inbox events and registers them. Failed events are retried with an exponential
+// backoff; undecodable payloads and events out of attempts are moved to the dead letters. Returns the events claimed.
+func (s *webhookInboxServiceImpl) ProcessBatch(ctx context.Context) (int, error) {
+	settings := config.App().WebhookInbox
+	batchSize := int(settings.BatchSize)
+	lease := time.Duration(settings.LeaseSeconds) * time.Second
+	events, err := s.inboxDAO.Claim(ctx, batchSize, lease)
+	if err != nil {
+		return 0, goerr.New(err, "service: claiming webhook inbox events failed")
+	}
+	for _, event := range events {
+		s.process(ctx, event)
+	}
+	return len(events), nil
+}
+
+func (s *webhookInboxServiceImpl) process(ctx context.Context, event entity.WebhookInboxEvent) {
+	err := s.webhookService.RegisterNewEvent(ctx, event.ID, event.Vendor, event.Payload)
+	if err == nil {
+		if err = s.inboxDAO.Complete(ctx, event.ID); err != nil {
+			log.Error(ctx).Err(err).Msgf("unable to complete webhook inbox event %d", event.ID)
+		}
+		return
+	}
+
+	settings := config.App().WebhookInbox
+	maxAttempts := int(settings.MaxAttempts)
+	if goerr.Code(err) == http.StatusBadRequest || event.Attempts >= maxAttempts {
+		log.Error(ctx).Err(err).Msgf("moving webhook inbox event %d to dead letters after %d attempts", event.ID, event.Attempts)
+		if err = s.inboxDAO.DeadLetter(ctx, event, err.Error()); err != nil {
+			log.Error(ctx).Err(err).Msgf("unable to dead letter webhook inbox event %d", event.ID)
+		}
+		return
+	}
+
+	base := time.Duration(settings.BackoffSeconds) * time.Second
+	max := time.Duration(settings.MaxBackoffSeconds) * time.Second
+	delay := inboxRetryDelay(event.Attempts, base, max)
+	log.Warn(ctx).Err(err).Msgf("webhook inbox event %d failed on attempt %d, retrying in %s", event.ID, event.Attempts, delay)
+	if err = s.inboxDAO.Retry(ctx, event.ID, time.Now().Add(delay), err.Error()); err != nil {
+		log.Error(ctx).Err(err).Msgf("unable to schedule retry of webhook inbox event %d", event.ID)
+	}
+}
+
+func (s *webhookInboxServiceImpl) FetchDeadLetters(ctx context.Context, vendor string, includeRequeued bool, limit int) ([]model.WebhookDeadLetter, error) {
+	deadLetters, err := s.inboxDAO.FetchDeadLetters(ctx, vendor, includeRequeued, limit)
+	if err != nil {
+		return nil, goerr.New(err, "service: fetching webhook dead letters failed")
+	}
+	response := []model.WebhookDeadLetter{}
+	for _, deadLetter := range deadLetters {
+		response = append(response, model.WebhookDeadLetter{ID: deadLetter.ID, Provider: deadLetter.Vendor, Attempts: deadLetter.Attempts, LastError: deadLetter.LastError, Payload: deadLetter.Payload,
+			ReceivedAt: deadLetter.ReceivedAt, FailedAt: deadLetter.CreatedAt, RequeuedAt: deadLetter.RequeuedAt, RequeuedBy: deadLetter.RequeuedBy})
+	}
+	return response, nil
+}
+
+// RequeueDeadLetter returns false when there is no dead letter waiting to be requeued with the id
+func (s *webhookInboxServiceImpl) RequeueDeadLetter(ctx context.Context, id int64, requeuedBy string) (bool, error) {
+	requeued, err := s.inboxDAO.Requeue(ctx, id, requeuedBy)
+	if err != nil {
+		return false, goerr.New(err, "service: requeueing webhook dead letter failed")
+	}
+	if requeued {
+		log.Info(ctx).Msgf("webhook dead letter %d requeued by %s", id, requeuedBy)
+	}
+	return requeued, nil
+}
+
+// inboxRetryDelay doubles the base delay for every attempt made, capped at max
+func inboxRetryDelay(attempts int, base time.Duration, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInboxRetryDelay(t *testing.T) {
+	base, max := 10*time.Second, 5*time.Minute
+	assert.Equal(t, 10*time.Second, inboxRetryDelay(1, base, max))
+	assert.Equal(t, 20*time.Second, inboxRetryDelay(2, base, max))
+	assert.Equal(t, 160*time.Second, inboxRetryDelay(5, base, max))
+	assert.Equal(t, max, inboxRetryDelay(6, base, max))
+	assert.Equal(t, max, inboxRetryDelay(40, base, max))
+}
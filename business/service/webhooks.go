@@ -2,55 +2,219 @@ package service
 
 import (
 	"context"
-	"math"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/angel-one/fd-core/business/model"
 	"github.com/angel-one/fd-core/business/repository/dao"
 	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/fd-core/constants"
 	"github.com/angel-one/goerr"
 )
 
-var avgDays float64 = 30.417
-
 type WebhookService interface {
-	RegisterNewEvent(ctx context.Context, vendor string, event model.UpSwingWebhookEvent) error
-
-	// private
-	extractTenure(tenure string) (int, int)
+	EnqueueEvent(ctx context.Context, vendor string, payload json.RawMessage) error
+	EnqueueEvents(ctx context.Context, vendor string, payloads []json.RawMessage) (model.WebhookBatchResult, error)
+	RegisterNewEvent(ctx context.Context, inboxID int64, vendor string, payload json.RawMessage) error
+	RederiveEvents(ctx context.Context, vendor string) (int, error)
 }
 
 type webhookServiceImpl struct {
 	webhookDAO dao.WebhooksEventsDAO
+	journeyDAO dao.JourneyDAO
+	inboxDAO   dao.WebhookInboxDAO
+	refreshes  ClientRefreshService
+}
+
+func NewWebhookService(webhookDAO dao.WebhooksEventsDAO, journeyDAO dao.JourneyDAO, inboxDAO dao.WebhookInboxDAO, refreshes ClientRefreshService) WebhookService {
+	return &webhookServiceImpl{webhookDAO: webhookDAO, journeyDAO: journeyDAO, inboxDAO: inboxDAO, refreshes: refreshes}
+}
+
+// EnqueueEvent validates the payload and stores the raw delivery in webhook_inbox, the inbox workers register it later.
+// A redelivery of an already received payload is acknowledged without queueing it again.
+func (w *webhookServiceImpl) EnqueueEvent(ctx context.Context, vendor string, payload json.RawMessage) error {
+	result, err := w.EnqueueEvents(ctx, vendor, []json.RawMessage{payload})
+	if err != nil {
+		return err
+	}
+	if result.Rejected > 0 {
+		return goerr.New(nil, http.StatusBadRequest, fmt.Sprintf("service: webhook payload rejected: %s", result.Results[0].Reason))
+	}
+	return nil
 }
 
-func DefaultWebhookService() WebhookService {
-	return &webhookServiceImpl{webhookDAO: dao.DefaultWebhookEventsDAO()}
+// EnqueueEvents validates every payload of a batch delivery and queues the valid ones in a single transaction.
+// Invalid payloads are rejected and already received ones reported as duplicates, neither fails the batch.
+func (w *webhookServiceImpl) EnqueueEvents(ctx context.Context, vendor string, payloads []json.RawMessage) (model.WebhookBatchResult, error) {
+	result := model.WebhookBatchResult{Results: make([]model.WebhookEventResult, len(payloads))}
+	var events []entity.WebhookInboxEvent
+	var indexes []int
+	for i, payload := range payloads {
+		result.Results[i] = model.WebhookEventResult{Index: i}
+		event, err := w.decode(vendor, payload)
+		if err != nil {
+			result.Results[i].Status = constants.WebhookEventRejected
+			result.Results[i].Reason = err.Error()
+			result.Rejected++
+			continue
+		}
+		events = append(events, entity.WebhookInboxEvent{Vendor: vendor, Payload: payload, DedupKey: dedupKey(event)})
+		indexes = append(indexes, i)
+	}
+	if len(events) == 0 {
+		return result, nil
+	}
+
+	queued, err := w.inboxDAO.EnqueueBatch(ctx, events)
+	if err != nil {
+		return result, goerr.New(err, "service: webhook inbox enqueue failed")
+	}
+	for j, i := range indexes {
+		if queued[j] {
+			result.Results[i].Status = constants.WebhookEventAccepted
+			result.Accepted++
+		} else {
+			result.Results[i].Status = constants.WebhookEventDuplicate
+			result.Results[i].Reason = "event already received"
+			result.Duplicates++
+		}
+	}
+	log.Info(ctx).Msgf("webhook delivery of %d events queued; accepted: %d; duplicates: %d; rejected: %d", len(payloads), result.Accepted, result.Duplicates, result.Rejected)
+	return result, nil
 }
 
-func (w *webhookServiceImpl) RegisterNewEvent(ctx context.Context, vendor string, event model.UpSwingWebhookEvent) error {
-	entity := entity.WebhookEvent{ClientCode: event.Pci, Vendor: vendor, TrackingId: event.JourneyID, EventType: event.EventType, Institution: event.Fsi, Type: event.TermDepositType, Amount: event.Amount, FailureReason: event.Reason, CreatedBy: "webhook-api", UpdatedBy: "webhook-api"}
-	entity.TenureMonths, entity.TenureDays = w.extractTenure(event.Tenure)
-	err := w.webhookDAO.SaveNewEvent(ctx, entity)
+// RegisterNewEvent stores the event of an inbox event, an inbox event processed again is registered only once
+func (w *webhookServiceImpl) RegisterNewEvent(ctx context.Context, inboxID int64, vendor string, payload json.RawMessage) error {
+	entity, err := w.decode(vendor, payload)
+	if err != nil {
+		return goerr.New(err, http.StatusBadRequest, "service: webhook payload decoding failed")
+	}
+	entity.InboxID = inboxID
+	entity.CreatedBy = "webhook-api"
+	entity.UpdatedBy = "webhook-api"
+	entity, inserted, err := w.webhookDAO.SaveNewEvent(ctx, entity)
 	if err != nil {
 		return goerr.New(err, "service: webhook event registration failed")
 	}
+	if !inserted {
+		log.Warn(ctx).Msgf("webhook inbox event %d is already registered, skipping it", inboxID)
+		return nil
+	}
+	w.trackJourney(ctx, entity)
+	w.queueRefresh(ctx, entity)
 	return nil
 }
 
-// sample formats: 22m0d 0m1826d 0m582d 11m11d
-func (w *webhookServiceImpl) extractTenure(tenure string) (int, int) {
-	if tenure != "" && len(tenure) > 0 {
-		monthsIndex := strings.Index(tenure, "m")
-		daysIndex := strings.Index(tenure, "d")
-		months, _ := strconv.Atoi(tenure[0:monthsIndex])
-		days, _ := strconv.Atoi(tenure[monthsIndex+1 : daysIndex])
-		if months == 0 && days > 31 {
-			months = int(math.Round(float64(days) / avgDays))
-			days = 0
+// queueRefresh queues a debounced refresh of the client of the event. The to_be_refreshed flag set with the event
+// stays the fallback, so a failure here is only logged.
+func (w *webhookServiceImpl) queueRefresh(ctx context.Context, event entity.WebhookEvent) {
+	if event.ClientCode == "" {
+		return
+	}
+	if err := w.refreshes.Enqueue(ctx, event.Vendor, event.ClientCode); err != nil {
+		log.Error(ctx).Err(err).Msgf("client refresh queueing failed for clientCode: %s", event.ClientCode)
+	}
+}
+
+// trackJourney moves the booking journey of the event through the state machine. The event is already stored, so a
+// failure here is only logged; the journey can be rebuilt from webhook_events with a replay. An event that can not
+// start a journey, like a withdrawal, is not applied to a journey not seen before.
+func (w *webhookServiceImpl) trackJourney(ctx context.Context, event entity.WebhookEvent) {
+	if event.TrackingId == "" {
+		return
+	}
+	found, err := w.journeyDAO.ApplyEvent(ctx, event, startsJourney(event.EventType), func(state string) string {
+		next, ok := nextJourneyState(state, event.EventType)
+		if !ok {
+			log.Warn(ctx).Msgf("invalid journey transition for trackingId: %s; state: %s; event: %s", event.TrackingId, state, event.EventType)
+		}
+		return next
+	})
+	if err != nil {
+		log.Error(ctx).Err(err).Msgf("journey state update failed for trackingId: %s", event.TrackingId)
+		return
+	}
+	if !found {
+		log.Warn(ctx).Msgf("rejected event %s of unknown journey trackingId: %s, it does not start a journey", event.EventType, event.TrackingId)
+	}
+}
+
+// RederiveEvents re-runs the decoders over the stored raw payloads of a vendor and rewrites the normalized columns,
+// used after a parser fix. Returns the number of events updated.
+func (w *webhookServiceImpl) RederiveEvents(ctx context.Context, vendor string) (int, error) {
+	batchSize := int(config.App().Webhooks.RederiveBatchSize)
+	var updated int
+	var lastID int64
+	for {
+		events, err := w.webhookDAO.FetchRawEvents(ctx, vendor, lastID, batchSize)
+		if err != nil {
+			return updated, goerr.New(err, "service: fetching raw webhook events failed")
+		}
+		if len(events) == 0 {
+			break
 		}
-		return months, days
+		for _, event := range events {
+			lastID = event.ID
+			derived, err := w.decode(vendor, event.RawPayload)
+			if err != nil {
+				log.Warn(ctx).Err(err).Msgf("skipping webhook event %d, raw payload could not be decoded", event.ID)
+				continue
+			}
+			derived.ID = event.ID
+			derived.UpdatedBy = "webhook-rederive"
+			err = w.webhookDAO.UpdateNormalizedEvent(ctx, derived)
+			if err != nil {
+				return updated, goerr.New(err, "service: webhook event re-derivation failed")
+			}
+			updated++
+		}
+	}
+	log.Info(ctx).Msgf("re-derived %d webhook events for vendor: %s", updated, vendor)
+	return updated, nil
+}
+
+// decode normalizes the payload with the normalizer of the vendor into the webhook_events columns
+func (w *webhookServiceImpl) decode(vendor string, payload json.RawMessage) (entity.WebhookEvent, error) {
+	normalizer, ok := webhookNormalizers[vendor]
+	if !ok {
+		return entity.WebhookEvent{}, goerr.New(nil, fmt.Sprintf("no webhook normalizer for vendor: %s", vendor))
+	}
+	event, err := normalizer.Normalize(payload)
+	if err != nil {
+		return entity.WebhookEvent{}, err
+	}
+	return entity.WebhookEvent{ClientCode: event.ClientCode, Vendor: vendor, TrackingId: event.JourneyID, EventType: event.EventType, Institution: event.Fsi, Type: event.DepositType, Amount: event.Amount,
+		TenureMonths: event.TenureMonths, TenureDays: event.TenureDays, FailureReason: event.FailureReason, RawPayload: payload, PayloadVersion: event.PayloadVersion}, nil
+}
+
+// dedupKey identifies a delivery by its normalized event, so a redelivery encoding the same values differently, like
+// an amount of 10000 and 10000.0, is still a duplicate. An event without a journey id is told apart from another one of
+// the same values by its payload, re-encoded so key order and whitespace do not matter.
+func dedupKey(event entity.WebhookEvent) string {
+	fields := []string{event.Vendor, event.ClientCode, event.TrackingId, event.EventType, event.Institution, event.Type,
+		strconv.FormatFloat(event.Amount, 'f', -1, 64), strconv.Itoa(event.TenureMonths), strconv.Itoa(event.TenureDays), event.FailureReason}
+	if event.TrackingId == "" {
+		fields = append(fields, canonicalPayload(event.RawPayload))
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
+
+func canonicalPayload(payload json.RawMessage) string {
+	var value interface{}
+	if err := json.Unmarshal(payload, &value); err != nil {
+		return string(payload)
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return string(payload)
 	}
-	return 0, 0
+	return string(encoded)
 }
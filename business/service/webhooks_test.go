@@ -1,16 +1,18 @@
 package service
 
 import (
+	"context"
+	"encoding/json"
 	"testing"
+	"time"
 
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/repository/entity"
+	"github.com/angel-one/fd-core/constants"
 	"github.com/stretchr/testify/assert"
 )
 
-var webhookService WebhookService
-
-func init() {
-	webhookService = &webhookServiceImpl{}
-}
+var webhookService = &webhookServiceImpl{}
 
 type pattern struct {
 	Pattern string
@@ -22,8 +24,142 @@ func TestExtractTenure(t *testing.T) {
 	patterns := []pattern{{Pattern: "3m3d", Days: 3, Months: 3}, {Pattern: "0m0d", Days: 0, Months: 0}, {Pattern: "0m548d", Days: 0, Months: 18}, {Pattern: "0m730d", Days: 0, Months: 24}, {Pattern: "0m1826d", Days: 0, Months: 60}, {Pattern: "40m20d", Days: 20, Months: 40}, {Pattern: "11m11d", Days: 11, Months: 11}, {Pattern: "22m0d", Days: 0, Months: 22}}
 	for _, p := range patterns {
 		t.Logf("tesitng pattern: %s", p.Pattern)
-		months, days := webhookService.extractTenure(p.Pattern)
+		months, days, err := parseTenure(p.Pattern)
+		assert.NoError(t, err)
 		assert.Equal(t, p.Months, months, "Invalid Months")
 		assert.Equal(t, p.Days, days, "Invalid Days")
 	}
+	for _, malformed := range []string{"12", "12m", "30d", "5d2m", "xm3d", "3m-d", "3m3d3"} {
+		_, _, err := parseTenure(malformed)
+		assert.Error(t, err, "malformed tenure %s must not be parsed", malformed)
+	}
+}
+
+func TestDecodeUpSwingPayload(t *testing.T) {
+	payload := json.RawMessage(`{"pci":"S1614297","fsi":"BJFLIN","amount":15000,"tenure":"0m1826d","journeyId":"J-1","eventType":"TD_BOOKED","termDepositType":"CUMULATIVE","maturityDate":"2029-06-01"}`)
+	event, err := webhookService.decode(constants.UpSwingProvider, payload)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "S1614297", event.ClientCode)
+		assert.Equal(t, "TD_BOOKED", event.EventType)
+		assert.Equal(t, 60, event.TenureMonths, "Invalid Months")
+		assert.Equal(t, constants.WebhookPayloadV1, event.PayloadVersion)
+		assert.JSONEq(t, string(payload), string(event.RawPayload), "raw payload must be kept untouched")
+	}
+
+	_, err = webhookService.decode(constants.UpSwingProvider, json.RawMessage(`{"version":"v99","pci":"S1614297"}`))
+	assert.Error(t, err, "unknown payload version must not be decoded")
+
+	_, err = webhookService.decode(constants.UpSwingProvider, json.RawMessage(`{"pci":"S1614297","tenure":"5d2m","eventType":"TD_BOOKED"}`))
+	assert.Error(t, err, "malformed tenure must not be decoded")
+
+	_, err = webhookService.decode(constants.UpSwingProvider, json.RawMessage(`[{"pci":"S1614297"}]`))
+	assert.Error(t, err, "non object payload must not be decoded")
+}
+
+func TestDedupKey(t *testing.T) {
+	key := func(payload string) string {
+		event, err := webhookService.decode(constants.UpSwingProvider, json.RawMessage(payload))
+		assert.NoError(t, err)
+		return dedupKey(event)
+	}
+	booked := key(`{"pci":"S1614297","journeyId":"J-1","eventType":"TD_BOOKED","amount":10000}`)
+	assert.Len(t, booked, 64)
+	assert.Equal(t, booked, key(`{ "amount": 10000.0, "eventType": "TD_BOOKED", "journeyId": "J-1", "pci": "S1614297" }`), "encoding of the values must not matter")
+	assert.Equal(t, booked, key(`{"pci":"S1614297","journeyId":"J-1","eventType":"TD_BOOKED","amount":10000,"deliveredAt":"2026-10-19T10:00:00Z"}`),
+		"fields not normalized must not matter")
+	assert.NotEqual(t, booked, key(`{"pci":"S1614297","journeyId":"J-1","eventType":"TD_BOOKED","amount":10001}`))
+	assert.NotEqual(t, booked, key(`{"pci":"S1614297","journeyId":"J-2","eventType":"TD_BOOKED","amount":10000}`))
+
+	// without a journey id the payload tells the events apart
+	withoutJourney := key(`{"pci":"S1614297","eventType":"TD_BOOKED","amount":10000,"deliveredAt":"2026-10-19T10:00:00Z"}`)
+	assert.Equal(t, withoutJourney, key(`{"deliveredAt":"2026-10-19T10:00:00Z","amount":10000.0,"eventType":"TD_BOOKED","pci":"S1614297"}`))
+	assert.NotEqual(t, withoutJourney, key(`{"pci":"S1614297","eventType":"TD_BOOKED","amount":10000,"deliveredAt":"2026-10-20T10:00:00Z"}`))
+}
+
+type fakeWebhookEventsDAO struct {
+	saved []entity.WebhookEvent
+}
+
+func (f *fakeWebhookEventsDAO) SaveNewEvent(ctx context.Context, event entity.WebhookEvent) (entity.WebhookEvent, bool, error) {
+	for _, saved := range f.saved {
+		if saved.InboxID == event.InboxID {
+			return event, false, nil
+		}
+	}
+	event.ID = int64(len(f.saved) + 1)
+	f.saved = append(f.saved, event)
+	return event, true, nil
+}
+
+func (f *fakeWebhookEventsDAO) FetchRawEvents(ctx context.Context, vendor string, afterID int64, limit int) ([]entity.WebhookEvent, error) {
+	return nil, nil
+}
+
+func (f *fakeWebhookEventsDAO) UpdateNormalizedEvent(ctx context.Context, event entity.WebhookEvent) error {
+	return nil
+}
+
+type fakeClientRefreshService struct {
+	enqueued int
+}
+
+func (f *fakeClientRefreshService) Enqueue(ctx context.Context, provider string, clientCode string) error {
+	f.enqueued++
+	return nil
+}
+
+func (f *fakeClientRefreshService) ProcessBatch(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func TestRegisterNewEventOncePerInboxEvent(t *testing.T) {
+	webhookDAO := &fakeWebhookEventsDAO{}
+	refreshes := &fakeClientRefreshService{}
+	service := &webhookServiceImpl{webhookDAO: webhookDAO, refreshes: refreshes}
+	payload := json.RawMessage(`{"pci":"S1614297","fsi":"BJFLIN","amount":15000,"tenure":"0m1826d","eventType":"TD_BOOKED"}`)
+
+	assert.NoError(t, service.RegisterNewEvent(context.Background(), 7, constants.UpSwingProvider, payload))
+	// processed again after its completion failed
+	assert.NoError(t, service.RegisterNewEvent(context.Background(), 7, constants.UpSwingProvider, payload))
+	assert.Len(t, webhookDAO.saved, 1)
+	assert.Equal(t, int64(7), webhookDAO.saved[0].InboxID)
+	assert.Equal(t, 1, refreshes.enqueued)
+}
+
+type fakeJourneyDAO struct {
+	dao.JourneyDAO
+	states  map[string]string
+	applied []entity.WebhookEvent
+}
+
+func (f *fakeJourneyDAO) ApplyEvent(ctx context.Context, event entity.WebhookEvent, create bool, transition func(state string) string) (bool, error) {
+	state, ok := f.states[event.TrackingId]
+	if !ok && !create {
+		return false, nil
+	}
+	if !ok {
+		state = constants.JourneyStateInitiated
+	}
+	f.states[event.TrackingId] = transition(state)
+	f.applied = append(f.applied, event)
+	return true, nil
+}
+
+func TestTrackJourney(t *testing.T) {
+	journeyDAO := &fakeJourneyDAO{states: map[string]string{"J-1": constants.JourneyStateBooked}}
+	service := &webhookServiceImpl{journeyDAO: journeyDAO}
+	createdAt := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+	// a withdrawal does not make up a journey it was never booked in
+	service.trackJourney(context.Background(), entity.WebhookEvent{TrackingId: "J-2", EventType: constants.EventWithdrawalBankSuccess, CreatedAt: createdAt})
+	assert.NotContains(t, journeyDAO.states, "J-2")
+
+	service.trackJourney(context.Background(), entity.WebhookEvent{TrackingId: "J-1", EventType: constants.EventWithdrawalBankSuccess, CreatedAt: createdAt})
+	assert.Equal(t, constants.JourneyStateWithdrawn, journeyDAO.states["J-1"])
+
+	service.trackJourney(context.Background(), entity.WebhookEvent{TrackingId: "J-3", EventType: constants.EventVKYCRequired, CreatedAt: createdAt})
+	assert.Equal(t, constants.JourneyStateKYC, journeyDAO.states["J-3"])
+	if assert.Len(t, journeyDAO.applied, 2) {
+		assert.Equal(t, createdAt, journeyDAO.applied[0].CreatedAt)
+	}
 }
@@ -0,0 +1,26 @@
+package workers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/angel-one/fd-core/business/service"
+	"github.com/angel-one/fd-core/commons/config"
+	fdctx "github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/commons/log"
+)
+
+const ClientRefreshWorker = "clientRefreshWorker"
+
+// StartClientRefreshWorkers starts the pool refreshing the clients queued by webhook events once their debounce is
+// over. No workers leaves the clients to the instant refreshers.
+func StartClientRefreshWorkers(refreshService service.ClientRefreshService) {
+	settings := config.App().ClientRefresh
+	workers := int(settings.Workers)
+	pollInterval := time.Duration(settings.PollIntervalSeconds) * time.Second
+	for i := 0; i < workers; i++ {
+		running.Add(1)
+		go runWorker(fdctx.Background(fmt.Sprintf("%s-%d", ClientRefreshWorker, i)), "client refresh", pollInterval, refreshService.ProcessBatch)
+	}
+	log.Info(fdctx.Background(ClientRefreshWorker)).Msgf("started %d client refresh workers", workers)
+}
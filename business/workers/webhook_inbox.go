@@ -0,0 +1,26 @@
+package workers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/angel-one/fd-core/business/service"
+	"github.com/angel-one/fd-core/commons/config"
+	fdctx "github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/commons/log"
+)
+
+const WebhookInboxWorker = "webhookInboxWorker"
+
+// StartWebhookInboxWorkers starts the pool draining webhook_inbox. Every worker claims its own batch, so workers of
+// this and other instances never process the same event at once.
+func StartWebhookInboxWorkers(inboxService service.WebhookInboxService) {
+	settings := config.App().WebhookInbox
+	workers := int(settings.Workers)
+	pollInterval := time.Duration(settings.PollIntervalSeconds) * time.Second
+	for i := 0; i < workers; i++ {
+		running.Add(1)
+		go runWorker(fdctx.Background(fmt.Sprintf("%s-%d", WebhookInboxWorker, i)), "webhook inbox", pollInterval, inboxService.ProcessBatch)
+	}
+	log.Info(fdctx.Background(WebhookInboxWorker)).Msgf("started %d webhook inbox workers", workers)
+}
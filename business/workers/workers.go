@@ -0,0 +1,61 @@
+package workers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/goerr"
+)
+
+// stopped is closed once the workers are asked to stop, a worker stops after the batch it is processing
+var stopped = make(chan struct{})
+
+var stopOnce sync.Once
+
+var running sync.WaitGroup
+
+// StopWorkers stops the workers after their current batch and waits for them until ctx is done. The batch is processed
+// with the context of the worker, so that it is not cut off midway.
+func StopWorkers(ctx context.Context) error {
+	stopOnce.Do(func() {
+		close(stopped)
+	})
+	done := make(chan struct{})
+	go func() {
+		running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		log.Info(ctx).Msg("workers stopped")
+		return nil
+	case <-ctx.Done():
+		return goerr.New(ctx.Err(), "workers: workers did not stop in time")
+	}
+}
+
+// runWorker processes batches while there is work and polls once there is none, until the workers are stopped
+func runWorker(ctx context.Context, name string, pollInterval time.Duration, processBatch func(ctx context.Context) (int, error)) {
+	defer running.Done()
+	for {
+		select {
+		case <-stopped:
+			return
+		default:
+		}
+		processed, err := processBatch(ctx)
+		if err != nil {
+			log.Error(ctx).Err(err).Msgf("%s batch processing failed", name)
+		}
+		if processed > 0 && err == nil {
+			continue
+		}
+		select {
+		case <-stopped:
+			return
+		case <-time.After(pollInterval):
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/angel-one/fd-core/app"
+	"github.com/angel-one/fd-core/business/jobs"
+	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/fd-core/commons/flags"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/fd-core/scripts/dbschema"
+)
+
+// command is a sub-command of the binary, its hooks are started before run and stopped once run returned
+type command struct {
+	usage string
+	hooks func() []app.Hook
+	run   func(ctx context.Context) error
+}
+
+func commands() map[string]command {
+	return map[string]command{
+		constants.ServeCommand: {usage: "serve: serve the api and run the cron jobs and workers until SIGINT or SIGTERM, the default command",
+			hooks: func() []app.Hook { return append(bootHooks(), serviceHooks()...) }, run: serve},
+		constants.MigrateCommand: {usage: "migrate up|down|status: apply the pending migrations, roll back the latest one or list them",
+			hooks: func() []app.Hook { return append(configHooks(), databaseHook()) }, run: migrate},
+		constants.RunJobCommand: {usage: "run-job <name> [--instant] [--clients=S1614297,S1614298]: run a job in the foreground",
+			hooks: bootHooks, run: runJob},
+		constants.ReplayCommand: {usage: "replay [--client=S1614297] [--from=2024-06-01] [--to=2024-07-01] [--dry-run]: rebuild the derived state from webhook_events",
+			hooks: bootHooks, run: runReplay},
+		constants.SeedCommand: {usage: "seed: insert the local development data, refused in release mode",
+			hooks: func() []app.Hook { return append(configHooks(), databaseHook()) }, run: seed},
+		constants.CheckConfigCommand: {usage: "check-config: validate the configs and exit",
+			hooks: func() []app.Hook { return nil }, run: checkConfig},
+	}
+}
+
+func printUsage() {
+	all := commands()
+	names := make([]string, 0, len(all))
+	for name := range all {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(os.Stderr, "usage:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  fd-core %s\n", all[name].usage)
+	}
+}
+
+// serve runs until SIGINT or SIGTERM
+func serve(ctx context.Context) error {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	received := <-signals
+	log.Info(ctx).Msgf("received %s, shutting down", received)
+	return nil
+}
+
+// migrate runs the embedded scripts/dbschema migrations against the configured database, usage:
+// fd-core migrate up|down|status
+func migrate(ctx context.Context) error {
+	args := flags.CommandArgs()
+	if len(args) != 1 {
+		return fmt.Errorf("migrate expects one of %s, %s or %s", constants.MigrateUp, constants.MigrateDown, constants.MigrateStatus)
+	}
+	migrator, err := database.NewMigrator(database.GetDBPool(database.Write), dbschema.Migrations)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case constants.MigrateUp:
+		applied, err := migrator.Up(ctx)
+		fmt.Fprintf(os.Stdout, "applied %d migrations\n", len(applied))
+		return err
+	case constants.MigrateDown:
+		migration, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if migration == nil {
+			fmt.Fprintln(os.Stdout, "no migration to roll back")
+			return nil
+		}
+		fmt.Fprintf(os.Stdout, "rolled back %s\n", migration.Name)
+		return nil
+	case constants.MigrateStatus:
+		states, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, "Applied At\tMigration")
+		for _, state := range states {
+			appliedAt := "Pending"
+			if state.AppliedAt != nil {
+				appliedAt = state.AppliedAt.Format(time.DateTime)
+			}
+			fmt.Fprintf(writer, "%s\t%s\n", appliedAt, state.Name)
+		}
+		return writer.Flush()
+	default:
+		return fmt.Errorf("unknown migrate action %s", args[0])
+	}
+}
+
+// runJob runs a cron job in the foreground and prints its run, usage:
+// fd-core run-job portfolioUpdateCron [--instant] [--clients=S1614297,S1614298]
+func runJob(ctx context.Context) error {
+	args := flags.CommandArgs()
+	if len(args) != 1 {
+		return fmt.Errorf("run-job expects the job name, one of %s", strings.Join(jobs.JobNames, ", "))
+	}
+	options := jobs.RunOptions{Clients: flags.RunJobClients()}
+	if flags.RunJobInstant() {
+		options.Refresher = "instant"
+	}
+
+	run, err := deps.jobs.RunJob(ctx, args[0], options)
+	if run.JobName != "" {
+		output, _ := json.MarshalIndent(run, "", "  ")
+		fmt.Fprintln(os.Stdout, string(output))
+	}
+	return err
+}
+
+// seed inserts the local development data, refused in release mode so it never reaches a shared database
+func seed(ctx context.Context) error {
+	if flags.Mode() == constants.ReleaseMode {
+		return errors.New("seed is refused in release mode")
+	}
+	if _, err := database.GetDBPool(database.Write).ExecContext(ctx, dbschema.LocalSeed); err != nil {
+		return fmt.Errorf("seeding local data failed: %w", err)
+	}
+	log.Info(ctx).Msg("local data seeded")
+	return nil
+}
+
+// checkConfig loads the configs and reports every missing or invalid value, nothing is connected to
+func checkConfig(ctx context.Context) error {
+	if err := initConfig(ctx); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "config of environment %s is valid\n", flags.Env())
+	return nil
+}
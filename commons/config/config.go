@@ -93,13 +93,13 @@ func (c *Client) GetStringWithEnv(config, key string) (string, error) {
 }
 
 func (c *Client) GetIntFromMap(options map[string]interface{}, key string, defaultv int) (int, error) {
-	var val interface{}
-	var ok bool
-	var s int
-	if val, ok = options[key]; ok {
-		if s, ok = val.(int); !ok {
-			return s, fmt.Errorf("invalid %s, must be a int", key)
-		}
+	val, ok := options[key]
+	if !ok {
+		return defaultv, nil
+	}
+	s, err := cast.ToIntE(val)
+	if err != nil {
+		return defaultv, fmt.Errorf("invalid %s, must be a int", key)
 	}
 	return s, nil
 }
@@ -123,6 +123,14 @@ func (c *Client) GetStringWithSecretsFromMap(options map[string]interface{}, key
 	return c.ReplaceWithSecret(s), err
 }
 
+// Secret returns the secret of the store, a secret missing from the store is read from the secrets of the client
+func (c *Client) Secret(key string) string {
+	if value, ok := c.Secrets[key]; ok {
+		return value
+	}
+	return c.getStringSecretD(key, "")
+}
+
 // Replaces the specified string with the secrets variable values for all name placeholders
 func (c *Client) ReplaceWithSecret(value string) string {
 	var s string = value
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/angel-one/fd-core/constants"
+	configs "github.com/angel-one/go-config-client"
+)
+
+// LoadSettings decodes the settings from the configs of the client and validates them, the settings are only in
+// effect when every config decodes and validates. Every problem found is reported in the returned error.
+func LoadSettings(httpClientKeys []string) (*Settings, error) {
+	settings, err := decodeSettings(client, httpClientKeys)
+	if err != nil {
+		return nil, err
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	current.Store(settings)
+	return settings, nil
+}
+
+func decodeSettings(c configs.Client, httpClientKeys []string) (*Settings, error) {
+	settings := DefaultSettings()
+	var errs []error
+	errs = append(errs, decode(c, constants.ApplicationConfig, &settings.Application)...)
+	errs = append(errs, decode(c, constants.DatabaseConfig, &settings.Database)...)
+	errs = append(errs, decode(c, constants.LoggerConfig, &settings.Logger)...)
+	for _, key := range httpClientKeys {
+		var httpClient HTTPClient
+		if err := c.Unmarshal(constants.HTTPClientConfig, key, &httpClient); err != nil {
+			errs = append(errs, fmt.Errorf("%s.%s: %w", constants.HTTPClientConfig, key, err))
+			continue
+		}
+		settings.HTTPClients[key] = httpClient
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
+// decode sets every field tagged with a config key from the key of the named config, a missing key keeps the value
+// of the field. The untagged struct fields group keys of the same config and are decoded alike.
+func decode(c configs.Client, name string, out any) []error {
+	var errs []error
+	value := reflect.ValueOf(out).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		key := field.Tag.Get("config")
+		if key == "" {
+			if field.Type.Kind() == reflect.Struct {
+				errs = append(errs, decode(c, name, value.Field(i).Addr().Interface())...)
+			}
+			continue
+		}
+		// decoded into a new value, so a map or slice of the config replaces the default instead of merging into it
+		decoded := reflect.New(field.Type)
+		decoded.Elem().Set(value.Field(i))
+		if field.Type.Kind() == reflect.Map || field.Type.Kind() == reflect.Slice {
+			decoded.Elem().Set(reflect.Zero(field.Type))
+		}
+		err := c.Unmarshal(name, key, decoded.Interface())
+		if errors.Is(err, configs.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s.%s: %w", name, key, err))
+			continue
+		}
+		value.Field(i).Set(decoded.Elem())
+	}
+	return errs
+}
+
+// tagged returns the values of the fields of s by their config key
+func tagged(s any) map[string]any {
+	value := reflect.ValueOf(s)
+	values := make(map[string]any, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		if key := value.Type().Field(i).Tag.Get("config"); key != "" {
+			values[key] = value.Field(i).Interface()
+		}
+	}
+	return values
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	fdcontext "github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/commons/log"
+)
+
+// Listener is called with the settings in effect once a changed config is reloaded
+type Listener func(ctx context.Context, settings *Settings)
+
+var listeners = struct {
+	sync.Mutex
+	all []Listener
+}{}
+
+// reloading serializes the reloads, the providers watch every config on its own goroutine
+var reloading sync.Mutex
+
+// Subscribe adds a listener of the reloaded settings, the listeners are called in the order they subscribed
+func Subscribe(listener Listener) {
+	listeners.Lock()
+	defer listeners.Unlock()
+	listeners.all = append(listeners.all, listener)
+}
+
+// WatchSettings reloads the settings whenever one of the configs changes, the file provider watches the files in test
+// mode and the AppConfig provider polls in release mode. A changed config that does not decode or validate is
+// reported and the settings in effect are kept.
+func WatchSettings(configNames []string, httpClientKeys []string) error {
+	for _, name := range configNames {
+		name := name
+		if err := client.AddChangeListener(name, func(params ...interface{}) {
+			reloadSettings(name, httpClientKeys)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// StopWatching stops reloading the settings on config changes
+func StopWatching(configNames []string) error {
+	for _, name := range configNames {
+		if err := client.RemoveChangeListener(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func reloadSettings(name string, httpClientKeys []string) {
+	reloading.Lock()
+	defer reloading.Unlock()
+	ctx := fdcontext.Background("configReload")
+
+	settings, err := decodeSettings(client, httpClientKeys)
+	if err == nil {
+		err = settings.validate(client)
+	}
+	if err != nil {
+		log.Error(ctx).Err(err).Msgf("config %s changed but is invalid, keeping the settings in effect", name)
+		return
+	}
+	// the pools and http clients are built at startup, their configs stay as they were until the next restart
+	previous := Current()
+	if !reflect.DeepEqual(previous.Database, settings.Database) || !reflect.DeepEqual(previous.HTTPClients, settings.HTTPClients) {
+		log.Warn(ctx).Msg("database and http client configs changed, they apply on the next restart")
+		settings.Database, settings.HTTPClients = previous.Database, previous.HTTPClients
+	}
+	if reflect.DeepEqual(previous, settings) {
+		return
+	}
+	current.Store(settings)
+	log.Info(ctx).Msgf("config %s reloaded", name)
+	notify(ctx, settings)
+}
+
+func notify(ctx context.Context, settings *Settings) {
+	listeners.Lock()
+	all := append([]Listener(nil), listeners.all...)
+	listeners.Unlock()
+	for _, listener := range all {
+		listener(ctx, settings)
+	}
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"sync/atomic"
+
+	"github.com/angel-one/fd-core/constants"
+)
+
+// Settings are the typed configs, decoded once from the config client and validated before they are used. A key
+// missing from a config keeps the default of its field.
+type Settings struct {
+	Application Application
+	Database    Database
+	HTTPClients map[string]HTTPClient
+	Logger      Logger
+}
+
+// Application is the application config
+type Application struct {
+	OriginsAllowedForCors string            `config:"orginsAllowedForCors"`
+	WhitelistedHostHeader string            `config:"whitelistedHostHeader"`
+	AdminUsers            map[string]string `config:"adminUsers"`
+	AdminUnmaskedRoles    []string          `config:"adminUnmaskedRoles"`
+
+	Jobs           Jobs
+	Portfolio      Portfolio
+	PendingJourney PendingJourney
+	Webhooks       Webhooks
+	WebhookInbox   WebhookInbox
+	ClientRefresh  ClientRefresh
+	Revalidation   Revalidation
+	Archival       Archival
+	Reconciliation Reconciliation
+	Shutdown       Shutdown
+}
+
+// Jobs configure the cron jobs, job_config enables them and overrides the schedule, batch size and provider at runtime
+type Jobs struct {
+	Disabled                  bool `config:"jobsDisabled"`
+	Schedules                 JobSchedules
+	LeaseSeconds              int64 `config:"jobLeaseSeconds"`
+	ConfigReloadSeconds       int64 `config:"jobConfigReloadSeconds"`
+	RunHeartbeatSeconds       int64 `config:"jobRunHeartbeatSeconds"`
+	UpswingRateLimitPerSecond int64 `config:"upswingRateLimitPerSecond"`
+	UpswingRateLimitBurst     int64 `config:"upswingRateLimitBurst"`
+}
+
+// JobSchedules are the cron schedules of the jobs, keyed by job name
+type JobSchedules struct {
+	TokenRenewal              string `config:"tokenRenewalCron"`
+	PortfolioUpdate           string `config:"portfolioUpdateCron"`
+	PendingJourneyUpdate      string `config:"pendingJourneyUpdateCron"`
+	PortfolioReconciliation   string `config:"portfolioReconciliationCron"`
+	InvalidClientRevalidation string `config:"invalidClientRevalidationCron"`
+	ClosedRecordArchival      string `config:"closedRecordArchivalCron"`
+}
+
+// Of returns the schedule of the job, empty for an unknown job
+func (s JobSchedules) Of(name string) string {
+	schedule, _ := tagged(s)[name].(string)
+	return schedule
+}
+
+type Portfolio struct {
+	Provider                    string `config:"portfolioProvider"`
+	UpdateBatchSize             int64  `config:"portfolioUpdateBatchSize"`
+	RefreshActiveWindowDays     int64  `config:"portfolioRefreshActiveWindowDays"`
+	RefreshActiveIntervalHours  int64  `config:"portfolioRefreshActiveIntervalHours"`
+	RefreshMaturityWindowDays   int64  `config:"portfolioRefreshMaturityWindowDays"`
+	RefreshDormantIntervalHours int64  `config:"portfolioRefreshDormantIntervalHours"`
+	RefreshMaxStaleHours        int64  `config:"portfolioRefreshMaxStaleHours"`
+}
+
+type PendingJourney struct {
+	Provider        string `config:"pendingJourneyProvider"`
+	UpdateBatchSize int64  `config:"pendingJourneyUpdateBatchSize"`
+	ResumeLink      string `config:"pendingJourneyResumeLink"`
+}
+
+// Webhooks configure the webhook endpoints, Auth maps a provider to its auth strategy, jwt or hmac. JWTSubjects maps a
+// jwt provider to the user_id its tokens must be issued to, without one any user but the guest is accepted. An hmac
+// signed delivery is accepted while its timestamp is within SignatureMaxSkewSeconds of the clock.
+type Webhooks struct {
+	Auth                    map[string]string `config:"webhookAuth"`
+	JWTSubjects             map[string]string `config:"webhookJWTSubjects"`
+	SignatureMaxSkewSeconds int64             `config:"webhookSignatureMaxSkewSeconds"`
+	MaxBatchSize            int64             `config:"webhookMaxBatchSize"`
+	RederiveBatchSize       int64             `config:"webhookRederiveBatchSize"`
+}
+
+type WebhookInbox struct {
+	Workers             int64 `config:"webhookInboxWorkers"`
+	BatchSize           int64 `config:"webhookInboxBatchSize"`
+	PollIntervalSeconds int64 `config:"webhookInboxPollIntervalSeconds"`
+	LeaseSeconds        int64 `config:"webhookInboxLeaseSeconds"`
+	MaxAttempts         int64 `config:"webhookInboxMaxAttempts"`
+	BackoffSeconds      int64 `config:"webhookInboxBackoffSeconds"`
+	MaxBackoffSeconds   int64 `config:"webhookInboxMaxBackoffSeconds"`
+	RetentionDays       int64 `config:"webhookInboxRetentionDays"`
+}
+
+type ClientRefresh struct {
+	DebounceSeconds     int64 `config:"clientRefreshDebounceSeconds"`
+	MaxDelaySeconds     int64 `config:"clientRefreshMaxDelaySeconds"`
+	Workers             int64 `config:"clientRefreshWorkers"`
+	BatchSize           int64 `config:"clientRefreshBatchSize"`
+	PollIntervalSeconds int64 `config:"clientRefreshPollIntervalSeconds"`
+	LeaseSeconds        int64 `config:"clientRefreshLeaseSeconds"`
+	MaxAttempts         int64 `config:"clientRefreshMaxAttempts"`
+	BackoffSeconds      int64 `config:"clientRefreshBackoffSeconds"`
+	MaxBackoffSeconds   int64 `config:"clientRefreshMaxBackoffSeconds"`
+}
+
+type Revalidation struct {
+	Limit           int64 `config:"invalidClientRevalidationLimit"`
+	BackoffHours    int64 `config:"invalidClientRevalidationBackoffHours"`
+	MaxBackoffHours int64 `config:"invalidClientRevalidationMaxBackoffHours"`
+}
+
+type Archival struct {
+	RetentionDays int64 `config:"closedRecordRetentionDays"`
+	BatchSize     int64 `config:"closedRecordArchivalBatchSize"`
+}
+
+type Reconciliation struct {
+	Provider        string `config:"reconciliationProvider"`
+	SampleSize      int64  `config:"reconciliationSampleSize"`
+	MinorPercentage int64  `config:"reconciliationMinorPercentage"`
+	MajorPercentage int64  `config:"reconciliationMajorPercentage"`
+	AlertSeverity   string `config:"reconciliationAlertSeverity"`
+}
+
+type Shutdown struct {
+	HTTPTimeoutSeconds int64 `config:"shutdownHttpTimeoutSeconds"`
+	JobsTimeoutSeconds int64 `config:"shutdownJobsTimeoutSeconds"`
+}
+
+// Database is the database config, the replica pool is optional and serves the reads that tolerate its lag
+type Database struct {
+	Postgres DBPool `config:"postgres-db"`
+	Replica  DBPool `config:"replica-db"`
+	ReplicaRouting
+}
+
+type ReplicaRouting struct {
+	MaxLagSeconds      int64 `config:"replica-max-lag-seconds"`
+	HealthCheckSeconds int64 `config:"replica-health-check-seconds"`
+}
+
+// DBPool is the config of a connection pool, the url holds ${NAME} placeholders of the secrets
+type DBPool struct {
+	DriverName                     string `mapstructure:"drivername"`
+	URL                            string `mapstructure:"url"`
+	MaxOpenConnections             int    `mapstructure:"maxopenconnections"`
+	MaxIdleConnections             int    `mapstructure:"maxidleconnections"`
+	ConnectionMaxLifetimeInSeconds int    `mapstructure:"connectionmaxlifetimeinseconds"`
+	ConnectionMaxIdleTimeInSeconds int    `mapstructure:"connectionmaxidletimeinseconds"`
+	QueryTimeout                   int    `mapstructure:"querytimeout"`
+}
+
+// Configured tells whether the pool is set in the config, only the replica pool may be left out
+func (p DBPool) Configured() bool {
+	return p.URL != ""
+}
+
+// HTTPClient is the config of an outgoing request, the url holds ${NAME} placeholders of the environment
+type HTTPClient struct {
+	Method          string            `mapstructure:"method"`
+	URL             string            `mapstructure:"url"`
+	Headers         map[string]string `mapstructure:"headers"`
+	TimeoutInMillis int64             `mapstructure:"timeoutinmillis"`
+	RetryCount      int64             `mapstructure:"retrycount"`
+}
+
+// Logger is the logger config
+type Logger struct {
+	Level string `config:"level"`
+}
+
+// DefaultSettings are the settings used for every key missing from the configs
+func DefaultSettings() Settings {
+	return Settings{
+		Application: Application{
+			OriginsAllowedForCors: constants.AllowedOriginsForCorsDefault,
+			WhitelistedHostHeader: constants.WhitelistedHostsHeaderDefault,
+			AdminUsers:            map[string]string{},
+			AdminUnmaskedRoles:    []string{"engineer"},
+			Jobs: Jobs{LeaseSeconds: 60, ConfigReloadSeconds: 60, RunHeartbeatSeconds: 5, UpswingRateLimitPerSecond: 0,
+				UpswingRateLimitBurst: 1},
+			Portfolio: Portfolio{UpdateBatchSize: 50, RefreshActiveWindowDays: 7, RefreshActiveIntervalHours: 24, RefreshMaturityWindowDays: 7,
+				RefreshDormantIntervalHours: 168, RefreshMaxStaleHours: 336},
+			PendingJourney: PendingJourney{UpdateBatchSize: 50},
+			Webhooks: Webhooks{Auth: map[string]string{constants.UpSwingProvider: constants.WebhookAuthJWT}, JWTSubjects: map[string]string{},
+				SignatureMaxSkewSeconds: 300, MaxBatchSize: 500,
+				RederiveBatchSize: 500},
+			WebhookInbox: WebhookInbox{Workers: 4, BatchSize: 10, PollIntervalSeconds: 1, LeaseSeconds: 60, MaxAttempts: 8, BackoffSeconds: 10,
+				MaxBackoffSeconds: 1800, RetentionDays: 30},
+			ClientRefresh: ClientRefresh{DebounceSeconds: 30, MaxDelaySeconds: 300, Workers: 2, BatchSize: 10, PollIntervalSeconds: 5, LeaseSeconds: 60,
+				MaxAttempts: 5, BackoffSeconds: 30, MaxBackoffSeconds: 1800},
+			Revalidation:   Revalidation{Limit: 500, BackoffHours: 24, MaxBackoffHours: 720},
+			Archival:       Archival{RetentionDays: 180, BatchSize: 1000},
+			Reconciliation: Reconciliation{SampleSize: 200, MinorPercentage: 1, MajorPercentage: 5, AlertSeverity: constants.SeverityHigh},
+			Shutdown:       Shutdown{HTTPTimeoutSeconds: 15, JobsTimeoutSeconds: 30},
+		},
+		Database: Database{Postgres: DBPool{MaxOpenConnections: 25, MaxIdleConnections: 25, ConnectionMaxLifetimeInSeconds: 90,
+			ConnectionMaxIdleTimeInSeconds: 30}, Replica: DBPool{MaxOpenConnections: 25, MaxIdleConnections: 25, ConnectionMaxLifetimeInSeconds: 90,
+			ConnectionMaxIdleTimeInSeconds: 30}, ReplicaRouting: ReplicaRouting{MaxLagSeconds: 5, HealthCheckSeconds: 10}},
+		HTTPClients: map[string]HTTPClient{},
+		Logger:      Logger{Level: "debug"},
+	}
+}
+
+var current atomic.Pointer[Settings]
+
+// Current returns the loaded settings, the defaults before they are loaded
+func Current() *Settings {
+	if settings := current.Load(); settings != nil {
+		return settings
+	}
+	defaults := DefaultSettings()
+	return &defaults
+}
+
+// App returns the loaded application config
+func App() *Application {
+	return &Current().Application
+}
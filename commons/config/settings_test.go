@@ -0,0 +1,154 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/angel-one/fd-core/constants"
+	"github.com/stretchr/testify/assert"
+)
+
+var testConfigNames = []string{constants.ApplicationConfig, constants.DatabaseConfig, constants.LoggerConfig, constants.HTTPClientConfig}
+
+const testSecrets = `{"dbName":"fd","dbHost":"localhost:5432","dbUsername":"fd","dbPassword":"fd","jwtSymmetricKey":"key",
+"profileServiceToken":"token","upswingClientId":"id","upswingGrantType":"grant","upswingClientSecret":"secret","upswingScope":"scope"}`
+
+// initTestConfigs writes the configs and the test secrets to a directory and loads them, returns the directory
+func initTestConfigs(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "secrets.json"), []byte(testSecrets), 0o600))
+	for name, content := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	assert.NoError(t, InitTestMode(dir, testConfigNames...))
+	return dir
+}
+
+var validConfigs = map[string]string{
+	"application.yml": "portfolioUpdateCron: \"*/5 * * * *\"\nadminUnmaskedRoles: [ops]\nwebhookInboxWorkers: 1\n",
+	"database.yml": "postgres-db:\n  drivername: postgres\n  url: \"postgres://${DATABASE_USERNAME}:${DATABASE_PASSWORD}@${DATABASE_URL}/${DATABASE_NAME}\"\n" +
+		"  maxopenconnections: 5\n  maxidleconnections: 3\n",
+	"logger.yml":      "level: info\n",
+	"http-client.yml": "alertHook:\n  method: POST\n  url: https://alerts.example.com/hook\n  headers:\n    Content-Type: application/json\n  timeoutinmillis: 1000\n",
+}
+
+func TestDecodeSettings(t *testing.T) {
+	initTestConfigs(t, validConfigs)
+
+	settings, err := decodeSettings(Default(), []string{constants.AlertHookConfig})
+	assert.NoError(t, err)
+	assert.Equal(t, "*/5 * * * *", settings.Application.Jobs.Schedules.Of("portfolioUpdateCron"))
+	assert.Empty(t, settings.Application.Jobs.Schedules.Of("unknownCron"))
+	// a configured list replaces the default one
+	assert.Equal(t, []string{"ops"}, settings.Application.AdminUnmaskedRoles)
+	assert.Equal(t, int64(1), settings.Application.WebhookInbox.Workers)
+	// missing keys keep their defaults
+	assert.Equal(t, int64(10), settings.Application.WebhookInbox.BatchSize)
+	assert.Equal(t, int64(30), settings.Application.WebhookInbox.RetentionDays)
+	assert.Equal(t, int64(300), settings.Application.Webhooks.SignatureMaxSkewSeconds)
+	assert.Equal(t, constants.AllowedOriginsForCorsDefault, settings.Application.OriginsAllowedForCors)
+	assert.Equal(t, 5, settings.Database.Postgres.MaxOpenConnections)
+	assert.Equal(t, 30, settings.Database.Postgres.ConnectionMaxIdleTimeInSeconds)
+	assert.Equal(t, "info", settings.Logger.Level)
+	assert.Equal(t, "https://alerts.example.com/hook", settings.HTTPClients[constants.AlertHookConfig].URL)
+	assert.Equal(t, int64(1000), settings.HTTPClients[constants.AlertHookConfig].TimeoutInMillis)
+
+	assert.NoError(t, settings.Validate())
+}
+
+func TestDecodeDevConfigs(t *testing.T) {
+	files := map[string]string{}
+	for _, name := range []string{"application.yml", "database.yml", "logger.yml", "http-client.yml"} {
+		content, err := os.ReadFile(filepath.Join("..", "..", "resources", "dev", name))
+		assert.NoError(t, err)
+		files[name] = string(content)
+	}
+	initTestConfigs(t, files)
+
+	settings, err := decodeSettings(Default(), []string{constants.AlertHookConfig})
+	assert.NoError(t, err)
+	assert.NoError(t, settings.Validate())
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	initTestConfigs(t, map[string]string{
+		"application.yml": "portfolioUpdateCron: \"not a cron\"\nwebhookInboxBatchSize: 0\n" +
+			"webhookAuth:\n  acme: hmac\n  other: basic\nreconciliationMinorPercentage: 10\n",
+		"database.yml":    "postgres-db:\n  drivername: postgres\n  url: \"postgres://${DATABASE_USERNAME}@${DB_REPLICA_HOST}/fd\"\n",
+		"logger.yml":      "level: loud\n",
+		"http-client.yml": "alertHook:\n  method: SEND\n  url: alerts.example.com\n",
+	})
+
+	settings, err := decodeSettings(Default(), []string{constants.AlertHookConfig})
+	assert.NoError(t, err)
+	err = settings.Validate()
+	assert.Error(t, err)
+	for _, problem := range []string{
+		"application.portfolioUpdateCron: invalid cron schedule 'not a cron'",
+		"application.webhookInboxBatchSize: must be positive, got 0",
+		"application.webhookAuth.other: unknown auth strategy basic",
+		"secrets.acmeWebhookSecret: is missing",
+		"application.reconciliationMajorPercentage: must be at least reconciliationMinorPercentage, got 5",
+		"secrets.DB_REPLICA_HOST: is missing, required by database.postgres-db.url",
+		"logger.level: unknown level loud",
+		"http-client.alertHook.method: must be one of GET, POST, PUT, PATCH, DELETE, got 'SEND'",
+		"http-client.alertHook.url: must be an http or https url, got 'alerts.example.com'",
+		"http-client.alertHook.timeoutinmillis: must be positive, got 0",
+	} {
+		assert.Contains(t, err.Error(), problem)
+	}
+}
+
+func TestValidateReplica(t *testing.T) {
+	configs := map[string]string{}
+	for name, content := range validConfigs {
+		configs[name] = content
+	}
+	configs["database.yml"] += "replica-db:\n  drivername: postgres\n  url: \"postgres://${DATABASE_USERNAME}@${DATABASE_REPLICA_URL}/fd\"\n" +
+		"  maxopenconnections: 5\n  maxidleconnections: 8\nreplica-max-lag-seconds: 0\n"
+	initTestConfigs(t, configs)
+
+	settings, err := decodeSettings(Default(), []string{constants.AlertHookConfig})
+	assert.NoError(t, err)
+	assert.True(t, settings.Database.Replica.Configured())
+	assert.Equal(t, int64(10), settings.Database.HealthCheckSeconds)
+	err = settings.Validate()
+	assert.Error(t, err)
+	for _, problem := range []string{
+		"secrets.DATABASE_REPLICA_URL: is missing, required by database.replica-db.url",
+		"database.replica-db.maxidleconnections: must be between 0 and maxopenconnections, got 8",
+		"database.replica-max-lag-seconds: must be positive, got 0",
+	} {
+		assert.Contains(t, err.Error(), problem)
+	}
+}
+
+func TestDecodeSettingsReportsUndecodableKeys(t *testing.T) {
+	initTestConfigs(t, map[string]string{
+		"application.yml": "webhookInboxWorkers: many\nwebhookInboxBatchSize: lots\n",
+		"database.yml":    "postgres-db:\n  maxopenconnections: all\n",
+		"logger.yml":      "level: info\n",
+		"http-client.yml": "alertHook:\n  method: POST\n",
+	})
+
+	_, err := decodeSettings(Default(), []string{constants.AlertHookConfig, "missingClient"})
+	assert.Error(t, err)
+	for _, key := range []string{"application.webhookInboxWorkers", "application.webhookInboxBatchSize", "database.postgres-db", "http-client.missingClient"} {
+		assert.Contains(t, err.Error(), key)
+	}
+}
+
+func TestGetIntFromMap(t *testing.T) {
+	c := &Client{}
+	value, err := c.GetIntFromMap(map[string]interface{}{}, "maxopenconnections", 25)
+	assert.NoError(t, err)
+	assert.Equal(t, 25, value)
+
+	value, err = c.GetIntFromMap(map[string]interface{}{"maxopenconnections": 5}, "maxopenconnections", 25)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, value)
+
+	_, err = c.GetIntFromMap(map[string]interface{}{"maxopenconnections": "all"}, "maxopenconnections", 25)
+	assert.Error(t, err)
+}
@@ -0,0 +1,204 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/robfig/cron/v3"
+)
+
+// secrets every command needs, the webhook secrets are required by the providers authenticated with hmac
+var requiredSecrets = []string{constants.JWTSymmetricKey, constants.ProfileServiceToken, constants.UpswingClientId, constants.UpswingClientSecret,
+	constants.UpswingGrantType, constants.UpswingScope}
+
+var httpMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// validation collects every problem of the settings, keyed by config and key
+type validation struct {
+	errs []error
+}
+
+func (v *validation) check(ok bool, config string, key string, format string, args ...any) {
+	if !ok {
+		v.errs = append(v.errs, fmt.Errorf("%s.%s: %s", config, key, fmt.Sprintf(format, args...)))
+	}
+}
+
+func (v *validation) positive(config string, key string, value int64) {
+	v.check(value > 0, config, key, "must be positive, got %d", value)
+}
+
+func (v *validation) required(config string, key string, value string) {
+	v.check(strings.TrimSpace(value) != "", config, key, "is required")
+}
+
+// Validate checks the settings against the secrets and environment of the config client
+func (s *Settings) Validate() error {
+	return s.validate(client)
+}
+
+func (s *Settings) validate(c *Client) error {
+	v := &validation{}
+	s.Application.validate(v, c)
+	s.Database.validate(v, c)
+	for name, httpClient := range s.HTTPClients {
+		httpClient.validate(v, name, c)
+	}
+	v.check(log.Level(s.Logger.Level).Valid(), constants.LoggerConfig, constants.LogLevelKey, "unknown level %s", s.Logger.Level)
+	for _, secret := range requiredSecrets {
+		v.check(c.hasSecret(secret), "secrets", secret, "is missing")
+	}
+	return errors.Join(v.errs...)
+}
+
+func (a *Application) validate(v *validation, c *Client) {
+	const config = constants.ApplicationConfig
+	v.required(config, "orginsAllowedForCors", a.OriginsAllowedForCors)
+	v.required(config, "whitelistedHostHeader", a.WhitelistedHostHeader)
+	for user, role := range a.AdminUsers {
+		v.required(config, "adminUsers."+user, role)
+	}
+
+	schedules := tagged(a.Jobs.Schedules)
+	for name, schedule := range schedules {
+		if schedule == "" {
+			continue
+		}
+		_, err := cron.ParseStandard(schedule.(string))
+		v.check(err == nil, config, name, "invalid cron schedule '%s': %v", schedule, err)
+	}
+	v.positive(config, "jobLeaseSeconds", a.Jobs.LeaseSeconds)
+	v.positive(config, "jobConfigReloadSeconds", a.Jobs.ConfigReloadSeconds)
+	v.positive(config, "jobRunHeartbeatSeconds", a.Jobs.RunHeartbeatSeconds)
+	v.check(a.Jobs.UpswingRateLimitPerSecond >= 0, config, "upswingRateLimitPerSecond", "must not be negative, got %d", a.Jobs.UpswingRateLimitPerSecond)
+	v.positive(config, "upswingRateLimitBurst", a.Jobs.UpswingRateLimitBurst)
+
+	v.positive(config, "portfolioUpdateBatchSize", a.Portfolio.UpdateBatchSize)
+	v.positive(config, "portfolioRefreshActiveWindowDays", a.Portfolio.RefreshActiveWindowDays)
+	v.positive(config, "portfolioRefreshActiveIntervalHours", a.Portfolio.RefreshActiveIntervalHours)
+	v.positive(config, "portfolioRefreshMaturityWindowDays", a.Portfolio.RefreshMaturityWindowDays)
+	v.positive(config, "portfolioRefreshDormantIntervalHours", a.Portfolio.RefreshDormantIntervalHours)
+	v.check(a.Portfolio.RefreshMaxStaleHours >= a.Portfolio.RefreshDormantIntervalHours, config, "portfolioRefreshMaxStaleHours",
+		"must be at least portfolioRefreshDormantIntervalHours, got %d", a.Portfolio.RefreshMaxStaleHours)
+
+	v.positive(config, "pendingJourneyUpdateBatchSize", a.PendingJourney.UpdateBatchSize)
+	if a.PendingJourney.ResumeLink != "" {
+		link, err := url.Parse(a.PendingJourney.ResumeLink)
+		v.check(err == nil && link.Scheme != "", config, "pendingJourneyResumeLink", "must be an absolute url, got '%s'", a.PendingJourney.ResumeLink)
+	}
+
+	for provider, auth := range a.Webhooks.Auth {
+		v.check(auth == constants.WebhookAuthJWT || auth == constants.WebhookAuthHMAC, config, "webhookAuth."+provider, "unknown auth strategy %s", auth)
+		if auth == constants.WebhookAuthHMAC {
+			v.check(c.hasSecret(provider+constants.WebhookSecretSuffix), "secrets", provider+constants.WebhookSecretSuffix, "is missing")
+		}
+	}
+	v.positive(config, "webhookSignatureMaxSkewSeconds", a.Webhooks.SignatureMaxSkewSeconds)
+	v.positive(config, "webhookMaxBatchSize", a.Webhooks.MaxBatchSize)
+	v.positive(config, "webhookRederiveBatchSize", a.Webhooks.RederiveBatchSize)
+
+	v.check(a.WebhookInbox.Workers >= 0, config, "webhookInboxWorkers", "must not be negative, got %d", a.WebhookInbox.Workers)
+	v.positive(config, "webhookInboxBatchSize", a.WebhookInbox.BatchSize)
+	v.positive(config, "webhookInboxPollIntervalSeconds", a.WebhookInbox.PollIntervalSeconds)
+	v.positive(config, "webhookInboxLeaseSeconds", a.WebhookInbox.LeaseSeconds)
+	v.positive(config, "webhookInboxMaxAttempts", a.WebhookInbox.MaxAttempts)
+	v.positive(config, "webhookInboxBackoffSeconds", a.WebhookInbox.BackoffSeconds)
+	v.check(a.WebhookInbox.MaxBackoffSeconds >= a.WebhookInbox.BackoffSeconds, config, "webhookInboxMaxBackoffSeconds",
+		"must be at least webhookInboxBackoffSeconds, got %d", a.WebhookInbox.MaxBackoffSeconds)
+	v.positive(config, "webhookInboxRetentionDays", a.WebhookInbox.RetentionDays)
+
+	v.check(a.ClientRefresh.DebounceSeconds >= 0, config, "clientRefreshDebounceSeconds", "must not be negative, got %d", a.ClientRefresh.DebounceSeconds)
+	v.check(a.ClientRefresh.MaxDelaySeconds >= a.ClientRefresh.DebounceSeconds, config, "clientRefreshMaxDelaySeconds",
+		"must be at least clientRefreshDebounceSeconds, got %d", a.ClientRefresh.MaxDelaySeconds)
+	v.check(a.ClientRefresh.Workers >= 0, config, "clientRefreshWorkers", "must not be negative, got %d", a.ClientRefresh.Workers)
+	v.positive(config, "clientRefreshBatchSize", a.ClientRefresh.BatchSize)
+	v.positive(config, "clientRefreshPollIntervalSeconds", a.ClientRefresh.PollIntervalSeconds)
+	v.positive(config, "clientRefreshLeaseSeconds", a.ClientRefresh.LeaseSeconds)
+	v.positive(config, "clientRefreshMaxAttempts", a.ClientRefresh.MaxAttempts)
+	v.positive(config, "clientRefreshBackoffSeconds", a.ClientRefresh.BackoffSeconds)
+	v.check(a.ClientRefresh.MaxBackoffSeconds >= a.ClientRefresh.BackoffSeconds, config, "clientRefreshMaxBackoffSeconds",
+		"must be at least clientRefreshBackoffSeconds, got %d", a.ClientRefresh.MaxBackoffSeconds)
+
+	v.positive(config, "invalidClientRevalidationLimit", a.Revalidation.Limit)
+	v.positive(config, "invalidClientRevalidationBackoffHours", a.Revalidation.BackoffHours)
+	v.check(a.Revalidation.MaxBackoffHours >= a.Revalidation.BackoffHours, config, "invalidClientRevalidationMaxBackoffHours",
+		"must be at least invalidClientRevalidationBackoffHours, got %d", a.Revalidation.MaxBackoffHours)
+
+	v.positive(config, "closedRecordRetentionDays", a.Archival.RetentionDays)
+	v.positive(config, "closedRecordArchivalBatchSize", a.Archival.BatchSize)
+
+	v.check(a.Reconciliation.SampleSize >= 0, config, "reconciliationSampleSize", "must not be negative, got %d", a.Reconciliation.SampleSize)
+	v.positive(config, "reconciliationMinorPercentage", a.Reconciliation.MinorPercentage)
+	v.check(a.Reconciliation.MajorPercentage >= a.Reconciliation.MinorPercentage, config, "reconciliationMajorPercentage",
+		"must be at least reconciliationMinorPercentage, got %d", a.Reconciliation.MajorPercentage)
+	v.check(slices.Contains(constants.Severities, a.Reconciliation.AlertSeverity), config, "reconciliationAlertSeverity",
+		"must be one of %s, got %s", strings.Join(constants.Severities, ", "), a.Reconciliation.AlertSeverity)
+
+	v.positive(config, "shutdownHttpTimeoutSeconds", a.Shutdown.HTTPTimeoutSeconds)
+	v.positive(config, "shutdownJobsTimeoutSeconds", a.Shutdown.JobsTimeoutSeconds)
+}
+
+func (d Database) validate(v *validation, c *Client) {
+	const config = constants.DatabaseConfig
+	d.Postgres.validate(v, config, constants.PostgresDBKey, c)
+	if !d.Replica.Configured() {
+		return
+	}
+	d.Replica.validate(v, config, constants.ReplicaDBKey, c)
+	v.positive(config, "replica-max-lag-seconds", d.MaxLagSeconds)
+	v.positive(config, "replica-health-check-seconds", d.HealthCheckSeconds)
+}
+
+func (p DBPool) validate(v *validation, config string, key string, c *Client) {
+	v.required(config, key+"."+constants.DBDriver, p.DriverName)
+	v.check(p.MaxOpenConnections > 0, config, key+"."+constants.DBMaxOpenConnections, "must be positive, got %d", p.MaxOpenConnections)
+	v.check(p.MaxIdleConnections >= 0 && p.MaxIdleConnections <= p.MaxOpenConnections, config, key+"."+constants.DBMaxIdleConnections,
+		"must be between 0 and %s, got %d", constants.DBMaxOpenConnections, p.MaxIdleConnections)
+	if p.URL == "" {
+		v.required(config, key+"."+constants.DBURL, p.URL)
+		return
+	}
+	for _, secret := range placeholders(p.URL) {
+		v.check(c.hasSecret(secret), "secrets", secret, "is missing, required by %s.%s.%s", config, key, constants.DBURL)
+	}
+	dsn, err := url.Parse(c.ReplaceWithSecret(p.URL))
+	v.check(err == nil && dsn.Scheme != "" && dsn.Host != "", config, key+"."+constants.DBURL, "must be a url with a scheme and a host")
+}
+
+func (h HTTPClient) validate(v *validation, name string, c *Client) {
+	const config = constants.HTTPClientConfig
+	v.check(slices.Contains(httpMethods, h.Method), config, name+"."+constants.MethodKey, "must be one of %s, got '%s'", strings.Join(httpMethods, ", "), h.Method)
+	target, err := url.Parse(c.ReplaceWithEnv(h.URL))
+	v.check(err == nil && (target.Scheme == "http" || target.Scheme == "https") && target.Host != "", config, name+"."+constants.UrlKey,
+		"must be an http or https url, got '%s'", h.URL)
+	v.check(h.TimeoutInMillis > 0, config, name+".timeoutinmillis", "must be positive, got %d", h.TimeoutInMillis)
+}
+
+// hasSecret tells whether the secret is set, a missing secret holds the placeholder of the store
+func (c *Client) hasSecret(key string) bool {
+	value := c.Secret(key)
+	return value != "" && value != DS
+}
+
+// placeholders returns the names of the ${NAME} placeholders of the value
+func placeholders(value string) []string {
+	var names []string
+	for {
+		start := strings.Index(value, "${")
+		if start < 0 {
+			return names
+		}
+		end := strings.Index(value[start:], "}")
+		if end < 0 {
+			return names
+		}
+		names = append(names, value[start+2:start+end])
+		value = value[start+end+1:]
+	}
+}
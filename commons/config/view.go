@@ -0,0 +1,115 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/angel-one/fd-core/constants"
+)
+
+const (
+	Redacted          = "[REDACTED]"
+	minRedactedLength = 6
+)
+
+// headers whose values are credentials, matched case-insensitively against the header names
+var sensitiveHeaders = []string{"authorization", "token", "secret", "key", "cookie"}
+
+// View returns the settings in effect keyed like the configs, with the secret values, url passwords and credential
+// headers redacted
+func View() map[string]any {
+	settings := Current()
+	httpClients := make(map[string]any, len(settings.HTTPClients))
+	for key, httpClient := range settings.HTTPClients {
+		httpClients[key] = view(reflect.ValueOf(httpClient))
+	}
+	return client.redact(map[string]any{
+		constants.ApplicationConfig: view(reflect.ValueOf(settings.Application)),
+		constants.DatabaseConfig:    view(reflect.ValueOf(settings.Database)),
+		constants.HTTPClientConfig:  httpClients,
+		constants.LoggerConfig:      view(reflect.ValueOf(settings.Logger)),
+	}).(map[string]any)
+}
+
+// view keys the fields of a struct by their config or mapstructure key, the untagged struct fields are flattened
+func view(value reflect.Value) any {
+	if value.Kind() != reflect.Struct {
+		return value.Interface()
+	}
+	values := make(map[string]any)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		key := field.Tag.Get("config")
+		if key == "" {
+			key = field.Tag.Get("mapstructure")
+		}
+		if key != "" {
+			values[key] = view(value.Field(i))
+			continue
+		}
+		if nested, ok := view(value.Field(i)).(map[string]any); ok {
+			for k, v := range nested {
+				values[k] = v
+			}
+		}
+	}
+	return values
+}
+
+func (c *Client) redact(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, item := range v {
+			redacted[key] = c.redact(item)
+		}
+		return redacted
+	case map[string]string:
+		redacted := make(map[string]string, len(v))
+		for key, item := range v {
+			if isSensitiveHeader(key) {
+				item = Redacted
+			}
+			redacted[key] = c.redact(item).(string)
+		}
+		return redacted
+	case []string:
+		redacted := make([]string, len(v))
+		for i, item := range v {
+			redacted[i] = c.redact(item).(string)
+		}
+		return redacted
+	case string:
+		return c.redactString(v)
+	default:
+		return v
+	}
+}
+
+// redactString hides the password of a url and the values of the secrets, a secret shorter than minRedactedLength
+// would mangle the view rather than hide anything
+func (c *Client) redactString(value string) string {
+	if parsed, err := url.Parse(value); err == nil && parsed.User != nil {
+		value = parsed.Redacted()
+	}
+	if c == nil {
+		return value
+	}
+	for _, secret := range c.Secrets {
+		if len(secret) >= minRedactedLength && secret != DS {
+			value = strings.ReplaceAll(value, secret, Redacted)
+		}
+	}
+	return value
+}
+
+func isSensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range sensitiveHeaders {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
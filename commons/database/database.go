@@ -14,72 +14,61 @@ import (
 
 var ConfigNotFound = fderr.New().Code("DB-01").Msg("configs not found from config file").Build()
 
-var postgresDB *sql.DB
+var router *Router
 
-var (
-	DBDefaultMaxOpenConnections             = 25
-	DBDefaultMaxIdleConnections             = 25
-	DBDefaultConnectionMaxLifetimeInSeconds = 90
-	DBDefaultConnectionMaxIdleTimeInSeconds = 30
-	DBDefaultConnecTimeout                  = 10
-)
+// GetDBPool returns the pool for the intent as of now, see Router.DB
+func GetDBPool(intent Intent) *sql.DB {
+	return router.DB(intent)
+}
 
-func GetDBPool(postgres bool) *sql.DB {
-	return postgresDB
+// GetRouter returns the router of the pools, the DAOs resolve their pool on every query
+func GetRouter() *Router {
+	return router
 }
 
-func Init(ctx context.Context, cfg *config.Client, key string) error {
-	var err error
-	if postgresDB, err = initDBPools(ctx, cfg, key, constants.PostgresDBKey); err != nil {
+// Init opens the primary pool and the replica pool when configured. An unreachable replica does not fail the startup,
+// the reads stay on the primary until it is healthy.
+func Init(ctx context.Context, cfg *config.Client, settings config.Database) error {
+	primary, err := initDBPool(ctx, cfg, settings.Postgres, constants.PostgresDBKey)
+	if err != nil {
+		return err
+	}
+	router = NewRouter(primary)
+	if !settings.Replica.Configured() {
+		return nil
+	}
+	replica, err := openDBPool(poolConfig(cfg, settings.Replica))
+	if err != nil {
+		log.Error(ctx).Err(err).Msgf("failed to initlaize %s db pool", constants.ReplicaDBKey)
 		return err
 	}
+	router.StartReplica(replica, time.Second*time.Duration(settings.MaxLagSeconds), time.Second*time.Duration(settings.HealthCheckSeconds))
+	log.Info(ctx).Msgf("successfully initialized db-pool for type %s", constants.ReplicaDBKey)
 	return nil
 }
 
-func initDBPools(ctx context.Context, cfg *config.Client, key string, poolType string) (*sql.DB, error) {
-	var err error
-	var dbPool *sql.DB
-	var config map[string]interface{}
+// CloseAll stops checking the replica and closes the pools
+func CloseAll() error {
+	return router.Close()
+}
 
-	if config, err = cfg.GetMap(key, poolType); err != nil {
-		log.Fatal(ctx).Err(err).Msgf("error during load of key: %s; pooType: %s configs", key, poolType)
-		return nil, err
-	}
-	if len(config) == 0 {
-		log.Fatal(ctx).Err(err).Msgf("missing config for key: %s; pooType: %s configs", key, poolType)
-		return nil, err
-	}
-	dbPool, err = initConnection(ctx, cfg, config)
+func initDBPool(ctx context.Context, cfg *config.Client, settings config.DBPool, poolType string) (*sql.DB, error) {
+	dbPool, err := InitDBPool(ctx, poolConfig(cfg, settings))
 	if err != nil {
-		log.Fatal(ctx).Err(err).Msgf("failed to initlaize %s db pool", poolType)
+		log.Error(ctx).Err(err).Msgf("failed to initlaize %s db pool", poolType)
 		return nil, err
 	}
 	log.Info(ctx).Msgf("successfully initialized db-pool for type %s", poolType)
 	return dbPool, nil
 }
 
-func initConnection(ctx context.Context, cfg *config.Client, configMap map[string]interface{}) (*sql.DB, error) {
-	var err error
-	var pool *sql.DB
-	driverName, _ := cfg.GetStringFromMap(configMap, constants.DBDriver, "")
-	url, err := cfg.GetStringWithSecretsFromMap(configMap, constants.DBURL, "")
-	if err != nil {
-		log.Fatal(ctx).Err(err).Msg("error getting database url")
+func poolConfig(cfg *config.Client, settings config.DBPool) dbConfig {
+	return dbConfig{
+		DriverName:            settings.DriverName,
+		URL:                   cfg.ReplaceWithSecret(settings.URL),
+		MaxOpenConnections:    settings.MaxOpenConnections,
+		MaxIdleConnections:    settings.MaxIdleConnections,
+		ConnectionMaxLifetime: time.Second * time.Duration(settings.ConnectionMaxLifetimeInSeconds),
+		ConnectionMaxIdleTime: time.Second * time.Duration(settings.ConnectionMaxIdleTimeInSeconds),
 	}
-
-	maxOpenConnections, _ := cfg.GetIntFromMap(configMap, constants.DBMaxOpenConnections, DBDefaultMaxOpenConnections)
-	maxIdleConnections, _ := cfg.GetIntFromMap(configMap, constants.DBMaxIdleConnections, DBDefaultMaxIdleConnections)
-	connectionMaxLifetime, _ := cfg.GetIntFromMap(configMap, constants.DBConnectionMaxLifetimeInSeconds, DBDefaultConnectionMaxLifetimeInSeconds)
-	connectionMaxIdleTime, _ := cfg.GetIntFromMap(configMap, constants.DBConnectionMaxIdleTimeInSeconds, DBDefaultConnectionMaxIdleTimeInSeconds)
-
-	pool, err = InitDBPool(ctx, dbConfig{
-		DriverName:            driverName,
-		URL:                   url,
-		MaxOpenConnections:    int(maxOpenConnections),
-		MaxIdleConnections:    int(maxIdleConnections),
-		ConnectionMaxLifetime: time.Second * time.Duration(connectionMaxLifetime),
-		ConnectionMaxIdleTime: time.Second * time.Duration(connectionMaxIdleTime),
-	})
-
-	return pool, err
 }
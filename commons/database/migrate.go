@@ -0,0 +1,260 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/goerr"
+)
+
+// the version table of goose, so a database migrated by the goose binary is picked up as is
+const (
+	createVersionTable = `CREATE TABLE IF NOT EXISTS goose_db_version (
+		id serial NOT NULL,
+		version_id bigint NOT NULL,
+		is_applied boolean NOT NULL,
+		tstamp timestamp NULL DEFAULT now(),
+		PRIMARY KEY (id)
+	)`
+	initVersionTable   = `INSERT INTO goose_db_version (version_id, is_applied) SELECT 0, true WHERE NOT EXISTS (SELECT 1 FROM goose_db_version)`
+	fetchVersions      = `SELECT version_id, is_applied, tstamp FROM goose_db_version ORDER BY id DESC`
+	insertVersion      = `INSERT INTO goose_db_version (version_id, is_applied) VALUES ($1, true)`
+	deleteVersion      = `DELETE FROM goose_db_version WHERE version_id = $1`
+	gooseAnnotation    = "-- +goose "
+	gooseUp            = "Up"
+	gooseDown          = "Down"
+	gooseNoTransaction = "NO TRANSACTION"
+)
+
+// Migration is a goose sql migration, versioned by the timestamp prefix of its file name
+type Migration struct {
+	Version       int64
+	Name          string
+	Up            string
+	Down          string
+	NoTransaction bool
+}
+
+// MigrationState is a migration and when it was applied, AppliedAt is nil while it is pending
+type MigrationState struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Migrator applies the migrations of a directory in version order. Like goose with -allow-missing, a migration older
+// than the applied ones is still applied.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewMigrator(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// LoadMigrations reads the .sql migrations of fsys sorted by version
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, goerr.New(err, "migrate: listing migrations failed")
+	}
+	migrations := make([]Migration, 0, len(names))
+	versions := make(map[int64]string, len(names))
+	for _, name := range names {
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, goerr.New(err, fmt.Sprintf("migrate: reading migration %s failed", name))
+		}
+		migration, err := parseMigration(name, string(content))
+		if err != nil {
+			return nil, err
+		}
+		if other, ok := versions[migration.Version]; ok {
+			return nil, goerr.New(nil, fmt.Sprintf("migrate: migrations %s and %s have the same version", other, name))
+		}
+		versions[migration.Version] = name
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// parseMigration splits a goose migration into its up and down sections. Each section is executed as one script, so
+// the statement annotations are not needed.
+func parseMigration(name string, content string) (Migration, error) {
+	version, err := strconv.ParseInt(strings.SplitN(path.Base(name), "_", 2)[0], 10, 64)
+	if err != nil || version <= 0 {
+		return Migration{}, goerr.New(err, fmt.Sprintf("migrate: migration %s is not prefixed by its version", name))
+	}
+	migration := Migration{Version: version, Name: path.Base(name)}
+
+	var up, down strings.Builder
+	var section *strings.Builder
+	for _, line := range strings.Split(content, "\n") {
+		annotation, ok := strings.CutPrefix(strings.TrimSpace(line), gooseAnnotation)
+		if !ok {
+			if section != nil {
+				section.WriteString(line + "\n")
+			}
+			continue
+		}
+		switch strings.TrimSpace(annotation) {
+		case gooseUp:
+			section = &up
+		case gooseDown:
+			section = &down
+		case gooseNoTransaction:
+			migration.NoTransaction = true
+		}
+	}
+	migration.Up, migration.Down = strings.TrimSpace(up.String()), strings.TrimSpace(down.String())
+	if migration.Up == "" {
+		return Migration{}, goerr.New(nil, fmt.Sprintf("migrate: migration %s has no up section", name))
+	}
+	return migration, nil
+}
+
+// Status returns every migration with when it was applied
+func (m *Migrator) Status(ctx context.Context) ([]MigrationState, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]MigrationState, len(m.migrations))
+	for i, migration := range m.migrations {
+		states[i] = MigrationState{Migration: migration, AppliedAt: applied[migration.Version]}
+	}
+	return states, nil
+}
+
+// Up applies the pending migrations in version order, stops at the first failing one
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for _, migration := range pendingMigrations(m.migrations, applied) {
+		if err := m.apply(ctx, migration, migration.Up, insertVersion); err != nil {
+			return done, err
+		}
+		log.Info(ctx).Msgf("applied migration %s", migration.Name)
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// Down rolls back the applied migration of the highest version, nil when none is applied
+func (m *Migrator) Down(ctx context.Context) (*Migration, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if applied[migration.Version] == nil {
+			continue
+		}
+		if err := m.apply(ctx, migration, migration.Down, deleteVersion); err != nil {
+			return nil, err
+		}
+		log.Info(ctx).Msgf("rolled back migration %s", migration.Name)
+		return &migration, nil
+	}
+	return nil, nil
+}
+
+// pendingMigrations are the migrations not applied, in version order
+func pendingMigrations(migrations []Migration, applied map[int64]*time.Time) []Migration {
+	var pending []Migration
+	for _, migration := range migrations {
+		if applied[migration.Version] == nil {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+// appliedVersions returns when each applied version was applied, the latest row of a version tells whether it is applied
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int64]*time.Time, error) {
+	if _, err := m.db.ExecContext(ctx, createVersionTable); err != nil {
+		return nil, goerr.New(err, "migrate: creating goose_db_version failed")
+	}
+	if _, err := m.db.ExecContext(ctx, initVersionTable); err != nil {
+		return nil, goerr.New(err, "migrate: initialising goose_db_version failed")
+	}
+	rows, err := m.db.QueryContext(ctx, fetchVersions)
+	if err != nil {
+		return nil, goerr.New(err, "migrate: fetching applied versions failed")
+	}
+	defer rows.Close()
+
+	seen := make(map[int64]bool)
+	applied := make(map[int64]*time.Time)
+	for rows.Next() {
+		var version int64
+		var isApplied bool
+		var appliedAt sql.NullTime
+		if err := rows.Scan(&version, &isApplied, &appliedAt); err != nil {
+			return nil, goerr.New(err, "migrate: scanning applied version failed")
+		}
+		if seen[version] {
+			continue
+		}
+		seen[version] = true
+		if isApplied {
+			at := appliedAt.Time
+			applied[version] = &at
+		}
+	}
+	return applied, rows.Err()
+}
+
+// apply runs the script of the migration and records it with the version statement, in one transaction unless the
+// migration opts out of it
+func (m *Migrator) apply(ctx context.Context, migration Migration, script string, versionStatement string) error {
+	if migration.NoTransaction {
+		return runMigration(ctx, m.db, migration, script, versionStatement)
+	}
+
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return goerr.New(err, "migrate: starting transaction failed")
+	}
+	defer tx.Rollback()
+	if err := runMigration(ctx, tx, migration, script, versionStatement); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return goerr.New(err, fmt.Sprintf("migrate: committing migration %s failed", migration.Name))
+	}
+	return nil
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+func runMigration(ctx context.Context, db execer, migration Migration, script string, versionStatement string) error {
+	if script != "" {
+		if _, err := db.ExecContext(ctx, script); err != nil {
+			return goerr.New(err, fmt.Sprintf("migrate: running migration %s failed", migration.Name))
+		}
+	}
+	if _, err := db.ExecContext(ctx, versionStatement, migration.Version); err != nil {
+		return goerr.New(err, fmt.Sprintf("migrate: recording migration %s failed", migration.Name))
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/angel-one/fd-core/scripts/dbschema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMigration(t *testing.T) {
+	migration, err := parseMigration("20240606111410_faqs.sql", `-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE faqs (tag varchar NOT NULL);
+CREATE INDEX faqs_tag ON faqs (tag);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE faqs;
+-- +goose StatementEnd`)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20240606111410), migration.Version)
+	assert.Equal(t, "CREATE TABLE faqs (tag varchar NOT NULL);\nCREATE INDEX faqs_tag ON faqs (tag);", migration.Up)
+	assert.Equal(t, "DROP TABLE faqs;", migration.Down)
+	assert.False(t, migration.NoTransaction)
+
+	migration, err = parseMigration("20240606111411_index.sql", "-- +goose NO TRANSACTION\n-- +goose Up\nCREATE INDEX CONCURRENTLY faqs_active ON faqs (is_active);\n")
+	assert.NoError(t, err)
+	assert.True(t, migration.NoTransaction)
+	assert.Empty(t, migration.Down)
+
+	_, err = parseMigration("faqs.sql", "-- +goose Up\nSELECT 1;")
+	assert.Error(t, err)
+	_, err = parseMigration("20240606111412_empty.sql", "-- +goose Down\nSELECT 1;")
+	assert.Error(t, err)
+}
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := LoadMigrations(fstest.MapFS{
+		"20240607112643_plans.sql":  {Data: []byte("-- +goose Up\nCREATE TABLE plans ();")},
+		"20240205161654_events.sql": {Data: []byte("-- +goose Up\nCREATE TABLE events ();")},
+		"README.md":                 {Data: []byte("not a migration")},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, migrations, 2)
+	assert.Equal(t, "20240205161654_events.sql", migrations[0].Name)
+
+	_, err = LoadMigrations(fstest.MapFS{
+		"20240607112643_plans.sql": {Data: []byte("-- +goose Up\nCREATE TABLE plans ();")},
+		"20240607112643_banks.sql": {Data: []byte("-- +goose Up\nCREATE TABLE banks ();")},
+	})
+	assert.Error(t, err)
+
+	// every migration of the schema parses
+	migrations, err = LoadMigrations(dbschema.Migrations)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+}
+
+func TestPendingMigrations(t *testing.T) {
+	appliedAt := time.Now()
+	migrations := []Migration{{Version: 1}, {Version: 2}, {Version: 3}}
+
+	// a migration older than the applied ones is still pending
+	pending := pendingMigrations(migrations, map[int64]*time.Time{0: &appliedAt, 1: &appliedAt, 3: &appliedAt})
+	assert.Equal(t, []Migration{{Version: 2}}, pending)
+	assert.Len(t, pendingMigrations(migrations, map[int64]*time.Time{}), 3)
+}
@@ -19,10 +19,22 @@ type dbConfig struct {
 }
 
 func InitDBPool(ctx context.Context, config dbConfig) (*sql.DB, error) {
+	pool, err := openDBPool(config)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+	Ping(ctx, pool)
+
+	return pool, nil
+}
+
+// openDBPool opens the pool without connecting, the connections are made by the first queries
+func openDBPool(config dbConfig) (*sql.DB, error) {
 	// open the database
-	var err error
-	var pool *sql.DB
-	pool, err = sql.Open(
+	pool, err := sql.Open(
 		config.DriverName,
 		config.URL,
 	)
@@ -35,11 +47,6 @@ func InitDBPool(ctx context.Context, config dbConfig) (*sql.DB, error) {
 	pool.SetMaxIdleConns(config.MaxIdleConnections)
 	pool.SetConnMaxIdleTime(config.ConnectionMaxIdleTime)
 	pool.SetConnMaxLifetime(config.ConnectionMaxLifetime)
-
-	ctx, stop := context.WithCancel(ctx)
-	defer stop()
-	Ping(ctx, pool)
-
 	return pool, nil
 }
 
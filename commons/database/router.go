@@ -0,0 +1,206 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	fdcontext "github.com/angel-one/fd-core/commons/context"
+	"github.com/angel-one/fd-core/commons/log"
+)
+
+// Intent tells the router what a query needs from the pool it runs on
+type Intent int
+
+const (
+	// Write runs on the primary, so do the reads that must see the latest rows of every instance, like the claims
+	// and the leases
+	Write Intent = iota
+	// Read may run on the replica and miss the rows written in the last max lag
+	Read
+	// ReadAfterWrite must see the writes made through the router, it runs on the replica once the replica caught up
+	// with the last of them
+	ReadAfterWrite
+)
+
+// whether the replica streams from the primary and its lag. A replica whose wal receiver is down replayed all it
+// received and would report no lag, so it is not streaming. The lag is null when it has wal to replay but never
+// replayed a transaction.
+const replicaLagQuery = `SELECT COALESCE((SELECT status = 'streaming' FROM pg_stat_wal_receiver), false) AND pg_last_wal_receive_lsn() IS NOT NULL,
+	CASE WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) END`
+
+var (
+	errReplicaNotStreaming = errors.New("replica is not streaming from the primary")
+	errReplicaLagUnknown   = errors.New("replica lag is unknown")
+)
+
+const replicaHealthCheckTimeout = 5 * time.Second
+
+type replicaState struct {
+	healthy   bool
+	lag       time.Duration
+	checkedAt time.Time
+}
+
+// caughtUp tells whether the replica had replayed a write made at the given time when it was checked
+func (s *replicaState) caughtUp(write time.Time) bool {
+	return s.checkedAt.Add(-s.lag).After(write)
+}
+
+// Router hands out the primary or the replica pool by the intent of the query. The replica is checked periodically,
+// the reads fall back to the primary while it is unreachable or lags more than the max lag.
+type Router struct {
+	primary   *sql.DB
+	replica   *sql.DB
+	maxLag    time.Duration
+	state     atomic.Pointer[replicaState]
+	lastWrite atomic.Int64
+	writing   atomic.Int64
+	stop      chan struct{}
+	stopped   chan struct{}
+}
+
+// NewRouter routes every query to the primary until a replica is started
+func NewRouter(primary *sql.DB) *Router {
+	return &Router{primary: primary}
+}
+
+// DB returns the pool for the intent. The pool is meant for the query at hand, holding on to it skips the fallback.
+// A write through it is stamped when it is handed out, the writes of ExecContext and BeginTx are stamped once done.
+func (r *Router) DB(intent Intent) *sql.DB {
+	if intent == Write {
+		r.wrote()
+		return r.primary
+	}
+	if r.replica == nil {
+		return r.primary
+	}
+	state := r.state.Load()
+	if state == nil || !state.healthy {
+		return r.primary
+	}
+	if intent == ReadAfterWrite && (r.writing.Load() > 0 || !state.caughtUp(time.Unix(0, r.lastWrite.Load()))) {
+		return r.primary
+	}
+	return r.replica
+}
+
+// ExecContext runs a write on the primary, the reads after it stay on the primary while it runs and until the
+// replica caught up with it
+func (r *Router) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	r.writing.Add(1)
+	defer r.done()
+	return r.primary.ExecContext(ctx, query, args...)
+}
+
+// BeginTx begins a transaction on the primary, the reads after it stay on the primary until it ends and the replica
+// caught up with it
+func (r *Router) BeginTx(ctx context.Context, opts *sql.TxOptions) (*Tx, error) {
+	r.writing.Add(1)
+	tx, err := r.primary.BeginTx(ctx, opts)
+	if err != nil {
+		r.done()
+		return nil, err
+	}
+	return &Tx{Tx: tx, router: r}, nil
+}
+
+func (r *Router) wrote() {
+	r.lastWrite.Store(time.Now().UnixNano())
+}
+
+func (r *Router) done() {
+	r.wrote()
+	r.writing.Add(-1)
+}
+
+// Tx is a transaction of the router, it is stamped as a write once committed or rolled back
+type Tx struct {
+	*sql.Tx
+	router *Router
+	ended  bool
+}
+
+func (t *Tx) Commit() error {
+	defer t.end()
+	return t.Tx.Commit()
+}
+
+// Rollback is a no-op once the transaction ended, so it can be deferred
+func (t *Tx) Rollback() error {
+	defer t.end()
+	return t.Tx.Rollback()
+}
+
+func (t *Tx) end() {
+	if !t.ended {
+		t.ended = true
+		t.router.done()
+	}
+}
+
+// StartReplica checks the replica right away and then every interval, it is healthy when reachable and its lag is at
+// most maxLag
+func (r *Router) StartReplica(replica *sql.DB, maxLag time.Duration, interval time.Duration) {
+	r.replica, r.maxLag = replica, maxLag
+	r.stop, r.stopped = make(chan struct{}), make(chan struct{})
+	ctx := fdcontext.Background("replicaHealthCheck")
+	r.checkReplica(ctx)
+	go func() {
+		defer close(r.stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.checkReplica(ctx)
+			}
+		}
+	}()
+}
+
+func (r *Router) checkReplica(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, replicaHealthCheckTimeout)
+	defer cancel()
+	var streaming bool
+	var seconds sql.NullFloat64
+	err := r.replica.QueryRowContext(ctx, replicaLagQuery).Scan(&streaming, &seconds)
+	if err == nil && !streaming {
+		err = errReplicaNotStreaming
+	} else if err == nil && !seconds.Valid {
+		err = errReplicaLagUnknown
+	}
+	r.observe(ctx, err, time.Duration(seconds.Float64*float64(time.Second)), time.Now())
+}
+
+// observe records the outcome of a check, the changes of health are logged
+func (r *Router) observe(ctx context.Context, err error, lag time.Duration, checkedAt time.Time) {
+	state := &replicaState{healthy: err == nil && lag <= r.maxLag, lag: lag, checkedAt: checkedAt}
+	previous := r.state.Swap(state)
+	wasHealthy := previous == nil || previous.healthy
+	switch {
+	case err != nil && wasHealthy:
+		log.Warn(ctx).Err(err).Msg("replica is unhealthy, reads fall back to the primary")
+	case !state.healthy && wasHealthy:
+		log.Warn(ctx).Msgf("replica lags %s behind, reads fall back to the primary", lag)
+	case state.healthy && (previous == nil || !previous.healthy):
+		log.Info(ctx).Msg("replica is healthy, reads are routed to it")
+	}
+}
+
+// Close stops checking the replica and closes the pools
+func (r *Router) Close() error {
+	var errs []error
+	if r.replica != nil {
+		close(r.stop)
+		<-r.stopped
+		errs = append(errs, Close(r.replica))
+	}
+	errs = append(errs, Close(r.primary))
+	return errors.Join(errs...)
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testPool(t *testing.T, host string) *sql.DB {
+	pool, err := openDBPool(dbConfig{DriverName: "postgres", URL: "postgres://fd@" + host + "/fd"})
+	assert.NoError(t, err)
+	t.Cleanup(func() { pool.Close() })
+	return pool
+}
+
+func TestRouterWithoutReplica(t *testing.T) {
+	primary := testPool(t, "primary")
+	router := NewRouter(primary)
+	for _, intent := range []Intent{Write, Read, ReadAfterWrite} {
+		assert.Same(t, primary, router.DB(intent))
+	}
+}
+
+func TestRouterFallsBackToPrimary(t *testing.T) {
+	ctx := context.Background()
+	primary, replica := testPool(t, "primary"), testPool(t, "replica")
+	router := &Router{primary: primary, replica: replica, maxLag: 5 * time.Second}
+	// not checked yet
+	assert.Same(t, primary, router.DB(Read))
+
+	router.observe(ctx, nil, time.Second, time.Now())
+	assert.Same(t, replica, router.DB(Read))
+	assert.Same(t, replica, router.DB(ReadAfterWrite))
+	assert.Same(t, primary, router.DB(Write))
+
+	router.observe(ctx, nil, 10*time.Second, time.Now())
+	assert.Same(t, primary, router.DB(Read))
+
+	router.observe(ctx, errors.New("connection refused"), 0, time.Now())
+	assert.Same(t, primary, router.DB(Read))
+
+	router.observe(ctx, nil, 0, time.Now())
+	assert.Same(t, replica, router.DB(Read))
+}
+
+func TestRouterKeepsReadsAfterWritesOnPrimary(t *testing.T) {
+	ctx := context.Background()
+	primary, replica := testPool(t, "primary"), testPool(t, "replica")
+	router := &Router{primary: primary, replica: replica, maxLag: 5 * time.Second}
+
+	router.DB(Write)
+	written := time.Unix(0, router.lastWrite.Load())
+	// checked after the write but lagging behind it
+	router.observe(ctx, nil, 2*time.Second, written.Add(time.Second))
+	assert.Same(t, primary, router.DB(ReadAfterWrite))
+	assert.Same(t, replica, router.DB(Read))
+
+	router.observe(ctx, nil, 2*time.Second, written.Add(3*time.Second))
+	assert.Same(t, replica, router.DB(ReadAfterWrite))
+}
+
+func TestRouterKeepsReadsOnPrimaryWhileWriting(t *testing.T) {
+	ctx := context.Background()
+	primary, replica := testPool(t, "primary"), testPool(t, "replica")
+	router := &Router{primary: primary, replica: replica, maxLag: 5 * time.Second}
+	router.observe(ctx, nil, 0, time.Now())
+	assert.Same(t, replica, router.DB(ReadAfterWrite))
+
+	// a transaction begun and still open
+	router.writing.Add(1)
+	tx := &Tx{router: router}
+	assert.Same(t, primary, router.DB(ReadAfterWrite))
+	assert.Same(t, replica, router.DB(Read))
+
+	tx.end()
+	tx.end()
+	assert.Equal(t, int64(0), router.writing.Load())
+	// ended after the last check
+	assert.Same(t, primary, router.DB(ReadAfterWrite))
+	router.observe(ctx, nil, 0, time.Now().Add(time.Second))
+	assert.Same(t, replica, router.DB(ReadAfterWrite))
+}
+
+func TestRouterFallsBackOnUnhealthyReplica(t *testing.T) {
+	ctx := context.Background()
+	primary, replica := testPool(t, "primary"), testPool(t, "replica")
+	router := &Router{primary: primary, replica: replica, maxLag: 5 * time.Second}
+	for _, err := range []error{errReplicaNotStreaming, errReplicaLagUnknown} {
+		router.observe(ctx, nil, 0, time.Now())
+		assert.Same(t, replica, router.DB(Read))
+		router.observe(ctx, err, 0, time.Now())
+		assert.Same(t, primary, router.DB(Read))
+	}
+}
@@ -14,9 +14,20 @@ var (
 	port           = flag.Int(constants.PortKey, constants.PortDefaultValue, constants.PortUsage)
 	baseConfigPath = flag.String(constants.BaseConfigPathKey, constants.BaseConfigPathDefaultValue,
 		constants.BaseConfigPathUsage)
+
+	// replay command flags
+	replayClient = flag.String(constants.ReplayClientKey, "", constants.ReplayClientUsage)
+	replayFrom   = flag.String(constants.ReplayFromKey, "", constants.ReplayFromUsage)
+	replayTo     = flag.String(constants.ReplayToKey, "", constants.ReplayToUsage)
+	replayDryRun = flag.Bool(constants.ReplayDryRunKey, false, constants.ReplayDryRunUsage)
+
+	// run-job command flags
+	runJobInstant = flag.Bool(constants.RunJobInstantKey, false, constants.RunJobInstantUsage)
+	runJobClients = flag.StringSlice(constants.RunJobClientsKey, nil, constants.RunJobClientsUsage)
 )
 
-func init() {
+// Parse reads the command line, the flags hold their defaults until it is called
+func Parse() {
 	flag.Parse()
 }
 
@@ -44,6 +55,52 @@ func Mode() string {
 	return *mode
 }
 
+// Command is the sub-command given as the first argument, serve when none is given
+func Command() string {
+	if flag.Arg(0) == "" {
+		return constants.ServeCommand
+	}
+	return flag.Arg(0)
+}
+
+// CommandArgs are the arguments following the sub-command
+func CommandArgs() []string {
+	if flag.NArg() == 0 {
+		return nil
+	}
+	return flag.Args()[1:]
+}
+
+// ReplayClient is the client code to replay webhook events for
+func ReplayClient() string {
+	return *replayClient
+}
+
+// ReplayFrom is the inclusive start date of the events to replay
+func ReplayFrom() string {
+	return *replayFrom
+}
+
+// ReplayTo is the exclusive end date of the events to replay
+func ReplayTo() string {
+	return *replayTo
+}
+
+// ReplayDryRun reports only the diff without writing the derived state
+func ReplayDryRun() bool {
+	return *replayDryRun
+}
+
+// RunJobInstant runs the job as an instant refresh
+func RunJobInstant() bool {
+	return *runJobInstant
+}
+
+// RunJobClients are the client codes the job processes, the clients of the job when empty
+func RunJobClients() []string {
+	return *runJobClients
+}
+
 // AWSRegion is the region where the application is running
 func AWSRegion() string {
 	region := os.Getenv(constants.AWSRegionKey)
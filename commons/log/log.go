@@ -34,11 +34,25 @@ func (l Level) zeroLogLevel() zerolog.Level {
 	}
 }
 
+// Valid tells whether the level is known, the logger logs at debug level otherwise
+func (l Level) Valid() bool {
+	switch l {
+	case constants.TraceLevel, constants.DebugLevel, constants.InfoLevel, constants.WarnLevel, constants.ErrorLevel, constants.FatalLevel, constants.PanicLevel:
+		return true
+	}
+	return false
+}
+
 // InitLogger is used to initialize logger
 func InitLogger(level Level) {
 	zerolog.ErrorStackMarshaler = getErrorStackMarshaller()
 	zerolog.SetGlobalLevel(level.zeroLogLevel())
-	log.Logger = zerolog.New(os.Stdout).Level(level.zeroLogLevel()).With().Caller().Logger()
+	log.Logger = zerolog.New(os.Stdout).With().Caller().Logger()
+}
+
+// SetLevel changes the level of the logger, safe to call while logging
+func SetLevel(level Level) {
+	zerolog.SetGlobalLevel(level.zeroLogLevel())
 }
 
 func Trace(ctx context.Context) *zerolog.Event {
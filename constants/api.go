@@ -26,6 +26,8 @@ const (
 	HeaderOS                     = "X-OperatingSystem"
 	HeaderSourceID               = "X-Source-ID"
 	HeaderAcceptLanguage         = "Accept-Language"
+	HeaderWebhookSignature       = "X-Webhook-Signature"
+	HeaderWebhookTimestamp       = "X-Webhook-Timestamp"
 )
 
 // URL path constants
@@ -43,8 +45,6 @@ const (
 const (
 	AllowedMethodsForCors         = u.AllMethodsHeaderValue + ",DELETE"
 	AllowedOriginsForCorsDefault  = "*"
-	AllowedOrginsForCorsConfigKey = "orginsAllowedForCors"
-	WhitelistedHostsHeader        = "whitelistedHostHeader"
 	WhitelistedHostsHeaderDefault = "angelone.in"
 )
 
@@ -53,6 +53,8 @@ const (
 	AuthJWTClaimsUserData       = "userData"
 	AuthJWTClaimsUserDataUserID = "user_id"
 	AuthGuestUserID             = "guest"
+	AdminRoleKey                = "adminRole"
+	AuthUserIDKey               = "authUserID"
 )
 
 const (
@@ -75,14 +77,45 @@ const (
 	Jobs           = "/jobs"
 	Update         = "/update"
 	PendingJourney = "/pendingJourney"
+	Admin          = "/admin"
+	Webhooks       = "/webhooks"
+	Rederive       = "/rederive"
+	Replay         = "/replay"
+	DeadLetters    = "/deadLetters"
+	Requeue        = "/requeue"
+	Journeys       = "/journeys"
+	Timeline       = "/timeline"
+	Clients        = "/clients"
+	State          = "/state"
+	Reconciliation = "/reconciliation"
+	Summary        = "/summary"
+	Leases         = "/leases"
+	Runs           = "/runs"
+	Config         = "/config"
+	Cancel         = "/cancel"
+	Invalid        = "/invalid"
+	Audit          = "/audit"
 )
 
 const (
-	Provider      = "provider"
-	FSI           = "fsi"
-	StatusSuccess = "success"
-	Tag           = "tag"
-	Refresher     = "refresher"
+	Provider        = "provider"
+	FSI             = "fsi"
+	StatusSuccess   = "success"
+	Tag             = "tag"
+	Refresher       = "refresher"
+	JourneyID       = "id"
+	DeadLetterID    = "id"
+	Limit           = "limit"
+	IncludeRequeued = "includeRequeued"
+	ClientCode      = "clientCode"
+	Events          = "events"
+	Job             = "job"
+	Status          = "status"
+	Trigger         = "trigger"
+	From            = "from"
+	To              = "to"
+	JobName         = "name"
+	RunID           = "id"
 )
 
 var (
@@ -90,8 +123,27 @@ var (
 	KnownProviders  = []string{UpSwingProvider}
 )
 
+// webhook auth strategies
+const (
+	WebhookAuthJWT  = "jwt"
+	WebhookAuthHMAC = "hmac"
+)
+
+// webhook payload schema versions
+const (
+	WebhookPayloadVersionKey = "version"
+	WebhookPayloadV1         = "v1"
+)
+
 const (
 	ErrorCode         = "errorCode"
 	ErrClientNotFound = "INTERNAL_CUSTOMER_DETAILS_NOT_FOUND_FOR_PCI"
 	ErrPciNotFound    = "[PCI:%s] not found"
 )
+
+// client registration status at the provider
+const (
+	RegistrationRegistered    = "registered"
+	RegistrationNotRegistered = "not_registered"
+	RegistrationUnknown       = "unknown"
+)
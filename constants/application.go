@@ -39,13 +39,34 @@ const (
 	ModeKey                    = "mode"
 	ModeUsage                  = "run mode of the application, can be test or release"
 	ModeDefaultValue           = "test"
+	ReplayCommand              = "replay"
+	ReplayClientKey            = "client"
+	ReplayClientUsage          = "replay: client code to replay, all clients when not set"
+	ReplayFromKey              = "from"
+	ReplayFromUsage            = "replay: pick clients having events on or after this date (2006-01-02 or RFC3339)"
+	ReplayToKey                = "to"
+	ReplayToUsage              = "replay: pick clients having events before this date (2006-01-02 or RFC3339)"
+	ReplayDryRunKey            = "dry-run"
+	ReplayDryRunUsage          = "replay: only print the diff of the derived state, nothing is written"
+	ServeCommand               = "serve"
+	MigrateCommand             = "migrate"
+	MigrateUp                  = "up"
+	MigrateDown                = "down"
+	MigrateStatus              = "status"
+	RunJobCommand              = "run-job"
+	RunJobInstantKey           = "instant"
+	RunJobInstantUsage         = "run-job: refresh only the clients flagged for an instant refresh"
+	RunJobClientsKey           = "clients"
+	RunJobClientsUsage         = "run-job: comma separated client codes to process instead of the clients of the job"
+	SeedCommand                = "seed"
+	CheckConfigCommand         = "check-config"
 )
 
 // Error Messages
 const (
-	ErrAuthKeyEnvNotSet     = "environment key JWT_SYMMETRIC_KEY not set"
 	ErrNoUserDetailsFound   = "user not found, please contact service"
 	WhitelistedHostIsNotSet = "Whitelisted HOSTS config is missing"
+	AllowedOriginsIsNotSet  = "Allowed origins for CORS config is missing"
 )
 
 const (
@@ -101,14 +122,45 @@ const (
 	UpPCIField = "{pci}"
 
 	ProfileServerConfig = "profileServiceConfig"
+
+	AlertHookConfig = "alertHook"
 )
 
 const (
-	PortfolioUpdateBatchSize = "portfolioUpdateBatchSize"
-	PortfolioProvider        = "portfolioProvider"
+	ResumeLinkJourneyIDField = "{journeyId}"
+	ResumeLinkStepField      = "{step}"
+	ResumeLinkFSIField       = "{fsi}"
 )
 
+// job run triggers and statuses
 const (
-	PendingJourneyUpdateBatchSize = "pendingJourneyUpdateBatchSize"
-	PendingJourneyProvider        = "pendingJourneyProvider"
+	JobTriggerCron = "cron"
+	JobTriggerAPI  = "api"
+	JobTriggerCLI  = "cli"
+
+	JobRunRunning   = "running"
+	JobRunSucceeded = "succeeded"
+	JobRunFailed    = "failed"
+	JobRunCancelled = "cancelled"
+
+	JobRunModeFull        = "full"
+	JobRunModeResume      = "resume"
+	JobRunModeRetryFailed = "retryFailed"
 )
+
+// invalid client revalidation
+const (
+	InvalidClientScopePortfolio      = "portfolio"
+	InvalidClientScopePendingJourney = "pendingJourney"
+)
+
+var InvalidClientScopes = []string{InvalidClientScopePortfolio, InvalidClientScopePendingJourney}
+
+// discrepancy severities, in increasing order
+const (
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+)
+
+var Severities = []string{SeverityLow, SeverityMedium, SeverityHigh}
@@ -2,6 +2,7 @@ package constants
 
 const (
 	PostgresDBKey = "postgres-db"
+	ReplicaDBKey  = "replica-db"
 	MSSQLDBKey    = "mssql-db"
 
 	DBDriver                         = "drivername"
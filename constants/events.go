@@ -0,0 +1,64 @@
+package constants
+
+// webhook event types sent by the providers
+const (
+	EventTDBooked                   = "TD_BOOKED"
+	EventPrematureWithdrawalSuccess = "PREMATURE_WITHDRAWAL_SUCCESS"
+	EventWithdrawalBankSuccess      = "WITHDRAWL_BANK_SUCCESS"
+	EventPaymentFailure             = "PAYMENT_FAILURE"
+	EventAadhaarFailed              = "AADHAAR_FAILED"
+	EventVKYCInitiated              = "VKYC_INITIATED"
+	EventVKYCFailure                = "VKYC_FAILURE"
+	EventVKYCRequired               = "VKYC_REQUIRED"
+	EventVKYCRetryRequired          = "VKYC_RETRY_REQUIRED"
+	EventDigilockerFailed           = "DIGILOCKER_FAILED"
+	EventPANFailure                 = "PAN_FAILURE"
+	EventTDFailed                   = "TD_FAILED"
+)
+
+var (
+	// mirrors the event types of the insert_or_update_portfolio_from_webhook_events trigger
+	PortfolioEventTypes = []string{EventTDBooked, EventPrematureWithdrawalSuccess, EventWithdrawalBankSuccess}
+
+	// mirrors the event types of the insert_pending_journey trigger
+	PendingJourneyEventTypes = []string{EventPaymentFailure, EventAadhaarFailed, EventVKYCInitiated, EventVKYCFailure, EventVKYCRequired, EventVKYCRetryRequired, EventDigilockerFailed, EventPANFailure}
+)
+
+// steps a pending journey can be blocked on, resolved from the latest failure event
+const (
+	BlockingStepPayment    = "payment"
+	BlockingStepAadhaar    = "aadhaar"
+	BlockingStepDigilocker = "digilocker"
+	BlockingStepPAN        = "pan"
+	BlockingStepVKYC       = "vkyc"
+	BlockingStepVKYCRetry  = "vkyc_retry"
+	BlockingStepVKYCReview = "vkyc_in_progress"
+)
+
+var PendingJourneyBlockingSteps = map[string]string{
+	EventPaymentFailure:    BlockingStepPayment,
+	EventAadhaarFailed:     BlockingStepAadhaar,
+	EventDigilockerFailed:  BlockingStepDigilocker,
+	EventPANFailure:        BlockingStepPAN,
+	EventVKYCRequired:      BlockingStepVKYC,
+	EventVKYCFailure:       BlockingStepVKYCRetry,
+	EventVKYCRetryRequired: BlockingStepVKYCRetry,
+	EventVKYCInitiated:     BlockingStepVKYCReview,
+}
+
+// per event result of a webhook delivery
+const (
+	WebhookEventAccepted  = "accepted"
+	WebhookEventDuplicate = "duplicate"
+	WebhookEventRejected  = "rejected"
+)
+
+// booking journey states
+const (
+	JourneyStateInitiated = "initiated"
+	JourneyStateKYC       = "kyc"
+	JourneyStatePayment   = "payment"
+	JourneyStateBooked    = "booked"
+	JourneyStateFailed    = "failed"
+	JourneyStateWithdrawn = "withdrawn"
+)
@@ -17,4 +17,7 @@ const (
 	UpswingGrantType    = "upswingGrantType"
 	UpswingClientSecret = "upswingClientSecret"
 	UpswingScope        = "upswingScope"
+
+	// webhook signing secret of a provider is <provider>WebhookSecret, eg. upswingWebhookSecret
+	WebhookSecretSuffix = "WebhookSecret"
 )
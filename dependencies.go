@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/angel-one/fd-core/api/events"
+	"github.com/angel-one/fd-core/api/routes"
+	v1 "github.com/angel-one/fd-core/api/v1"
+	"github.com/angel-one/fd-core/business/jobs"
+	"github.com/angel-one/fd-core/business/repository/dao"
+	"github.com/angel-one/fd-core/business/service"
+	servicev1 "github.com/angel-one/fd-core/business/service/v1"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/fd-core/commons/httpclient"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/fd-core/external"
+)
+
+// dependencies are the clients, services and controllers of the application, every one of them is handed what it
+// uses through its constructor
+type dependencies struct {
+	jobs          *jobs.Jobs
+	controllers   routes.Controllers
+	inboxService  service.WebhookInboxService
+	refreshes     service.ClientRefreshService
+	replayService service.ReplayService
+}
+
+// deps are built by the dependencies hook, the later hooks and the commands take theirs from it
+var deps *dependencies
+
+// newDependencies builds the clients from the secrets and the http client, the DAOs on the database router and the
+// services and controllers on top of them
+func newDependencies(ctx context.Context, cfg *config.Client, db *database.Router, httpClient httpclient.Client) (*dependencies, error) {
+	profileToken := cfg.Secret(constants.ProfileServiceToken)
+	if profileToken == "" {
+		return nil, errors.New("profile service token is missing from configs")
+	}
+	upswing := external.NewUpSwing(ctx, cfg.Secrets, httpClient, external.NewProfileService(httpClient, profileToken))
+
+	// daos
+	portfolioDAO := dao.NewPortfolioDAO(db)
+	pendingJourneyDAO := dao.NewPendingJourneyDAO(db)
+	reconciliationDAO := dao.NewReconciliationDAO(db)
+	jobRunDAO := dao.NewJobRunDAO(db)
+	jobLeaseDAO := dao.NewJobLeaseDAO(db)
+	jobConfigDAO := dao.NewJobConfigDAO(db)
+	invalidClientDAO := dao.NewInvalidClientDAO(db)
+	journeyDAO := dao.NewJourneyDAO(db)
+	inboxDAO := dao.NewWebhookInboxDAO(db)
+	plansDAO := dao.NewPlansDAO(db)
+
+	// jobs
+	cronJobs := jobs.NewJobs(jobs.Dependencies{UpSwing: upswing, AlertHook: external.DefaultAlertHook(httpClient), PortfolioDAO: portfolioDAO,
+		PendingJourneyDAO: pendingJourneyDAO, ReconciliationDAO: reconciliationDAO, JobRunDAO: jobRunDAO, JobLeaseDAO: jobLeaseDAO,
+		JobConfigDAO: jobConfigDAO, InvalidClientDAO: invalidClientDAO, ArchivalDAO: dao.NewArchivalDAO(db)})
+
+	// services
+	portfolioService := servicev1.NewPortfolioService(portfolioDAO)
+	refreshes := service.NewClientRefreshService(dao.NewClientRefreshDAO(db), cronJobs.RefreshClient)
+	webhookService := service.NewWebhookService(dao.NewWebhookEventsDAO(db), journeyDAO, inboxDAO, refreshes)
+	inboxService := service.NewWebhookInboxService(inboxDAO, webhookService)
+	replayService := service.NewReplayService(dao.NewReplayDAO(db))
+
+	return &dependencies{
+		jobs: cronJobs,
+		controllers: routes.Controllers{
+			Homepage:  v1.NewHomepageController(service.NewHomepageService(plansDAO, pendingJourneyDAO)),
+			Token:     v1.NewTokenController(upswing),
+			Portfolio: v1.NewPortfolioController(upswing, portfolioService),
+			Plans:     v1.NewPlansController(service.NewPlansService(plansDAO)),
+			FAQ:       v1.NewFAQController(service.NewFAQService(dao.NewFAQDAO(db))),
+			Compare:   v1.NewCompareController(service.NewCompareService(dao.NewCompareDAO(db))),
+			Journey:   v1.NewJourneyController(service.NewJourneyService(journeyDAO)),
+			Jobs:      v1.NewJobsController(service.NewJobsService(jobLeaseDAO, jobRunDAO, cronJobs)),
+			Admin: v1.NewAdminController(webhookService, replayService, inboxService,
+				service.NewClientStateService(dao.NewClientStateDAO(db), journeyDAO, upswing), service.NewReconciliationService(reconciliationDAO),
+				service.NewJobConfigService(jobConfigDAO), service.NewInvalidClientService(invalidClientDAO)),
+			Webhooks: events.NewWebhooksController(webhookService),
+		},
+		inboxService:  inboxService,
+		refreshes:     refreshes,
+		replayService: replayService,
+	}, nil
+}
@@ -282,6 +282,139 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/journeys": {
+            "get": {
+                "description": "Lists the booking journeys of the user with their current state, latest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Journeys"
+                ],
+                "summary": "Get booking journeys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "authorization token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "unique request id",
+                        "name": "X-Request-Id",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BookingJourneys"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/journeys/{id}/timeline": {
+            "get": {
+                "description": "Returns the journey with every event received for it and the state reached after each event",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Journeys"
+                ],
+                "summary": "Get booking journey timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "authorization token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "unique request id",
+                        "name": "X-Request-Id",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "journey id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.JourneyTimeline"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/plans": {
             "get": {
                 "description": "Fetch all plan details across all banks/FSIs",
@@ -557,6 +690,43 @@ const docTemplate = `{
                 }
             }
         },
+        "model.BookingJourney": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "fsi": {
+                    "type": "string"
+                },
+                "journeyId": {
+                    "type": "string"
+                },
+                "lastEventAt": {
+                    "type": "string"
+                },
+                "lastEventType": {
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BookingJourneys": {
+            "type": "object",
+            "properties": {
+                "journeys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BookingJourney"
+                    }
+                }
+            }
+        },
         "model.CompareFSIDetails": {
             "type": "object",
             "properties": {
@@ -664,6 +834,7 @@ const docTemplate = `{
                     "type": "object",
                     "additionalProperties": true
                 },
+                "calculator": {},
                 "compareFsi": {
                     "type": "string"
                 },
@@ -716,11 +887,63 @@ const docTemplate = `{
         "model.Journey": {
             "type": "object",
             "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "blockingStep": {
+                    "type": "string"
+                },
+                "failureReason": {
+                    "type": "string"
+                },
+                "fsi": {
+                    "type": "string"
+                },
+                "journeyId": {
+                    "type": "string"
+                },
                 "pending": {
                     "type": "boolean"
                 },
                 "pendingState": {
                     "$ref": "#/definitions/model.PendingState"
+                },
+                "resumeLink": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.JourneyTimeline": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.JourneyTimelineEvent"
+                    }
+                },
+                "journey": {
+                    "$ref": "#/definitions/model.BookingJourney"
+                }
+            }
+        },
+        "model.JourneyTimelineEvent": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "at": {
+                    "type": "string"
+                },
+                "eventType": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
                 }
             }
         },
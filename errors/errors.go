@@ -18,4 +18,5 @@ var (
 	NotAuthorized            = goerr.New(nil, http.StatusUnauthorized, "user not authorized")
 	ErrClientData            = goerr.New(nil, http.StatusBadRequest, "error validating client")
 	ErrClientValidation      = goerr.New(nil, http.StatusBadRequest, "Client does not belong to the partner")
+	AdminAccessDenied        = goerr.New(nil, http.StatusForbidden, "user not allowed to access admin APIs")
 )
@@ -0,0 +1,34 @@
+package external
+
+import (
+	"context"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/commons/httpclient"
+	"github.com/angel-one/fd-core/constants"
+	"github.com/angel-one/fd-core/utils"
+	"github.com/angel-one/goerr"
+)
+
+// AlertHook posts alerts of the background jobs to the configured alerting endpoint
+type AlertHook interface {
+	Notify(ctx context.Context, alert model.Alert) error
+}
+
+type alertHookImpl struct {
+	httpClient httpclient.Client
+}
+
+func DefaultAlertHook(httpClient httpclient.Client) AlertHook {
+	return &alertHookImpl{httpClient: httpClient}
+}
+
+func (a *alertHookImpl) Notify(ctx context.Context, alert model.Alert) error {
+	configs := config.Current().HTTPClients[constants.AlertHookConfig]
+	err := utils.DoRequest(ctx, constants.AlertHookConfig, a.httpClient, utils.GetHeaders(configs), utils.GetBaseUrl(configs), nil, alert, nil)
+	if err != nil {
+		return goerr.New(err, "external failed : alert hook call failed")
+	}
+	return nil
+}
@@ -31,10 +31,14 @@ func DefaultProfileService(httpclient httpclient.Client) ProfileService {
 	if jwtToken == "" {
 		log.Fatal(c.Background("init")).Msgf("Profile service token is missing from configs")
 	}
-	return &profileServerImpl{httpClient: httpclient, jwtToken: jwtToken}
+	return NewProfileService(httpclient, jwtToken)
 }
 
-func (p *profileServerImpl) getHeaders(configs map[string]interface{}, appendToken bool) map[string]string {
+func NewProfileService(httpClient httpclient.Client, jwtToken string) ProfileService {
+	return &profileServerImpl{httpClient: httpClient, jwtToken: jwtToken}
+}
+
+func (p *profileServerImpl) getHeaders(configs config.HTTPClient, appendToken bool) map[string]string {
 	headers := utils.GetHeaders(configs)
 	if appendToken {
 		headers[constants.HeaderAuthorization] = constants.HeaderAuthorizationBearer + " " + p.jwtToken
@@ -44,7 +48,7 @@ func (p *profileServerImpl) getHeaders(configs map[string]interface{}, appendTok
 
 func (p *profileServerImpl) GetUserProfileDetails(ctx context.Context, clientCode string) (*model.ProfileResponse, error) {
 	response := model.ProfileResponse{}
-	configs, _ := config.Default().GetMap(constants.HTTPClientConfig, constants.ProfileServerConfig)
+	configs := config.Current().HTTPClients[constants.ProfileServerConfig]
 	queryParams := make(map[string]string)
 	queryParams[ClientCodeKey] = clientCode
 	err := utils.DoRequest(ctx, constants.ProfileServerConfig, p.httpClient, p.getHeaders(configs, true), utils.GetBaseUrl(configs), queryParams, nil, &response)
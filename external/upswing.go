@@ -2,6 +2,7 @@ package external
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 	"time"
 
@@ -39,20 +40,23 @@ type upSwingImpl struct {
 }
 
 func DefaultUpSwing(ctx context.Context) UpSwing {
-	secrets := config.Default().Secrets
+	return NewUpSwing(ctx, config.Default().Secrets, httpclient.Default(), DefaultProfileService(httpclient.Default()))
+}
 
+// NewUpSwing builds the client with the upswing credentials of the secrets and generates its first token
+func NewUpSwing(ctx context.Context, secrets map[string]string, httpClient httpclient.Client, profileService ProfileService) UpSwing {
 	tokenPayload := make(map[string]string)
 	tokenPayload[clientId] = secrets[constants.UpswingClientId]
 	tokenPayload[grantType] = secrets[constants.UpswingGrantType]
 	tokenPayload[clientSecret] = secrets[constants.UpswingClientSecret]
 	tokenPayload[scope] = secrets[constants.UpswingScope]
 
-	u := &upSwingImpl{tokenPayload: tokenPayload, httpClient: httpclient.Default(), profileService: DefaultProfileService(httpclient.Default())}
+	u := &upSwingImpl{tokenPayload: tokenPayload, httpClient: httpClient, profileService: profileService}
 	u.generateAccessToken(ctx)
 	return u
 }
 
-func (i *upSwingImpl) getHeaders(configs map[string]interface{}, appendToken bool) map[string]string {
+func (i *upSwingImpl) getHeaders(configs config.HTTPClient, appendToken bool) map[string]string {
 	headers := utils.GetHeaders(configs)
 	if appendToken {
 		headers[constants.HeaderAuthorization] = constants.HeaderAuthorizationBearer + " " + token
@@ -64,7 +68,7 @@ func (u *upSwingImpl) generateAccessToken(ctx context.Context) error {
 
 	// todo: token reuse ; validate its expiry and invoke only if its expired
 	response := model.GenerateTokenResponse{}
-	configs, _ := config.Default().GetMap(constants.HTTPClientConfig, constants.UpSwingGenerateToken)
+	configs := config.Current().HTTPClients[constants.UpSwingGenerateToken]
 	err := utils.DoEncodeRequest(ctx, constants.UpSwingGenerateToken, u.getHeaders(configs, false), utils.GetBaseUrl(configs), u.tokenPayload, &response)
 	if err != nil {
 		return goerr.New(err, "external failed : failed to generate upswing token")
@@ -80,7 +84,7 @@ func (u *upSwingImpl) DoPCIRegistration(ctx context.Context, clientCode string)
 
 	pciResponse := model.PCIRegistrationResponse{}
 	pciRequest := model.PCIRegistrationRequest{PartnerCustomerId: clientCode}
-	pciConfigs, _ := config.Default().GetMap(constants.HTTPClientConfig, constants.UpSwingPCIRegistration)
+	pciConfigs := config.Current().HTTPClients[constants.UpSwingPCIRegistration]
 	err := utils.DoRequest(ctx, constants.UpSwingPCIRegistration, u.httpClient, u.getHeaders(pciConfigs, true), utils.GetBaseUrl(pciConfigs), nil, pciRequest, &pciResponse)
 	if err != nil {
 		return nil, goerr.New(err, "external failed : failed to do upswing PCI registration")
@@ -103,7 +107,7 @@ func (u *upSwingImpl) DoPCIRegistration(ctx context.Context, clientCode string)
 }
 
 func (u *upSwingImpl) postDataIngestion(ctx context.Context, clientCode string, request model.DataIngestionRequest) error {
-	configs, _ := config.Default().GetMap(constants.HTTPClientConfig, constants.UpswingDataIngestion)
+	configs := config.Current().HTTPClients[constants.UpswingDataIngestion]
 	url := strings.Replace(utils.GetBaseUrl(configs), constants.UpPCIField, clientCode, -1)
 	err := utils.DoRequest(ctx, constants.UpswingDataIngestion, u.httpClient, u.getHeaders(configs, true), url, nil, request, nil)
 	if err != nil {
@@ -114,7 +118,7 @@ func (u *upSwingImpl) postDataIngestion(ctx context.Context, clientCode string,
 
 func (u *upSwingImpl) GetNetWorthData(ctx context.Context, clientCode string) (*model.NetWorthResponse, error) {
 	response := model.NetWorthResponse{}
-	configs, _ := config.Default().GetMap(constants.HTTPClientConfig, constants.UpSwingNetWorth)
+	configs := config.Current().HTTPClients[constants.UpSwingNetWorth]
 	url := strings.Replace(utils.GetBaseUrl(configs), constants.UpPCIField, clientCode, -1)
 	err := utils.DoRequest(ctx, constants.UpSwingNetWorth, u.httpClient, u.getHeaders(configs, true), url, nil, nil, &response)
 	if err != nil {
@@ -136,7 +140,7 @@ func (u *upSwingImpl) ValidateToken(ctx context.Context) error {
 
 func (u *upSwingImpl) GetPendingJourneyData(ctx context.Context, clientCode string) (*model.PendingJourneyResponse, error) {
 	response := model.PendingJourneyResponse{}
-	configs, _ := config.Default().GetMap(constants.HTTPClientConfig, constants.UpswingPendingJourney)
+	configs := config.Current().HTTPClients[constants.UpswingPendingJourney]
 	url := utils.GetBaseUrl(configs)
 
 	queryParams := make(map[string]string)
@@ -148,3 +152,18 @@ func (u *upSwingImpl) GetPendingJourneyData(ctx context.Context, clientCode stri
 	}
 	return &response, nil
 }
+
+// UpSwingErrorCode returns the errorCode of the upswing error response carried by err, the response body is one of its
+// stacks. Empty when err carries no upswing error response.
+func UpSwingErrorCode(err error) string {
+	for _, stack := range goerr.ListStacks(err) {
+		var errResp map[string]interface{}
+		if json.Unmarshal([]byte(stack), &errResp) != nil {
+			continue
+		}
+		if errorCode, _ := errResp[constants.ErrorCode].(string); errorCode != "" {
+			return errorCode
+		}
+	}
+	return ""
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/angel-one/fd-core/api/routes"
+	"github.com/angel-one/fd-core/app"
+	"github.com/angel-one/fd-core/business/jobs"
+	"github.com/angel-one/fd-core/business/workers"
+	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/commons/database"
+	"github.com/angel-one/fd-core/commons/flags"
+	"github.com/angel-one/fd-core/commons/httpclient"
+	"github.com/angel-one/fd-core/commons/log"
+	"github.com/angel-one/fd-core/constants"
+)
+
+// configHooks load the configs and set the logger up, every command starts with them
+func configHooks() []app.Hook {
+	return []app.Hook{
+		{Name: "config", Start: initConfig},
+		{Name: "logger", Start: initLogger},
+	}
+}
+
+func databaseHook() app.Hook {
+	return app.Hook{Name: "database", Start: func(ctx context.Context) error {
+		return database.Init(ctx, config.Default(), config.Current().Database)
+	}, Stop: func(ctx context.Context) error {
+		return database.CloseAll()
+	}}
+}
+
+// bootHooks set up what the services need: config, logger, database, http clients and the dependencies built on them
+func bootHooks() []app.Hook {
+	return append(configHooks(),
+		databaseHook(),
+		app.Hook{Name: "http clients", Start: initHTTPClients},
+		app.Hook{Name: "dependencies", Start: initDependencies},
+	)
+}
+
+// serviceHooks serve the api and run the cron jobs and workers. They stop in reverse, so in-flight requests are drained
+// before the workers and the running jobs are stopped at a batch boundary.
+func serviceHooks() []app.Hook {
+	server := &http.Server{Addr: fmt.Sprintf(":%d", flags.Port())}
+	return []app.Hook{
+		{Name: "config reload", Start: watchConfig, Stop: func(ctx context.Context) error {
+			return config.StopWatching(configNames)
+		}},
+		{Name: "cron jobs", Start: startJobs, Stop: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, seconds(config.App().Shutdown.JobsTimeoutSeconds))
+			defer cancel()
+			return jobs.StopJobs(ctx)
+		}},
+		{Name: "workers", Start: func(ctx context.Context) error {
+			workers.StartWebhookInboxWorkers(deps.inboxService)
+			workers.StartClientRefreshWorkers(deps.refreshes)
+			return nil
+		}, Stop: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, seconds(config.App().Shutdown.JobsTimeoutSeconds))
+			defer cancel()
+			return workers.StopWorkers(ctx)
+		}},
+		{Name: "api server", Start: func(ctx context.Context) error {
+			return startServer(ctx, server)
+		}, Stop: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, seconds(config.App().Shutdown.HTTPTimeoutSeconds))
+			defer cancel()
+			return server.Shutdown(ctx)
+		}},
+	}
+}
+
+var configNames = []string{constants.ApplicationConfig, constants.LoggerConfig, constants.DatabaseConfig, constants.HTTPClientConfig}
+
+var httpClientConfigKeys = []string{constants.UpSwingGenerateToken, constants.UpSwingPCIRegistration, constants.UpSwingNetWorth, constants.UpswingDataIngestion,
+	constants.ProfileServerConfig, constants.UpswingPendingJourney, constants.AlertHookConfig}
+
+// initConfig loads the configs and decodes them into the settings, every missing or invalid value is reported at once
+func initConfig(ctx context.Context) error {
+	var err error
+	if flags.Mode() == "test" {
+		err = config.InitTestMode(fmt.Sprintf("%s/%s", flags.BaseConfigPath(), flags.Env()), configNames...)
+	} else {
+		err = config.InitReleaseMode(configNames...)
+	}
+	if err != nil {
+		return fmt.Errorf("loading configs failed: %w", err)
+	}
+	if _, err := config.LoadSettings(httpClientConfigKeys); err != nil {
+		return fmt.Errorf("invalid config of environment %s:\n%w", flags.Env(), err)
+	}
+	log.Info(ctx).Msgf("starting with environment: '%s' ; mode: %s", flags.Env(), flags.Mode())
+	return nil
+}
+
+func initLogger(ctx context.Context) error {
+	log.InitLogger(log.Level(config.Current().Logger.Level))
+	return nil
+}
+
+// watchConfig applies the reloaded configs: the log level, upswing rate limit and job schedules here, the middlewares in
+// the router
+func watchConfig(ctx context.Context) error {
+	config.Subscribe(func(ctx context.Context, settings *config.Settings) {
+		log.SetLevel(log.Level(settings.Logger.Level))
+	})
+	config.Subscribe(func(ctx context.Context, settings *config.Settings) {
+		jobs.UpdateUpswingLimiter(settings.Application.Jobs)
+		if err := jobs.ReloadJobConfig(ctx); err != nil {
+			log.Error(ctx).Err(err).Msg("rescheduling jobs after a config change failed")
+		}
+	})
+	return config.WatchSettings(configNames, httpClientConfigKeys)
+}
+
+func initHTTPClients(ctx context.Context) error {
+	return httpclient.Init(ctx, config.Default(), constants.HTTPClientConfig, httpClientConfigKeys)
+}
+
+func initDependencies(ctx context.Context) error {
+	built, err := newDependencies(ctx, config.Default(), database.GetRouter(), httpclient.Default())
+	if err != nil {
+		return err
+	}
+	deps = built
+	return nil
+}
+
+// startServer listens before it returns, so a port that cannot be bound fails the startup. Serving stops with
+// Shutdown, an error of the listener after that is logged.
+func startServer(ctx context.Context, server *http.Server) error {
+	options, err := routes.NewRouterOptions(config.Default(), config.App())
+	if err != nil {
+		return err
+	}
+	server.Handler = routes.NewRouter(options, deps.controllers)
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		return fmt.Errorf("listening to port %d failed: %w", flags.Port(), err)
+	}
+	log.Info(ctx).Msgf("starting server and listening to port: %d", flags.Port())
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error(ctx).Err(err).Msg("server stopped serving")
+		}
+	}()
+	return nil
+}
+
+func startJobs(ctx context.Context) error {
+	if config.App().Jobs.Disabled {
+		log.Info(ctx).Msg("jobs are marked not to run , its state is disabled, skipping startin it")
+		return nil
+	}
+	deps.jobs.Start()
+	return nil
+}
+
+func seconds(value int64) time.Duration {
+	return time.Duration(value) * time.Second
+}
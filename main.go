@@ -1,19 +1,12 @@
 package main
 
 import (
-	"errors"
-	"fmt"
+	"os"
 
-	"github.com/angel-one/fd-core/api/routes"
-	"github.com/angel-one/fd-core/business/jobs"
-	"github.com/angel-one/fd-core/commons/config"
+	"github.com/angel-one/fd-core/app"
 	"github.com/angel-one/fd-core/commons/context"
-	"github.com/angel-one/fd-core/commons/database"
 	"github.com/angel-one/fd-core/commons/flags"
-	"github.com/angel-one/fd-core/commons/httpclient"
 	"github.com/angel-one/fd-core/commons/log"
-	"github.com/angel-one/fd-core/constants"
-	"github.com/angel-one/fd-core/factory"
 
 	_ "github.com/angel-one/fd-core/docs"
 )
@@ -29,93 +22,30 @@ import (
 
 var ctx = context.Background("boot")
 
-// custom hook - pre init
-func init() {
-	log.Info(ctx).Msg("bootstrapping fd-core service...")
-}
-
-// config initialization
-func init() {
-	var err error
-	configNames := []string{constants.ApplicationConfig, constants.LoggerConfig, constants.DatabaseConfig, constants.HTTPClientConfig}
-	if flags.Mode() == "test" {
-		err = config.InitTestMode(fmt.Sprintf("%s/%s", flags.BaseConfigPath(), flags.Env()), configNames...)
-	} else {
-		err = config.InitReleaseMode(configNames...)
-	}
-	if err != nil {
-		log.Fatal(ctx).Err(err).Stack().Msg("failed to initialize configs")
-	}
-	log.Info(ctx).Msg("configuration initialized")
-}
-
-func init() {
-	authKey := config.Default().Secrets[constants.JWTSymmetricKey]
-	if authKey == "" {
-		log.Fatal(ctx).Err(errors.New(constants.ErrAuthKeyEnvNotSet)).Msg(constants.ErrAuthKeyEnvNotSet)
-	}
-
-	log.Info(ctx).Msgf("starting with environment: '%s' ; mode: %s", flags.Env(), flags.Mode())
-}
-
-// logger initialization
-func init() {
-	loglevel, err := config.Default().GetString(constants.LoggerConfig, constants.LogLevelKey)
-	if err != nil {
-		log.Fatal(ctx).Err(err).Stack().Msg("failed to initialize logger")
-	}
-	log.InitLogger(log.Level(loglevel))
-	log.Info(ctx).Msg("logger initialized")
-}
-
-// performs db initialization
-func init() {
-	err := database.Init(ctx, config.Default(), constants.DatabaseConfig)
-	if err != nil {
-		log.Fatal(ctx).Err(err).Stack().Msg("failed to initialize database")
+// main starts the hooks of the command in order, runs it and stops the hooks in reverse. Without a command the api
+// is served until SIGINT or SIGTERM.
+func main() {
+	flags.Parse()
+	cmd, ok := commands()[flags.Command()]
+	if !ok {
+		printUsage()
+		os.Exit(2)
 	}
-	log.Info(ctx).Msg("database initialized")
-}
+	log.Info(ctx).Msgf("bootstrapping fd-core %s...", flags.Command())
 
-// perform http clients
-func init() {
-	clientConfigKeys := []string{constants.UpSwingGenerateToken, constants.UpSwingPCIRegistration, constants.UpSwingNetWorth, constants.UpswingDataIngestion, constants.ProfileServerConfig, constants.UpswingPendingJourney}
-	if err := httpclient.Init(ctx, config.Default(), constants.HTTPClientConfig, clientConfigKeys); err != nil {
-		log.Fatal(ctx).Err(err).Stack().Msg("failed to initialize http client")
+	application := app.New(cmd.hooks()...)
+	if err := application.Start(ctx); err != nil {
+		log.Fatal(ctx).Err(err).Stack().Msgf("fd-core %s startup failed", flags.Command())
 	}
+	log.Info(ctx).Msgf("fd-core %s initialized...", flags.Command())
 
-	log.Info(ctx).Msg("httpclient initalized")
-}
-
-// init factory
-func init() {
-	factory.Init(ctx)
-	log.Info(ctx).Msg("fd-core factory initialized...")
-}
-
-// start cron jobs
-func init() {
-	disabled := config.Default().GetBoolD(constants.ApplicationConfig, "jobsDisabled", false)
-	if disabled {
-		log.Info(ctx).Msg("jobs are marked not to run , its state is disabled, skipping startin it")
-	} else {
-		jobs.StartJobs()
-		log.Info(ctx).Msg("started all cron jobs")
+	err := cmd.run(ctx)
+	if stopErr := application.Stop(ctx); stopErr != nil {
+		log.Error(ctx).Err(stopErr).Msgf("fd-core %s did not stop cleanly", flags.Command())
 	}
-}
-
-// custom hook - post init
-func init() {
-	log.Info(ctx).Msg("fd-core service initialized...")
-}
-
-// main function
-func main() {
-	router := routes.DefaultRouter(ctx)
-	log.Info(ctx).Msgf("starting server and listening to port: %d", flags.Port())
-	err := router.Run(fmt.Sprintf(":%d", flags.Port()))
 	if err != nil {
-		log.Fatal(ctx).Err(err).Msg("server startup failed")
+		log.Error(ctx).Err(err).Msgf("fd-core %s failed", flags.Command())
+		os.Exit(1)
 	}
-	log.Info(ctx).Msg("api server started...")
+	log.Info(ctx).Msgf("fd-core %s stopped", flags.Command())
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/angel-one/fd-core/business/model"
+	"github.com/angel-one/fd-core/commons/flags"
+	"github.com/angel-one/fd-core/constants"
+)
+
+// runReplay rebuilds the derived state from webhook_events, usage:
+// fd-core replay [--client=S1614297] [--from=2024-06-01] [--to=2024-07-01] [--dry-run]
+func runReplay(ctx context.Context) error {
+	request := model.ReplayRequest{Provider: constants.UpSwingProvider, ClientCode: flags.ReplayClient(), DryRun: flags.ReplayDryRun()}
+
+	var err error
+	if request.From, err = parseReplayDate(flags.ReplayFrom()); err != nil {
+		return fmt.Errorf("invalid --from date: %w", err)
+	}
+	if request.To, err = parseReplayDate(flags.ReplayTo()); err != nil {
+		return fmt.Errorf("invalid --to date: %w", err)
+	}
+
+	result, err := deps.replayService.Replay(ctx, request)
+	if err != nil {
+		return fmt.Errorf("webhook replay failed: %w", err)
+	}
+
+	output, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Fprintln(os.Stdout, string(output))
+	return nil
+}
+
+func parseReplayDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		date, err = time.Parse(time.RFC3339, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
@@ -1,3 +1,4 @@
 #!/bin/zsh
 
-goose -allow-missing -dir ../dbmigrations postgres "postgres://$DATABASE_USERNAME:$DATABASE_PASSWORD@$DATABASE_URL:5432/$DATABASE_NAME?sslmode=disable" $*
+# runs the migrations of scripts/dbschema embedded in fd-core against the database of the configs, e.g. dbmig.sh status
+cd "$(dirname "$0")/../.." && go run . migrate $*
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE webhook_events
+            ADD COLUMN raw_payload jsonb NULL,
+            ADD COLUMN payload_version varchar(20) NULL;
+
+-- numeric(2) overflows for tenures like 0m1826d, widen to plain integers
+ALTER TABLE webhook_events
+            ALTER COLUMN tenure_months TYPE int4,
+            ALTER COLUMN tenure_days TYPE int4;
+
+CREATE INDEX webhook_events_index_vendor_version ON webhook_events (vendor, payload_version);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX webhook_events_index_vendor_version;
+
+ALTER TABLE webhook_events
+            ALTER COLUMN tenure_months TYPE numeric(2),
+            ALTER COLUMN tenure_days TYPE numeric(2);
+
+ALTER TABLE webhook_events
+            DROP COLUMN raw_payload,
+            DROP COLUMN payload_version;
+-- +goose StatementEnd
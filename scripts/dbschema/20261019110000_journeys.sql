@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS journeys (
+  id int8 NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+  tracking_id varchar(100) NOT NULL,
+  provider varchar(50) NOT NULL,
+  client_code varchar(20) NOT NULL,
+  state varchar(20) NOT NULL,
+  last_event_type varchar(50) NULL,
+  last_event_at timestamptz NULL,
+  institution varchar(100) NULL,
+  amount numeric(19, 4) NULL,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  created_by varchar(50) NOT NULL,
+  updated_by varchar(50) NOT NULL,
+  CONSTRAINT journeys_pkey PRIMARY KEY (id),
+  CONSTRAINT unique_provider_tracking_id UNIQUE (provider, tracking_id)
+);
+CREATE INDEX journeys_clientcode ON journeys (client_code, provider);
+CREATE INDEX journeys_updated ON journeys (updated_at);
+CREATE INDEX webhook_events_index_trackingid ON webhook_events (tracking_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX webhook_events_index_trackingid;
+DROP INDEX journeys_clientcode;
+DROP INDEX journeys_updated;
+drop table journeys;
+-- +goose StatementEnd
@@ -0,0 +1,108 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE pending_journey
+            ADD COLUMN tracking_id varchar(100) NULL,
+            ADD COLUMN blocking_event varchar(50) NULL,
+            ADD COLUMN failure_reason varchar(200) NULL,
+            ADD COLUMN institution varchar(100) NULL,
+            ADD COLUMN amount numeric(19, 4) NULL;
+
+
+CREATE OR REPLACE FUNCTION public.insert_pending_journey()
+ RETURNS trigger
+ LANGUAGE plpgsql
+AS $function$
+DECLARE
+    event_types TEXT[] := ARRAY['PAYMENT_FAILURE', 'AADHAAR_FAILED', 'VKYC_INITIATED', 'VKYC_FAILURE', 'VKYC_REQUIRED', 'VKYC_RETRY_REQUIRED', 'DIGILOCKER_FAILED', 'PAN_FAILURE'];
+BEGIN
+    -- Check if the new inserted row meets the conditions
+    IF NEW.client_code IS NOT NULL AND NEW.event_type = ANY(event_types) THEN
+        -- Check if the combination of client_code and vendor already exists in pending_journey
+        IF EXISTS (
+            SELECT 1 FROM pending_journey 
+            WHERE client_code = NEW.client_code AND provider = NEW.vendor
+        ) THEN
+            -- Update the existing row in pending_journey, fsi and amount are kept from earlier events of the same journey
+            UPDATE pending_journey
+            SET updated_at = CURRENT_TIMESTAMP,
+                updated_by = 'webhook_event',
+                to_be_refreshed = true,
+                blocking_event = NEW.event_type,
+                failure_reason = NEW.failure_reason,
+                institution = CASE WHEN tracking_id IS DISTINCT FROM NEW.tracking_id THEN NULLIF(NEW.institution, '') ELSE COALESCE(NULLIF(NEW.institution, ''), institution) END,
+                amount = CASE WHEN tracking_id IS DISTINCT FROM NEW.tracking_id THEN NULLIF(NEW.amount, 0) ELSE COALESCE(NULLIF(NEW.amount, 0), amount) END,
+                tracking_id = NEW.tracking_id
+            WHERE client_code = NEW.client_code AND provider = NEW.vendor;
+        ELSE
+            -- Insert the new row into pending_journey
+            INSERT INTO pending_journey (client_code, provider, pending, payment_pending, kyc_pending, created_by, updated_by, to_be_refreshed, tracking_id, blocking_event, failure_reason, institution, amount)
+            VALUES (NEW.client_code, NEW.vendor, FALSE, FALSE, FALSE, 'webhook_events', 'webhook_events', true, NEW.tracking_id, NEW.event_type, NEW.failure_reason, NULLIF(NEW.institution, ''), NULLIF(NEW.amount, 0));
+        END IF;
+    END IF;
+    RETURN NEW;
+END;
+$function$
+;
+DROP TRIGGER IF EXISTS insert_pending_journey_trigger ON webhook_events;
+create trigger insert_pending_journey_trigger after
+insert on public.webhook_events for each row execute function insert_pending_journey(); 
+
+-- backfill from the latest failure event of every client
+UPDATE pending_journey pj
+SET tracking_id = we.tracking_id,
+    blocking_event = we.event_type,
+    failure_reason = we.failure_reason,
+    institution = NULLIF(we.institution, ''),
+    amount = NULLIF(we.amount, 0)
+FROM (
+    SELECT DISTINCT ON (client_code, vendor) client_code, vendor, tracking_id, event_type, failure_reason, institution, amount
+    FROM webhook_events
+    WHERE event_type IN ('PAYMENT_FAILURE', 'AADHAAR_FAILED', 'VKYC_INITIATED', 'VKYC_FAILURE', 'VKYC_REQUIRED', 'VKYC_RETRY_REQUIRED', 'DIGILOCKER_FAILED', 'PAN_FAILURE')
+    ORDER BY client_code, vendor, id DESC
+) we
+WHERE pj.client_code = we.client_code AND pj.provider = we.vendor;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION public.insert_pending_journey()
+ RETURNS trigger
+ LANGUAGE plpgsql
+AS $function$
+DECLARE
+    event_types TEXT[] := ARRAY['PAYMENT_FAILURE', 'AADHAAR_FAILED', 'VKYC_INITIATED', 'VKYC_FAILURE', 'VKYC_REQUIRED', 'VKYC_RETRY_REQUIRED', 'DIGILOCKER_FAILED', 'PAN_FAILURE'];
+BEGIN
+    -- Check if the new inserted row meets the conditions
+    IF NEW.client_code IS NOT NULL AND NEW.event_type = ANY(event_types) THEN
+        -- Check if the combination of client_code and vendor already exists in pending_journey
+        IF EXISTS (
+            SELECT 1 FROM pending_journey 
+            WHERE client_code = NEW.client_code AND provider = NEW.vendor
+        ) THEN
+            -- Update the existing row in pending_journey
+            UPDATE pending_journey
+            SET updated_at = CURRENT_TIMESTAMP,
+                updated_by = 'webhook_event',
+                to_be_refreshed = true
+            WHERE client_code = NEW.client_code AND provider = NEW.vendor;
+        ELSE
+            -- Insert the new row into pending_journey
+            INSERT INTO pending_journey (client_code, provider, pending, payment_pending, kyc_pending, created_by, updated_by, to_be_refreshed)
+            VALUES (NEW.client_code, NEW.vendor, FALSE, FALSE, FALSE, 'webhook_events', 'webhook_events', true);
+        END IF;
+    END IF;
+    RETURN NEW;
+END;
+$function$
+;
+DROP TRIGGER IF EXISTS insert_pending_journey_trigger ON webhook_events;
+create trigger insert_pending_journey_trigger after
+insert on public.webhook_events for each row execute function insert_pending_journey(); 
+
+ALTER TABLE pending_journey
+            DROP COLUMN tracking_id,
+            DROP COLUMN blocking_event,
+            DROP COLUMN failure_reason,
+            DROP COLUMN institution,
+            DROP COLUMN amount;
+-- +goose StatementEnd
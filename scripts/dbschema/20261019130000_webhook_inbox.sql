@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS webhook_inbox (
+  id int8 NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+  vendor varchar(50) NOT NULL,
+  payload jsonb NOT NULL,
+  status varchar(20) NOT NULL DEFAULT 'pending',
+  attempts int4 NOT NULL DEFAULT 0,
+  next_attempt_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  locked_until timestamptz NULL,
+  last_error text NULL,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT webhook_inbox_pkey PRIMARY KEY (id)
+);
+CREATE INDEX webhook_inbox_index_status_next_attempt ON webhook_inbox (status, next_attempt_at);
+
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+  id int8 NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+  inbox_id int8 NOT NULL,
+  vendor varchar(50) NOT NULL,
+  payload jsonb NOT NULL,
+  attempts int4 NOT NULL,
+  last_error text NULL,
+  received_at timestamptz NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  requeued_at timestamptz NULL,
+  requeued_by varchar(50) NULL,
+  CONSTRAINT webhook_dead_letters_pkey PRIMARY KEY (id)
+);
+CREATE INDEX webhook_dead_letters_index_vendor_created ON webhook_dead_letters (vendor, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX webhook_dead_letters_index_vendor_created;
+drop table webhook_dead_letters;
+DROP INDEX webhook_inbox_index_status_next_attempt;
+drop table webhook_inbox;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- processed and dead lettered deliveries stay in the inbox (status done / dead) so redeliveries are detected
+ALTER TABLE webhook_inbox
+            ADD COLUMN dedup_key varchar(64) NULL;
+
+CREATE UNIQUE INDEX webhook_inbox_unique_vendor_dedup_key ON webhook_inbox (vendor, dedup_key);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX webhook_inbox_unique_vendor_dedup_key;
+
+ALTER TABLE webhook_inbox
+            DROP COLUMN dedup_key;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+  id int8 NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+  provider varchar(50) NOT NULL,
+  started_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  finished_at timestamptz NULL,
+  clients_compared int4 NOT NULL DEFAULT 0,
+  clients_failed int4 NOT NULL DEFAULT 0,
+  discrepancies int4 NOT NULL DEFAULT 0,
+  CONSTRAINT reconciliation_runs_pkey PRIMARY KEY (id)
+);
+CREATE INDEX reconciliation_runs_index_provider_started ON reconciliation_runs (provider, started_at);
+
+CREATE TABLE IF NOT EXISTS portfolio_discrepancies (
+  id int8 NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+  run_id int8 NOT NULL,
+  client_code varchar(50) NOT NULL,
+  provider varchar(50) NOT NULL,
+  severity varchar(20) NOT NULL,
+  db_active_deposits int4 NOT NULL,
+  provider_active_deposits int4 NOT NULL,
+  db_invested_value numeric(20, 2) NOT NULL,
+  provider_invested_value numeric(20, 2) NOT NULL,
+  db_current_value numeric(20, 2) NOT NULL,
+  provider_current_value numeric(20, 2) NOT NULL,
+  difference_percentage numeric(10, 2) NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT portfolio_discrepancies_pkey PRIMARY KEY (id),
+  CONSTRAINT portfolio_discrepancies_run_fkey FOREIGN KEY (run_id) REFERENCES reconciliation_runs (id) ON DELETE CASCADE
+);
+CREATE INDEX portfolio_discrepancies_index_run_severity ON portfolio_discrepancies (run_id, severity);
+CREATE INDEX portfolio_discrepancies_index_client ON portfolio_discrepancies (client_code, provider);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX portfolio_discrepancies_index_client;
+DROP INDEX portfolio_discrepancies_index_run_severity;
+drop table portfolio_discrepancies;
+DROP INDEX reconciliation_runs_index_provider_started;
+drop table reconciliation_runs;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- one row per cron job, the instance holding an unexpired lease is the only one running the job
+CREATE TABLE IF NOT EXISTS job_leases (
+  job_name varchar(100) NOT NULL,
+  holder varchar(255) NOT NULL,
+  acquired_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  renewed_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  expires_at timestamptz NOT NULL,
+  CONSTRAINT job_leases_pkey PRIMARY KEY (job_name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table job_leases;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS job_runs (
+  id int8 NOT NULL GENERATED BY DEFAULT AS IDENTITY,
+  job_name varchar(100) NOT NULL,
+  "trigger" varchar(20) NOT NULL,
+  refresher varchar(50) NULL,
+  instance varchar(255) NOT NULL,
+  status varchar(20) NOT NULL DEFAULT 'running',
+  started_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  finished_at timestamptz NULL,
+  clients_processed int4 NOT NULL DEFAULT 0,
+  successes int4 NOT NULL DEFAULT 0,
+  upstream_errors int4 NOT NULL DEFAULT 0,
+  invalid_clients int4 NOT NULL DEFAULT 0,
+  error text NULL,
+  CONSTRAINT job_runs_pkey PRIMARY KEY (id)
+);
+CREATE INDEX job_runs_index_job_started ON job_runs (job_name, started_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX job_runs_index_job_started;
+drop table job_runs;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- runtime settings of the cron jobs, null settings fall back to the application config
+CREATE TABLE IF NOT EXISTS job_config (
+  job_name varchar(100) NOT NULL,
+  enabled bool NOT NULL DEFAULT false,
+  schedule varchar(100) NULL,
+  batch_size int4 NULL,
+  provider varchar(50) NULL,
+  concurrency int4 NOT NULL DEFAULT 1,
+  updated_by varchar(50) NOT NULL,
+  updated_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT job_config_pkey PRIMARY KEY (job_name)
+);
+
+INSERT INTO job_config (job_name, enabled, updated_by) VALUES
+  ('tokenRenewalCron', true, 'migration'),
+  ('portfolioUpdateCron', true, 'migration'),
+  ('pendingJourneyUpdateCron', true, 'migration'),
+  ('portfolioReconciliationCron', true, 'migration')
+ON CONFLICT (job_name) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table job_config;
+-- +goose StatementEnd
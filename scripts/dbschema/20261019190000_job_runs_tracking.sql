@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE job_runs
+            ADD COLUMN clients_total int4 NOT NULL DEFAULT 0,
+            ADD COLUMN heartbeat_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+            ADD COLUMN cancel_requested bool NOT NULL DEFAULT false,
+            ADD COLUMN requested_by varchar(50) NULL,
+            ADD COLUMN exclusive bool NOT NULL DEFAULT true;
+
+-- runs recorded before heartbeats existed can not be told apart from dead ones
+UPDATE job_runs SET status = 'failed', finished_at = current_timestamp, error = 'abandoned' WHERE status = 'running';
+
+-- at most one running run of an exclusive job across all instances
+CREATE UNIQUE INDEX job_runs_index_running ON job_runs (job_name) WHERE status = 'running' AND exclusive;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX job_runs_index_running;
+
+ALTER TABLE job_runs
+            DROP COLUMN clients_total,
+            DROP COLUMN heartbeat_at,
+            DROP COLUMN cancel_requested,
+            DROP COLUMN requested_by,
+            DROP COLUMN exclusive;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- throughput and provider call latency of a run, set when it finishes
+ALTER TABLE job_runs
+            ADD COLUMN throughput_per_second numeric(12, 2) NULL,
+            ADD COLUMN latency_avg_ms numeric(12, 2) NULL,
+            ADD COLUMN latency_p95_ms numeric(12, 2) NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE job_runs
+            DROP COLUMN throughput_per_second,
+            DROP COLUMN latency_avg_ms,
+            DROP COLUMN latency_p95_ms;
+-- +goose StatementEnd
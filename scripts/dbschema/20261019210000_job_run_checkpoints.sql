@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- cursor is the last client code whose result is stored, a resume run continues after the cursor of its source run and
+-- a retryFailed run processes the failed clients of its source run
+ALTER TABLE job_runs
+            ADD COLUMN "cursor" varchar(20) NULL,
+            ADD COLUMN mode varchar(20) NOT NULL DEFAULT 'full',
+            ADD COLUMN source_run_id int8 NULL REFERENCES job_runs (id);
+
+-- clients whose upstream call failed in a run
+CREATE TABLE IF NOT EXISTS job_run_failures (
+  run_id int8 NOT NULL REFERENCES job_runs (id) ON DELETE CASCADE,
+  client_code varchar(20) NOT NULL,
+  api_error text NULL,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT job_run_failures_pkey PRIMARY KEY (run_id, client_code)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table job_run_failures;
+
+ALTER TABLE job_runs
+            DROP COLUMN "cursor",
+            DROP COLUMN mode,
+            DROP COLUMN source_run_id;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- invalid clients are checked with upswing again from next_revalidation_at, the wait doubles with every attempt that
+-- finds them still unknown. A null next_revalidation_at is due right away.
+ALTER TABLE portfolio
+            ADD COLUMN revalidation_attempts int4 NOT NULL DEFAULT 0,
+            ADD COLUMN next_revalidation_at timestamptz NULL;
+
+ALTER TABLE pending_journey
+            ADD COLUMN revalidation_attempts int4 NOT NULL DEFAULT 0,
+            ADD COLUMN next_revalidation_at timestamptz NULL;
+
+CREATE INDEX portfolio_index_revalidation ON portfolio (provider, next_revalidation_at) WHERE invalid_client;
+CREATE INDEX pending_journey_index_revalidation ON pending_journey (provider, next_revalidation_at) WHERE invalid_client;
+
+-- every change of the invalid flag by the revalidation job or an admin
+CREATE TABLE IF NOT EXISTS invalid_client_audit (
+  id bigserial NOT NULL,
+  client_code varchar(20) NOT NULL,
+  provider varchar(50) NOT NULL,
+  "scope" varchar(20) NOT NULL,
+  invalid bool NOT NULL,
+  reason text NOT NULL,
+  changed_by varchar(50) NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  CONSTRAINT invalid_client_audit_pkey PRIMARY KEY (id)
+);
+CREATE INDEX invalid_client_audit_index_client ON invalid_client_audit (client_code, provider);
+
+INSERT INTO job_config (job_name, enabled, updated_by) VALUES ('invalidClientRevalidationCron', true, 'migration')
+ON CONFLICT (job_name) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM job_config WHERE job_name = 'invalidClientRevalidationCron';
+
+drop table invalid_client_audit;
+
+DROP INDEX portfolio_index_revalidation;
+DROP INDEX pending_journey_index_revalidation;
+
+ALTER TABLE portfolio
+            DROP COLUMN revalidation_attempts,
+            DROP COLUMN next_revalidation_at;
+
+ALTER TABLE pending_journey
+            DROP COLUMN revalidation_attempts,
+            DROP COLUMN next_revalidation_at;
+-- +goose StatementEnd
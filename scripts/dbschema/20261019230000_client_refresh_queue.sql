@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- clients whose portfolio and pending journey are refreshed once due_at passes. Every webhook event of the client
+-- pushes due_at out, up to a max delay after its first event; events counts the events seen, so that an event arriving
+-- while the refresh runs keeps the row queued.
+CREATE TABLE IF NOT EXISTS client_refresh_queue (
+  client_code varchar(20) NOT NULL,
+  provider varchar(50) NOT NULL,
+  due_at timestamptz NOT NULL,
+  first_event_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  last_event_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  events int4 NOT NULL DEFAULT 1,
+  attempts int4 NOT NULL DEFAULT 0,
+  locked_until timestamptz NULL,
+  last_error text NULL,
+  CONSTRAINT client_refresh_queue_pkey PRIMARY KEY (client_code, provider)
+);
+CREATE INDEX client_refresh_queue_index_due ON client_refresh_queue (due_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table client_refresh_queue;
+-- +goose StatementEnd
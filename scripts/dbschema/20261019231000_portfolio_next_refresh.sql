@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the portfolio sweep refreshes a client once next_refresh_at is past, set from its activity after every refresh. A
+-- null next_refresh_at is due right away.
+ALTER TABLE portfolio ADD COLUMN next_refresh_at timestamptz NULL;
+
+CREATE INDEX portfolio_index_next_refresh ON portfolio (provider, next_refresh_at) WHERE NOT invalid_client;
+CREATE INDEX webhook_events_index_client_vendor ON webhook_events (client_code, vendor, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX webhook_events_index_client_vendor;
+DROP INDEX portfolio_index_next_refresh;
+
+ALTER TABLE portfolio DROP COLUMN next_refresh_at;
+-- +goose StatementEnd
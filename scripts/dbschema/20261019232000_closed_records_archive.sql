@@ -0,0 +1,84 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the sweeps close portfolios without active deposits and pending journeys no longer pending instead of deleting them,
+-- a row getting active again is reopened. Rows closed longer than the retention are moved to the archive tables.
+ALTER TABLE portfolio ADD COLUMN closed_at timestamptz NULL;
+ALTER TABLE pending_journey ADD COLUMN closed_at timestamptz NULL;
+
+CREATE INDEX portfolio_index_closed ON portfolio (closed_at) WHERE closed_at IS NOT NULL;
+CREATE INDEX pending_journey_index_closed ON pending_journey (closed_at) WHERE closed_at IS NOT NULL;
+
+CREATE OR REPLACE FUNCTION public.reopen_portfolio()
+ RETURNS trigger
+ LANGUAGE plpgsql
+AS $function$
+BEGIN
+    IF NEW.total_active_deposits > 0 THEN
+        NEW.closed_at = NULL;
+    END IF;
+    RETURN NEW;
+END;
+$function$
+;
+
+CREATE OR REPLACE FUNCTION public.reopen_pending_journey()
+ RETURNS trigger
+ LANGUAGE plpgsql
+AS $function$
+BEGIN
+    IF NEW.pending THEN
+        NEW.closed_at = NULL;
+    END IF;
+    RETURN NEW;
+END;
+$function$
+;
+
+create trigger trg_reopen_portfolio before update on public.portfolio for each row execute function reopen_portfolio();
+create trigger trg_reopen_pending_journey before update on public.pending_journey for each row execute function reopen_pending_journey();
+
+-- the archived row is kept whole as json, so the archive does not follow every column change of the live tables
+CREATE TABLE IF NOT EXISTS portfolio_archive (
+  id bigserial NOT NULL,
+  client_code varchar(20) NOT NULL,
+  provider varchar(50) NOT NULL,
+  closed_at timestamptz NOT NULL,
+  archived_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  record jsonb NOT NULL,
+  CONSTRAINT portfolio_archive_pkey PRIMARY KEY (id)
+);
+CREATE INDEX portfolio_archive_index_client ON portfolio_archive (client_code, provider);
+
+CREATE TABLE IF NOT EXISTS pending_journey_archive (
+  id bigserial NOT NULL,
+  client_code varchar(20) NOT NULL,
+  provider varchar(50) NOT NULL,
+  closed_at timestamptz NOT NULL,
+  archived_at timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  record jsonb NOT NULL,
+  CONSTRAINT pending_journey_archive_pkey PRIMARY KEY (id)
+);
+CREATE INDEX pending_journey_archive_index_client ON pending_journey_archive (client_code, provider);
+
+INSERT INTO job_config (job_name, enabled, updated_by) VALUES ('closedRecordArchivalCron', true, 'migration')
+ON CONFLICT (job_name) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM job_config WHERE job_name = 'closedRecordArchivalCron';
+
+drop table portfolio_archive;
+drop table pending_journey_archive;
+
+DROP TRIGGER IF EXISTS trg_reopen_portfolio ON portfolio;
+DROP TRIGGER IF EXISTS trg_reopen_pending_journey ON pending_journey;
+DROP FUNCTION IF EXISTS reopen_portfolio();
+DROP FUNCTION IF EXISTS reopen_pending_journey();
+
+DROP INDEX portfolio_index_closed;
+DROP INDEX pending_journey_index_closed;
+
+ALTER TABLE portfolio DROP COLUMN closed_at;
+ALTER TABLE pending_journey DROP COLUMN closed_at;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the inbox event an event was registered from, an inbox event processed again after its completion failed or its
+-- lease expired is not inserted twice, so the triggers do not add its amounts to the portfolio again
+ALTER TABLE webhook_events ADD COLUMN inbox_id int8 NULL;
+
+CREATE UNIQUE INDEX webhook_events_unique_inbox_id ON webhook_events (inbox_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX webhook_events_unique_inbox_id;
+
+ALTER TABLE webhook_events DROP COLUMN inbox_id;
+-- +goose StatementEnd
@@ -0,0 +1,8 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the scheduled token renewals are no longer recorded, their runs so far are dropped
+DELETE FROM job_runs WHERE job_name = 'tokenRenewalCron' AND "trigger" = 'cron';
+-- +goose StatementEnd
+
+-- +goose Down
+-- the dropped runs are not restored
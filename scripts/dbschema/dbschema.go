@@ -0,0 +1,13 @@
+// Package dbschema embeds the goose migrations of the database run by the migrate command, and the local data of the
+// seed command
+package dbschema
+
+import "embed"
+
+//go:embed *.sql
+var Migrations embed.FS
+
+// LocalSeed is the local development data inserted by the seed command
+//
+//go:embed seed/local.sql
+var LocalSeed string
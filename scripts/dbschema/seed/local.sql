@@ -0,0 +1,30 @@
+-- local data for development, run by the seed command after the migrations. Every statement is idempotent, so the
+-- seed can be run again. The banks are inserted by their migration.
+
+INSERT INTO plans (plan_id, fsi, plan_type, tenure_years, tenure_months, tenure_days, interest_rate, lockin_months, is_active, is_insured, is_mostbought, senior_citizen_benefit, women_benefit)
+SELECT seed.*
+FROM (VALUES
+    (1, 'STFCIN', 'cumulative', 1, 0, 0, 7.85::numeric, 3, true, false, true, 0.50::numeric, 0.10::numeric),
+    (2, 'STFCIN', 'cumulative', 3, 0, 0, 8.65::numeric, 3, true, false, false, 0.50::numeric, 0.10::numeric),
+    (3, 'BJFLIN', 'cumulative', 2, 0, 0, 8.10::numeric, 3, true, false, false, 0.40::numeric, 0.00::numeric),
+    (4, 'UTKSIN', 'cumulative', 1, 6, 0, 8.50::numeric, 0, true, true, true, 0.60::numeric, 0.00::numeric),
+    (5, 'SMCBIN', 'cumulative', 1, 0, 0, 8.35::numeric, 0, true, true, false, 0.50::numeric, 0.00::numeric)
+) AS seed (plan_id, fsi, plan_type, tenure_years, tenure_months, tenure_days, interest_rate, lockin_months, is_active, is_insured, is_mostbought, senior_citizen_benefit, women_benefit)
+WHERE NOT EXISTS (SELECT 1 FROM plans p WHERE p.plan_id = seed.plan_id);
+
+INSERT INTO faqs (tag, faq)
+VALUES
+    ('home', '[{"contentType":"text","question":"When will my portfolio get updated?","content":"It will take 4 to 5 business days after realisation of funds"},{"contentType":"text","question":"Are my deposits insured?","content":"Deposits of small finance banks are insured up to ₹5 Lakh by DICGC"}]'::json),
+    ('STFCIN', '[{"contentType":"text","question":"Can I withdraw early?","content":"Early withdrawal is allowed after the lock in period without penalty"}]'::json)
+ON CONFLICT (tag) DO NOTHING;
+
+INSERT INTO portfolio (client_code, provider, total_active_deposits, invested_value, current_value, interest_earned, returns_value, returns_percentage, created_by, updated_by)
+VALUES
+    ('S1614297', 'upswing', 2, 150000, 158420.50, 8420.50, 8420.50, 5.6137, 'seed', 'seed'),
+    ('S1614298', 'upswing', 0, 0, 0, 0, 0, 0, 'seed', 'seed')
+ON CONFLICT (client_code, provider) DO NOTHING;
+
+INSERT INTO pending_journey (client_code, provider, pending, payment_pending, kyc_pending, created_by, updated_by)
+VALUES
+    ('S1614298', 'upswing', true, true, false, 'seed', 'seed')
+ON CONFLICT (client_code, provider) DO NOTHING;
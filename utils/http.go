@@ -14,17 +14,21 @@ import (
 	fdcontext "github.com/angel-one/fd-core/commons/context"
 	"github.com/angel-one/fd-core/commons/httpclient"
 	"github.com/angel-one/fd-core/commons/log"
-	"github.com/angel-one/fd-core/constants"
 	utils2 "github.com/angel-one/go-utils"
 	"github.com/angel-one/goerr"
 )
 
-func GetBaseUrl(configs map[string]interface{}) string {
-	return config.Default().GetConfigEnv(configs, constants.UrlKey)
+func GetBaseUrl(settings config.HTTPClient) string {
+	return config.Default().ReplaceWithEnv(settings.URL)
 }
 
-func GetHeaders(configs map[string]interface{}) map[string]string {
-	return config.Default().GetConfigOptionMap(configs, "headers")
+// GetHeaders returns a copy of the configured headers, the caller is free to add its own
+func GetHeaders(settings config.HTTPClient) map[string]string {
+	headers := make(map[string]string, len(settings.Headers)+1)
+	for k, v := range settings.Headers {
+		headers[k] = v
+	}
+	return headers
 }
 
 func DoEncodeRequest(ctx context.Context, configKey string, headers map[string]string, httpUrl string, request map[string]string, response interface{}) error {